@@ -25,11 +25,15 @@ type App struct {
 	pythonRunner       *services.PythonRunner
 	rRunner            *services.RRunner
 	envService         *services.EnvironmentService
-	scriptExecutor     *services.ScriptExecutor
 	portableEnvService *services.PortableEnvService
 	pluginService      *services.PluginService
 	pluginLoaderV2     *services.PluginLoaderV2
 	pluginExecutor     *services.PluginExecutor
+	jobBundles         *services.JobBundleService
+	retention          *services.RetentionService
+	trash              *services.TrashService
+	batches            *services.BatchService
+	notifications      *services.NotificationService
 }
 
 func NewApp() *App {
@@ -81,39 +85,8 @@ func (a *App) startup(ctx context.Context) {
 	a.jobQueue = services.NewJobQueueService(ctx, db)
 	log.Println("[App.startup] Setting job queue runners...")
 	a.jobQueue.SetRunners(a.pythonRunner, a.rRunner, directRunner, a.settings)
-
-	log.Println("[App.startup] Initializing script executor...")
-	a.scriptExecutor = services.NewScriptExecutor(a.settings)
-	a.scriptExecutor.SetUpdateCallback(func(jobID string, update models.Job) {
-		job, err := a.jobQueue.GetJob(jobID)
-		if err != nil {
-			log.Printf("[App] Failed to get job %s: %v", jobID, err)
-			return
-		}
-
-		if update.Status != "" {
-			job.Status = update.Status
-		}
-		if update.Progress > 0 {
-			job.Progress = update.Progress
-		}
-		if update.Error != "" {
-			job.Error = update.Error
-		}
-		if update.OutputPath != "" {
-			job.OutputPath = update.OutputPath
-		}
-		if update.Status == "completed" {
-			now := time.Now()
-			job.CompletedAt = &now
-		}
-
-		if err := a.db.GetDB().Save(job).Error; err != nil {
-			log.Printf("[App] Failed to save job %s: %v", jobID, err)
-		}
-
-		runtime.EventsEmit(a.ctx, "job:update", job)
-	})
+	log.Println("[App.startup] Starting job queue workers...")
+	a.jobQueue.Start()
 
 	log.Println("[App.startup] Initializing plugin service...")
 	a.pluginService = services.NewPluginService()
@@ -126,8 +99,14 @@ func (a *App) startup(ctx context.Context) {
 	a.pluginExecutor = services.NewPluginExecutor()
 	log.Println("[App.startup] Plugin system V2 initialized")
 
-	log.Println("[App.startup] Checking for unfinished jobs...")
-	go a.checkUnfinishedJobs()
+	a.jobBundles = services.NewJobBundleService(a.jobQueue, a.pluginLoaderV2, a.envService, a.fileService, a.settings)
+	a.retention = services.NewRetentionService(a.jobQueue, a.settings)
+	a.trash = services.NewTrashService(a.jobQueue, a.settings)
+	a.batches = services.NewBatchService(a.jobQueue, a.pluginLoaderV2, a.pluginExecutor)
+	a.notifications = services.NewNotificationService(a.jobQueue, a.batches, a.settings)
+	if _, err := a.trash.PurgeExpired(); err != nil {
+		log.Printf("[App.startup] Failed to purge expired jobs from the trash: %v", err)
+	}
 
 	log.Println("[App.startup] Application startup complete!")
 }
@@ -146,7 +125,13 @@ func (a *App) GetSettings() *models.Config {
 }
 
 func (a *App) SetSetting(key string, value interface{}) error {
-	return a.settings.Set(key, value)
+	if err := a.settings.Set(key, value); err != nil {
+		return err
+	}
+	if strings.HasSuffix(key, "Workers") && a.jobQueue != nil {
+		a.jobQueue.ApplyWorkerSettings()
+	}
+	return nil
 }
 
 func (a *App) DetectPythonPath() (string, error) {
@@ -196,6 +181,31 @@ func (a *App) ReadJobOutputFile(jobID string, filename string) (string, error) {
 	return content, nil
 }
 
+func (a *App) GetJobArtifacts(jobID string) ([]models.JobArtifact, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.Artifacts().GetJobArtifacts(jobID)
+}
+
+// ExportJobBundle writes a reproducibility bundle for a finished job and
+// returns the path of the archive.
+func (a *App) ExportJobBundle(jobID string, destPath string) (string, error) {
+	if a.jobBundles == nil {
+		return "", fmt.Errorf("job queue not initialized")
+	}
+	return a.jobBundles.ExportJobBundle(jobID, destPath)
+}
+
+// ImportJobBundle recreates a job from a bundle and reports how this machine
+// differs from the one that produced it.
+func (a *App) ImportJobBundle(bundlePath string) (*models.JobBundleImport, error) {
+	if a.jobBundles == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobBundles.ImportJobBundle(bundlePath)
+}
+
 func (a *App) WriteJobOutputFile(jobID string, filename string, content string) error {
 	job, err := a.jobQueue.GetJob(jobID)
 	if err != nil {
@@ -319,7 +329,7 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			args = append(args, "--center_count", centerCount)
 		}
 
-		return a.jobQueue.CreateJob(req.Type, req.Name, "python", args)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "python", args, make(map[string]interface{}), req.Options)
 
 	case "alphastats":
 		if len(req.InputFiles) < 2 {
@@ -365,7 +375,7 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			args = append(args, "--comparison_matrix", compStr)
 		}
 
-		return a.jobQueue.CreateJob(req.Type, req.Name, "python", args)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "python", args, make(map[string]interface{}), req.Options)
 
 	case "limma":
 		if len(req.InputFiles) < 2 {
@@ -388,18 +398,14 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			args = append(args, "--comparison_file", "comparisons.csv")
 		}
 
-		return a.jobQueue.CreateJob(req.Type, req.Name, "python", args)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "python", args, make(map[string]interface{}), req.Options)
 
 	case "imputation":
 		if len(req.InputFiles) == 0 {
 			return "", fmt.Errorf("no input file provided for imputation")
 		}
 
-		cfg := a.settings.GetConfig()
-		baseOutputDir := cfg.OutputDirectory
-		if baseOutputDir == "" {
-			baseOutputDir = "outputs"
-		}
+		baseOutputDir := a.projectOutputDir()
 
 		runtime := getStringParam(req.Parameters, "runtime", "r")
 		jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("imputation_%s", time.Now().Format("20060102_150405")))
@@ -435,18 +441,14 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			"outputDir": jobOutputDir,
 		}
 
-		return a.jobQueue.CreateJobWithParameters(req.Type, req.Name, runtime, args, parameters)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, runtime, args, parameters, req.Options)
 
 	case "normalization":
 		if len(req.InputFiles) == 0 {
 			return "", fmt.Errorf("no input file provided for normalization")
 		}
 
-		cfg := a.settings.GetConfig()
-		baseOutputDir := cfg.OutputDirectory
-		if baseOutputDir == "" {
-			baseOutputDir = "outputs"
-		}
+		baseOutputDir := a.projectOutputDir()
 
 		runtime := getStringParam(req.Parameters, "runtime", "r")
 		jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("normalization_%s", time.Now().Format("20060102_150405")))
@@ -492,18 +494,14 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			"outputDir": jobOutputDir,
 		}
 
-		return a.jobQueue.CreateJobWithParameters(req.Type, req.Name, runtime, args, parameters)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, runtime, args, parameters, req.Options)
 
 	case "correlation-matrix":
 		if len(req.InputFiles) == 0 {
 			return "", fmt.Errorf("no input file provided for correlation matrix")
 		}
 
-		cfg := a.settings.GetConfig()
-		baseOutputDir := cfg.OutputDirectory
-		if baseOutputDir == "" {
-			baseOutputDir = "outputs"
-		}
+		baseOutputDir := a.projectOutputDir()
 
 		jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("correlation_matrix_%s", time.Now().Format("20060102_150405")))
 		os.MkdirAll(jobOutputDir, 0755)
@@ -577,18 +575,14 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			"inputFiles": req.InputFiles,
 		}
 
-		return a.jobQueue.CreateJobWithParameters(req.Type, req.Name, "r", args, parameters)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "r", args, parameters, req.Options)
 
 	case "maxlfq":
 		if len(req.InputFiles) == 0 {
 			return "", fmt.Errorf("no input file provided for MaxLFQ normalization")
 		}
 
-		cfg := a.settings.GetConfig()
-		baseOutputDir := cfg.OutputDirectory
-		if baseOutputDir == "" {
-			baseOutputDir = "outputs"
-		}
+		baseOutputDir := a.projectOutputDir()
 
 		jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("maxlfq_%s", time.Now().Format("20060102_150405")))
 		os.MkdirAll(jobOutputDir, 0755)
@@ -618,18 +612,14 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			"outputDir": jobOutputDir,
 		}
 
-		return a.jobQueue.CreateJobWithParameters(req.Type, req.Name, "r", args, parameters)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "r", args, parameters, req.Options)
 
 	case "batch-correction":
 		if len(req.InputFiles) == 0 {
 			return "", fmt.Errorf("no input file provided for batch correction")
 		}
 
-		cfg := a.settings.GetConfig()
-		baseOutputDir := cfg.OutputDirectory
-		if baseOutputDir == "" {
-			baseOutputDir = "outputs"
-		}
+		baseOutputDir := a.projectOutputDir()
 
 		jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("batch_correction_%s", time.Now().Format("20060102_150405")))
 		os.MkdirAll(jobOutputDir, 0755)
@@ -656,18 +646,14 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			"outputDir": jobOutputDir,
 		}
 
-		return a.jobQueue.CreateJobWithParameters(req.Type, req.Name, "r", args, parameters)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "r", args, parameters, req.Options)
 
 	case "venn-diagram":
 		if len(req.InputFiles) == 0 {
 			return "", fmt.Errorf("no input file provided for venn diagram")
 		}
 
-		cfg := a.settings.GetConfig()
-		baseOutputDir := cfg.OutputDirectory
-		if baseOutputDir == "" {
-			baseOutputDir = "outputs"
-		}
+		baseOutputDir := a.projectOutputDir()
 
 		jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("venn_diagram_%s", time.Now().Format("20060102_150405")))
 		os.MkdirAll(jobOutputDir, 0755)
@@ -699,7 +685,7 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			"outputDir": jobOutputDir,
 		}
 
-		return a.jobQueue.CreateJobWithParameters(req.Type, req.Name, "r", args, parameters)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "r", args, parameters, req.Options)
 
 	case "estimation-plot":
 		if len(req.InputFiles) < 2 {
@@ -725,7 +711,7 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			args = append(args, "--condition_order", order)
 		}
 
-		return a.jobQueue.CreateJob(req.Type, req.Name, "python", args)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "python", args, make(map[string]interface{}), req.Options)
 
 	case "cv-plot":
 		args := []string{"cv.py"}
@@ -749,7 +735,7 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			args = append(args, "--sample_names", samples)
 		}
 
-		return a.jobQueue.CreateJob(req.Type, req.Name, "python", args)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "python", args, make(map[string]interface{}), req.Options)
 
 	case "fold-change-violin":
 		if len(req.InputFiles) == 0 {
@@ -783,7 +769,7 @@ func (a *App) CreateJob(req models.JobRequest) (string, error) {
 			args = append(args, "--figsize", figsize)
 		}
 
-		return a.jobQueue.CreateJob(req.Type, req.Name, "python", args)
+		return a.jobQueue.CreateJobWithOptions(req.Type, req.Name, "python", args, make(map[string]interface{}), req.Options)
 
 	case "qfeatures_limma":
 		return "", fmt.Errorf("QFeatures + Limma analysis is not yet implemented - backend script pending")
@@ -797,16 +783,248 @@ func (a *App) GetJob(id string) (*models.Job, error) {
 	return a.jobQueue.GetJob(id)
 }
 
+func (a *App) GetJobLog(jobID string, offset int, limit int, stream string) (*models.JobLogPage, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.Logs().GetJobLog(jobID, offset, limit, stream)
+}
+
 func (a *App) GetAllJobs() []*models.Job {
 	return a.jobQueue.GetAllJobs()
 }
 
+func (a *App) QueryJobs(query models.JobQuery) (*models.JobQueryPage, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.QueryJobs(query)
+}
+
+// SearchJobText searches job names, parameters, input paths, errors and logs
+// of the active project, or of every project when allProjects is set.
+func (a *App) SearchJobText(text string, allProjects bool, limit int) ([]models.JobSearchResult, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	projectID := ""
+	if !allProjects {
+		projectID = a.jobQueue.Projects().ActiveProjectID()
+	}
+	return a.jobQueue.Search().Search(text, projectID, limit)
+}
+
+func (a *App) SetJobTags(jobID string, tags []string) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.SetJobTags(jobID, tags)
+}
+
+// GetPluginRuntimeHistory returns the duration, CPU time and memory use of
+// the most recent finished runs of a plugin.
+func (a *App) GetPluginRuntimeHistory(pluginID string, limit int) (*models.PluginRuntimeHistory, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.PluginRuntimeHistory(pluginID, limit)
+}
+
+// GetInterruptedJobs returns the jobs a previous session left running that
+// wait for a recovery decision, with whether their process is still alive.
+func (a *App) GetInterruptedJobs() ([]models.InterruptedJob, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.GetInterruptedJobs()
+}
+
+// RecoverJob re-attaches to, restarts or fails a job interrupted by a
+// previous session.
+func (a *App) RecoverJob(id string, action string) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.RecoverJob(id, action)
+}
+
+// PreviewCleanup lists the jobs the saved retention policy would delete.
+func (a *App) PreviewCleanup() (*models.CleanupPlan, error) {
+	if a.retention == nil {
+		return nil, fmt.Errorf("retention service not initialized")
+	}
+	return a.retention.PreviewCleanup(a.settings.RetentionPolicy())
+}
+
+// RunCleanup permanently deletes the listed jobs of the current cleanup
+// plan, or all of them when jobIDs is empty, together with their output
+// directories.
+func (a *App) RunCleanup(jobIDs []string) (*models.CleanupSummary, error) {
+	if a.retention == nil {
+		return nil, fmt.Errorf("retention service not initialized")
+	}
+	return a.retention.RunCleanup(a.settings.RetentionPolicy(), jobIDs)
+}
+
+// DeleteJob moves a job and its output directory to the trash.
 func (a *App) DeleteJob(id string) error {
-	return a.jobQueue.DeleteJob(id)
+	if a.trash == nil {
+		return fmt.Errorf("trash service not initialized")
+	}
+	return a.trash.TrashJob(id)
+}
+
+func (a *App) ListTrash() ([]models.Job, error) {
+	if a.trash == nil {
+		return nil, fmt.Errorf("trash service not initialized")
+	}
+	return a.trash.ListTrash()
+}
+
+// RestoreJob takes a job out of the trash, moving its output directory back
+// when restoreOutput is set and discarding it otherwise.
+func (a *App) RestoreJob(id string, restoreOutput bool) (*models.Job, error) {
+	if a.trash == nil {
+		return nil, fmt.Errorf("trash service not initialized")
+	}
+	return a.trash.RestoreJob(id, restoreOutput)
+}
+
+func (a *App) PurgeJob(id string) error {
+	if a.trash == nil {
+		return fmt.Errorf("trash service not initialized")
+	}
+	return a.trash.PurgeJob(id)
+}
+
+func (a *App) EmptyTrash() (int, error) {
+	if a.trash == nil {
+		return 0, fmt.Errorf("trash service not initialized")
+	}
+	return a.trash.EmptyTrash()
+}
+
+func (a *App) ListProjects() ([]models.ProjectSummary, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.Projects().ListProjects()
+}
+
+func (a *App) GetActiveProject() (*models.Project, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.Projects().GetActiveProject()
+}
+
+// CreateProject adds a project whose outputs go to outputDirectory, or to a
+// folder named after it inside the configured output directory.
+func (a *App) CreateProject(name string, description string, outputDirectory string) (*models.Project, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	if outputDirectory == "" {
+		outputDirectory = services.ProjectOutputDirectory(a.configuredOutputDir(), name)
+	}
+	return a.jobQueue.Projects().CreateProject(name, description, outputDirectory)
+}
+
+func (a *App) SetActiveProject(id string) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.Projects().SetActiveProject(id)
+}
+
+func (a *App) DeleteProject(id string) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.Projects().DeleteProject(id)
+}
+
+// GetSampleAnnotations returns the sample annotations of the active project.
+func (a *App) GetSampleAnnotations() ([]models.SampleAnnotation, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	projects := a.jobQueue.Projects()
+	return projects.GetSampleAnnotations(projects.ActiveProjectID())
+}
+
+// SaveSampleAnnotations adds annotations to the active project, replacing
+// those of samples it already annotates.
+func (a *App) SaveSampleAnnotations(annotations []models.SampleAnnotation) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	projects := a.jobQueue.Projects()
+	return projects.SaveSampleAnnotations(projects.ActiveProjectID(), annotations)
+}
+
+func (a *App) DeleteSampleAnnotations(samples []string) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	projects := a.jobQueue.Projects()
+	return projects.DeleteSampleAnnotations(projects.ActiveProjectID(), samples)
+}
+
+func (a *App) ExportProject(id string, destPath string) (string, error) {
+	if a.jobBundles == nil {
+		return "", fmt.Errorf("job queue not initialized")
+	}
+	return a.jobBundles.ExportProject(id, destPath)
+}
+
+func (a *App) ImportProject(archivePath string) (*models.ProjectImport, error) {
+	if a.jobBundles == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobBundles.ImportProject(archivePath)
+}
+
+func (a *App) configuredOutputDir() string {
+	if dir := a.settings.GetConfig().OutputDirectory; dir != "" {
+		return dir
+	}
+	return "outputs"
+}
+
+// projectOutputDir returns the directory new job outputs go to: the active
+// project's output directory, or the configured one.
+func (a *App) projectOutputDir() string {
+	base := a.configuredOutputDir()
+	if a.jobQueue == nil {
+		return base
+	}
+	projects := a.jobQueue.Projects()
+	return projects.OutputDirectory(projects.ActiveProjectID(), base)
+}
+
+func (a *App) CancelJob(id string) error {
+	return a.jobQueue.CancelJob(id)
+}
+
+func (a *App) RerunJob(jobID string, useSameEnvironment bool, pythonEnvPath string, rEnvPath string, fromSnapshot bool) (string, error) {
+	return a.jobQueue.RerunJob(jobID, useSameEnvironment, pythonEnvPath, rEnvPath, fromSnapshot)
+}
+
+// VerifyJobInputs reports which inputs of a job, and of its snapshot, changed
+// since the job was submitted.
+func (a *App) VerifyJobInputs(jobID string) ([]models.JobInputCheck, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.VerifyJobInputs(jobID)
 }
 
-func (a *App) RerunJob(jobID string, useSameEnvironment bool, pythonEnvPath string, rEnvPath string) (string, error) {
-	return a.jobQueue.RerunJob(jobID, useSameEnvironment, pythonEnvPath, rEnvPath)
+func (a *App) CompareJobs(ids []string) (*models.JobComparison, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.jobQueue.CompareJobs(ids)
 }
 
 func (a *App) ReExecuteJob(id string) (string, error) {
@@ -847,11 +1065,7 @@ func (a *App) ReExecuteJob(id string) (string, error) {
 		inputFiles, _ := job.Parameters["inputFiles"].([]interface{})
 		inputFile := inputFiles[0].(string)
 
-		cfg := a.settings.GetConfig()
-		baseOutputDir := cfg.OutputDirectory
-		if baseOutputDir == "" {
-			baseOutputDir = "outputs"
-		}
+		baseOutputDir := a.projectOutputDir()
 
 		jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("correlation_matrix_%s", time.Now().Format("20060102_150405")))
 		os.MkdirAll(jobOutputDir, 0755)
@@ -926,7 +1140,7 @@ func (a *App) ReExecuteJob(id string) (string, error) {
 		}
 		rerunParameters["outputDir"] = jobOutputDir
 
-		return a.jobQueue.CreateJobWithParameters(job.Type, job.Name, "r", args, rerunParameters)
+		return a.jobQueue.CreateJobWithOptions(job.Type, job.Name, "r", args, rerunParameters, models.JobOptions{Limits: job.Limits})
 
 	default:
 		return "", fmt.Errorf("unsupported job type for re-execution: %s", job.Type)
@@ -935,7 +1149,7 @@ func (a *App) ReExecuteJob(id string) (string, error) {
 
 func (a *App) ExecutePythonScript(scriptName string, args []string) (string, error) {
 	var output string
-	err := a.pythonRunner.ExecuteScript(scriptName, args, func(line string) {
+	err := a.pythonRunner.ExecuteScript(a.ctx, scriptName, args, models.ExecutionLimits{}, func(stream string, line string) {
 		output += line + "\n"
 		runtime.EventsEmit(a.ctx, "script:output", line)
 	})
@@ -944,7 +1158,7 @@ func (a *App) ExecutePythonScript(scriptName string, args []string) (string, err
 
 func (a *App) ExecuteRScript(scriptName string, args []string) (string, error) {
 	var output string
-	err := a.rRunner.ExecuteScript(scriptName, args, func(line string) {
+	err := a.rRunner.ExecuteScript(a.ctx, scriptName, args, models.ExecutionLimits{}, func(stream string, line string) {
 		output += line + "\n"
 		runtime.EventsEmit(a.ctx, "script:output", line)
 	})
@@ -1044,8 +1258,10 @@ func (a *App) ParseDataFile(path string, previewRows int) (*services.DataFilePre
 }
 
 func (a *App) ImportDataFile(path string) (uint, error) {
+	projectID := a.jobQueue.Projects().ActiveProjectID()
+
 	var existingFile services.ImportedFile
-	err := a.db.GetDB().Where("path = ?", path).First(&existingFile).Error
+	err := a.db.GetDB().Where("path = ? AND project_id = ?", path, projectID).First(&existingFile).Error
 	if err == nil {
 		return existingFile.ID, nil
 	}
@@ -1061,6 +1277,7 @@ func (a *App) ImportDataFile(path string) (uint, error) {
 	}
 
 	importedFile := &services.ImportedFile{
+		ProjectID:  projectID,
 		Name:       info.Name,
 		Path:       path,
 		Size:       info.Size,
@@ -1078,10 +1295,12 @@ func (a *App) ImportDataFile(path string) (uint, error) {
 	return importedFile.ID, nil
 }
 
+// GetImportedFiles returns the files imported into the active project.
 func (a *App) GetImportedFiles() ([]services.ImportedFile, error) {
-	var files []services.ImportedFile
-	err := a.db.GetDB().Order("imported_at DESC").Limit(10).Find(&files).Error
-	return files, err
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.db.GetImportedFiles(a.jobQueue.Projects().ActiveProjectID())
 }
 
 func (a *App) DeleteImportedFile(id uint) error {
@@ -1095,11 +1314,7 @@ func (a *App) RunPCAAnalysis(inputFile string, outputDir string, columns []strin
 	log.Printf("[RunPCAAnalysis] Columns: %v", columns)
 	log.Printf("[RunPCAAnalysis] nComponents: %d, useLog2: %v", nComponents, useLog2)
 
-	cfg := a.settings.GetConfig()
-	baseOutputDir := cfg.OutputDirectory
-	if baseOutputDir == "" {
-		baseOutputDir = "outputs"
-	}
+	baseOutputDir := a.projectOutputDir()
 
 	jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("pca_analysis_%s", time.Now().Format("20060102_150405")))
 	os.MkdirAll(jobOutputDir, 0755)
@@ -1140,18 +1355,6 @@ func (a *App) RunPCAAnalysis(inputFile string, outputDir string, columns []strin
 	}
 	log.Printf("[RunPCAAnalysis] Job created with ID: %s", jobID)
 
-	go func() {
-		err := a.scriptExecutor.ExecutePythonScript(a.ctx, jobID, services.ScriptConfig{
-			Type:       "pca",
-			ScriptName: "pca.py",
-			Args:       args[1:],
-			OutputDir:  jobOutputDir,
-		})
-		if err != nil {
-			log.Printf("[RunPCAAnalysis] Error: %v", err)
-		}
-	}()
-
 	return jobID, nil
 }
 
@@ -1162,11 +1365,7 @@ func (a *App) RunPHATEAnalysis(inputFile string, outputDir string, columns []str
 	log.Printf("[RunPHATEAnalysis] Columns: %v", columns)
 	log.Printf("[RunPHATEAnalysis] nComponents: %d, useLog2: %v", nComponents, useLog2)
 
-	cfg := a.settings.GetConfig()
-	baseOutputDir := cfg.OutputDirectory
-	if baseOutputDir == "" {
-		baseOutputDir = "outputs"
-	}
+	baseOutputDir := a.projectOutputDir()
 
 	jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("phate_analysis_%s", time.Now().Format("20060102_150405")))
 	os.MkdirAll(jobOutputDir, 0755)
@@ -1207,27 +1406,11 @@ func (a *App) RunPHATEAnalysis(inputFile string, outputDir string, columns []str
 	}
 	log.Printf("[RunPHATEAnalysis] Job created with ID: %s", jobID)
 
-	go func() {
-		err := a.scriptExecutor.ExecutePythonScript(a.ctx, jobID, services.ScriptConfig{
-			Type:       "phate",
-			ScriptName: "phate_analysis.py",
-			Args:       args[1:],
-			OutputDir:  jobOutputDir,
-		})
-		if err != nil {
-			log.Printf("[RunPHATEAnalysis] Error: %v", err)
-		}
-	}()
-
 	return jobID, nil
 }
 
 func (a *App) RunNormalization(inputFile string, outputDir string, columns []string, scalerType string) (string, error) {
-	cfg := a.settings.GetConfig()
-	baseOutputDir := cfg.OutputDirectory
-	if baseOutputDir == "" {
-		baseOutputDir = "outputs"
-	}
+	baseOutputDir := a.projectOutputDir()
 
 	jobOutputDir := filepath.Join(baseOutputDir, fmt.Sprintf("normalization_%s", time.Now().Format("20060102_150405")))
 	os.MkdirAll(jobOutputDir, 0755)
@@ -1260,18 +1443,6 @@ func (a *App) RunNormalization(inputFile string, outputDir string, columns []str
 		return "", err
 	}
 
-	go func() {
-		err := a.scriptExecutor.ExecutePythonScript(a.ctx, jobID, services.ScriptConfig{
-			Type:       "normalization",
-			ScriptName: "normalization.py",
-			Args:       args[1:],
-			OutputDir:  jobOutputDir,
-		})
-		if err != nil {
-			log.Printf("[RunNormalization] Error: %v", err)
-		}
-	}()
-
 	return jobID, nil
 }
 
@@ -1330,12 +1501,18 @@ func (a *App) ExecutePlugin(req models.PluginExecutionRequest) (string, error) {
 		args = append(args, fmt.Sprintf("--%s", input.Name), fmt.Sprintf("%v", value))
 	}
 
-	cfg := a.settings.GetConfig()
-	baseOutputDir := cfg.OutputDirectory
-	if baseOutputDir == "" {
-		baseOutputDir = "outputs"
+	switch plugin.Config.Runtime {
+	case models.PluginRuntimePython, models.PluginRuntimeR:
+	case models.PluginRuntimePythonWithR:
+		if cfg := a.settings.GetConfig(); cfg.RPath != "" {
+			args = append(args, "--r_home", cfg.RPath)
+		}
+	default:
+		return "", fmt.Errorf("unsupported runtime: %s", plugin.Config.Runtime)
 	}
 
+	baseOutputDir := a.projectOutputDir()
+
 	outputDir := filepath.Join(baseOutputDir, fmt.Sprintf("plugin_%s_%s", plugin.ID, time.Now().Format("20060102_150405")))
 	os.MkdirAll(outputDir, 0755)
 
@@ -1349,72 +1526,7 @@ func (a *App) ExecutePlugin(req models.PluginExecutionRequest) (string, error) {
 
 	jobName := fmt.Sprintf("Plugin: %s", plugin.Config.Name)
 
-	var jobID string
-	switch plugin.Config.Runtime {
-	case models.PluginRuntimePython:
-		jobID, err = a.jobQueue.CreateJobWithParameters("plugin", jobName, "python", args, parameters)
-		if err != nil {
-			return "", err
-		}
-
-		go func() {
-			err := a.scriptExecutor.ExecutePythonScript(a.ctx, jobID, services.ScriptConfig{
-				Type:       "plugin",
-				ScriptName: plugin.ScriptPath,
-				Args:       args[1:],
-				OutputDir:  outputDir,
-			})
-			if err != nil {
-				log.Printf("[ExecutePlugin] Error: %v", err)
-			}
-		}()
-
-	case models.PluginRuntimeR:
-		jobID, err = a.jobQueue.CreateJobWithParameters("plugin", jobName, "r", args, parameters)
-		if err != nil {
-			return "", err
-		}
-
-		go func() {
-			err := a.scriptExecutor.ExecuteRScript(a.ctx, jobID, services.ScriptConfig{
-				Type:       "plugin",
-				ScriptName: plugin.ScriptPath,
-				Args:       args[1:],
-				OutputDir:  outputDir,
-			})
-			if err != nil {
-				log.Printf("[ExecutePlugin] Error: %v", err)
-			}
-		}()
-
-	case models.PluginRuntimePythonWithR:
-		jobID, err = a.jobQueue.CreateJobWithParameters("plugin", jobName, "python", args, parameters)
-		if err != nil {
-			return "", err
-		}
-
-		cfg := a.settings.GetConfig()
-		if cfg.RPath != "" {
-			args = append(args, "--r_home", cfg.RPath)
-		}
-
-		go func() {
-			err := a.scriptExecutor.ExecutePythonScript(a.ctx, jobID, services.ScriptConfig{
-				Type:       "plugin",
-				ScriptName: plugin.ScriptPath,
-				Args:       args[1:],
-				OutputDir:  outputDir,
-			})
-			if err != nil {
-				log.Printf("[ExecutePlugin] Error: %v", err)
-			}
-		}()
-
-	default:
-		return "", fmt.Errorf("unsupported runtime: %s", plugin.Config.Runtime)
-	}
-
-	return jobID, nil
+	return a.jobQueue.CreateJobWithOptions("plugin", jobName, string(plugin.Config.Runtime), args, parameters, req.Options)
 }
 
 func (a *App) GetPluginsV2() []*models.PluginV2 {
@@ -1431,73 +1543,53 @@ func (a *App) ExecutePluginV2(req models.PluginExecutionRequestV2) (string, erro
 		return "", err
 	}
 
-	if err := a.pluginExecutor.ValidateParameters(plugin, req.Parameters); err != nil {
-		return "", fmt.Errorf("parameter validation failed: %w", err)
-	}
-
-	args, err := a.pluginExecutor.BuildArguments(plugin, req.Parameters)
-	if err != nil {
-		return "", fmt.Errorf("failed to build arguments: %w", err)
-	}
-
-	cfg := a.settings.GetConfig()
-	baseOutputDir := cfg.OutputDirectory
-	if baseOutputDir == "" {
-		baseOutputDir = "outputs"
-	}
+	baseOutputDir := a.projectOutputDir()
 
 	outputDir := filepath.Join(baseOutputDir, fmt.Sprintf("%s_%s",
 		plugin.Definition.Plugin.ID,
 		time.Now().Format("20060102_150405")))
-	os.MkdirAll(outputDir, 0755)
 
-	if plugin.Definition.Execution.OutputDir != "" {
-		args = append(args, plugin.Definition.Execution.OutputDir, outputDir)
+	prepared, err := a.pluginExecutor.PrepareJob(plugin, req.Parameters, req.Options, outputDir)
+	if err != nil {
+		return "", err
 	}
 
-	parameters := make(map[string]interface{})
-	for k, v := range req.Parameters {
-		parameters[k] = v
+	return a.jobQueue.QueuePluginJob(plugin, plugin.Definition.Plugin.Name, prepared)
+}
+
+func (a *App) ExecutePluginBatch(req models.BatchExecutionRequest) (*models.BatchSummary, error) {
+	if a.batches == nil {
+		return nil, fmt.Errorf("job queue not initialized")
 	}
-	parameters["outputDir"] = outputDir
-	parameters["pluginId"] = plugin.Definition.Plugin.ID
+	return a.batches.ExecutePluginBatch(req, a.projectOutputDir())
+}
 
-	jobID, err := a.jobQueue.CreateJobWithParameters(
-		plugin.Definition.Plugin.ID,
-		plugin.Definition.Plugin.Name,
-		plugin.Definition.Runtime.Type,
-		args,
-		parameters,
-	)
-	if err != nil {
-		return "", err
+func (a *App) GetBatch(id string) (*models.BatchSummary, error) {
+	if a.batches == nil {
+		return nil, fmt.Errorf("job queue not initialized")
 	}
+	return a.batches.GetBatch(id)
+}
 
-	go func() {
-		var execErr error
-		switch plugin.Definition.Runtime.Type {
-		case "python", "pythonWithR":
-			execErr = a.scriptExecutor.ExecutePythonScript(a.ctx, jobID, services.ScriptConfig{
-				Type:       plugin.Definition.Plugin.ID,
-				ScriptName: filepath.Base(plugin.ScriptPath),
-				Args:       args[1:],
-				OutputDir:  outputDir,
-			})
-		case "r":
-			execErr = a.scriptExecutor.ExecuteRScript(a.ctx, jobID, services.ScriptConfig{
-				Type:       plugin.Definition.Plugin.ID,
-				ScriptName: filepath.Base(plugin.ScriptPath),
-				Args:       args[1:],
-				OutputDir:  outputDir,
-			})
-		}
-
-		if execErr != nil {
-			log.Printf("[ExecutePluginV2] Error: %v", execErr)
-		}
-	}()
+func (a *App) ListBatches(projectID string) ([]models.BatchSummary, error) {
+	if a.batches == nil {
+		return nil, fmt.Errorf("job queue not initialized")
+	}
+	return a.batches.ListBatches(projectID)
+}
 
-	return jobID, nil
+func (a *App) CancelBatch(id string) error {
+	if a.batches == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	return a.batches.CancelBatch(id)
+}
+
+func (a *App) TestWebhook(url string) error {
+	if a.notifications == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	return a.notifications.TestWebhook(url)
 }
 
 func (a *App) ReloadPluginsV2() error {
@@ -1620,94 +1712,57 @@ func (a *App) GetJobQueueStatus() map[string]interface{} {
 	return a.jobQueue.GetQueueStatus()
 }
 
-func (a *App) HasInProgressJobs() bool {
+func (a *App) GetQueuedJobs() ([]*models.Job, error) {
 	if a.jobQueue == nil {
-		log.Println("[HasInProgressJobs] jobQueue is nil")
-		return false
+		return nil, fmt.Errorf("job queue not initialized")
 	}
-	jobs := a.jobQueue.GetJobsByStatus(models.JobStatusInProgress)
-	log.Printf("[HasInProgressJobs] Found %d in-progress jobs", len(jobs))
-	if len(jobs) > 0 {
-		for _, job := range jobs {
-			log.Printf("[HasInProgressJobs] Job ID: %s, Name: %s, Status: %s", job.ID, job.Name, job.Status)
-		}
-	}
-	return len(jobs) > 0
+	return a.jobQueue.GetQueuedJobs(), nil
 }
 
-func (a *App) checkUnfinishedJobs() {
-	time.Sleep(1 * time.Second)
-
-	if a.jobQueue == nil || a.db == nil {
-		return
+func (a *App) SetJobPriority(id string, priority int) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
 	}
+	return a.jobQueue.SetJobPriority(id, priority)
+}
 
-	var unfinishedJobs []*models.Job
-	a.db.GetDB().Where("status IN ?", []models.JobStatus{models.JobStatusPending, models.JobStatusInProgress}).
-		Order("created_at DESC").
-		Find(&unfinishedJobs)
-
-	if len(unfinishedJobs) == 0 {
-		log.Println("[checkUnfinishedJobs] No unfinished jobs found")
-		return
+func (a *App) ReorderPendingJobs(jobIDs []string) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
 	}
+	return a.jobQueue.ReorderPendingJobs(jobIDs)
+}
 
-	log.Printf("[checkUnfinishedJobs] Found %d unfinished job(s)", len(unfinishedJobs))
-
-	selection, err := runtime.MessageDialog(a.ctx, runtime.MessageDialogOptions{
-		Type:          runtime.QuestionDialog,
-		Title:         "Unfinished Jobs Found",
-		Message:       fmt.Sprintf("Found %d unfinished job(s) from previous session. Would you like to restart them?", len(unfinishedJobs)),
-		Buttons:       []string{"Restart Jobs", "Mark as Failed", "Leave as Is"},
-		DefaultButton: "Restart Jobs",
-	})
-
-	if err != nil {
-		log.Printf("[checkUnfinishedJobs] Error showing dialog: %v", err)
-		return
+func (a *App) GetWorkers() ([]models.WorkerInfo, error) {
+	if a.jobQueue == nil {
+		return nil, fmt.Errorf("job queue not initialized")
 	}
+	return a.jobQueue.GetWorkers(), nil
+}
 
-	log.Printf("[checkUnfinishedJobs] User selected: %s", selection)
-
-	switch selection {
-	case "Restart Jobs":
-		for _, job := range unfinishedJobs {
-			job.Status = models.JobStatusPending
-			job.Progress = 0
-			job.Error = ""
-			job.StartedAt = nil
-			job.CompletedAt = nil
-			job.TerminalOutput = []string{}
-
-			if err := a.db.GetDB().Save(job).Error; err != nil {
-				log.Printf("[checkUnfinishedJobs] Failed to reset job %s: %v", job.ID, err)
-				continue
-			}
-
-			a.jobQueue.RequeueJob(job)
-			runtime.EventsEmit(a.ctx, "job:update", job)
-			log.Printf("[checkUnfinishedJobs] Restarted job: %s - %s", job.ID, job.Name)
-		}
-
-	case "Mark as Failed":
-		now := time.Now()
-		for _, job := range unfinishedJobs {
-			job.Status = models.JobStatusFailed
-			job.Error = "Job was interrupted by application shutdown"
-			job.CompletedAt = &now
-
-			if err := a.db.GetDB().Save(job).Error; err != nil {
-				log.Printf("[checkUnfinishedJobs] Failed to mark job %s as failed: %v", job.ID, err)
-				continue
-			}
+func (a *App) SetWorkerCount(runtimeName string, count int) error {
+	if a.jobQueue == nil {
+		return fmt.Errorf("job queue not initialized")
+	}
+	if err := a.jobQueue.SetWorkerCount(runtimeName, count); err != nil {
+		return err
+	}
+	return a.settings.Set(runtimeName+"Workers", count)
+}
 
-			runtime.EventsEmit(a.ctx, "job:update", job)
-			log.Printf("[checkUnfinishedJobs] Marked job as failed: %s - %s", job.ID, job.Name)
+func (a *App) HasInProgressJobs() bool {
+	if a.jobQueue == nil {
+		log.Println("[HasInProgressJobs] jobQueue is nil")
+		return false
+	}
+	jobs := a.jobQueue.GetJobsByStatus(models.JobStatusInProgress)
+	log.Printf("[HasInProgressJobs] Found %d in-progress jobs", len(jobs))
+	if len(jobs) > 0 {
+		for _, job := range jobs {
+			log.Printf("[HasInProgressJobs] Job ID: %s, Name: %s, Status: %s", job.ID, job.Name, job.Status)
 		}
-
-	case "Leave as Is":
-		log.Println("[checkUnfinishedJobs] Leaving jobs as is")
 	}
+	return len(jobs) > 0
 }
 
 func (a *App) handleWindowClose(ctx context.Context) {
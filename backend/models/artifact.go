@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// JobArtifact is a file found in a job's output directory after it ran.
+type JobArtifact struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	JobID      string    `gorm:"not null;index" json:"jobId"`
+	Path       string    `gorm:"not null" json:"path"`
+	Size       int64     `json:"size"`
+	SHA256     string    `gorm:"column:sha256" json:"sha256"`
+	MimeType   string    `json:"mimeType"`
+	OutputName string    `json:"outputName,omitempty"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type PluginOutputs []PluginOutputV2
+
+func (p *PluginOutputs) Scan(value interface{}) error {
+	*p = nil
+	bytes, ok := value.([]byte)
+	if !ok {
+		if str, isString := value.(string); isString {
+			bytes = []byte(str)
+		} else {
+			return nil
+		}
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+func (p PluginOutputs) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(p)
+}
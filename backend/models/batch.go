@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Batch expansion modes. Cartesian runs every combination of the files and
+// parameter values; zip pairs the i-th file with the i-th value of every
+// parameter, so all lists must have the same length.
+const (
+	BatchModeCartesian = "cartesian"
+	BatchModeZip       = "zip"
+)
+
+// JobBatch is a named group of runs of one plugin, submitted together. Its
+// runs write to numbered folders of OutputDir, next to the combined index.
+// RunCount is the number of runs the batch expanded to and FinishedAt is
+// set once every one of them has finished.
+type JobBatch struct {
+	ID         string      `gorm:"primaryKey" json:"id"`
+	Name       string      `gorm:"not null" json:"name"`
+	PluginID   string      `gorm:"not null;index" json:"pluginId"`
+	ProjectID  string      `gorm:"index" json:"projectId"`
+	Mode       string      `json:"mode"`
+	Varied     StringArray `gorm:"type:text" json:"varied"`
+	OutputDir  string      `json:"outputDir"`
+	RunCount   int         `json:"runCount"`
+	CreatedAt  time.Time   `gorm:"not null" json:"createdAt"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+}
+
+// BatchExecutionRequest fans a plugin out over the Files of one file input
+// and the value lists in Sweep. Parameters holds the values shared by every
+// run.
+type BatchExecutionRequest struct {
+	Name       string                   `json:"name"`
+	PluginID   string                   `json:"pluginId"`
+	Parameters map[string]interface{}   `json:"parameters"`
+	FileInput  string                   `json:"fileInput,omitempty"`
+	Files      []string                 `json:"files,omitempty"`
+	Sweep      map[string][]interface{} `json:"sweep,omitempty"`
+	Mode       string                   `json:"mode"`
+	Options    JobOptions               `json:"options"`
+}
+
+// BatchRun is one run of a batch with the parameter values that vary
+// between runs.
+type BatchRun struct {
+	Index           int                    `json:"index"`
+	JobID           string                 `json:"jobId"`
+	Name            string                 `json:"name"`
+	Status          JobStatus              `json:"status"`
+	Values          map[string]interface{} `json:"values"`
+	OutputDir       string                 `json:"outputDir"`
+	Outputs         []string               `json:"outputs"`
+	Error           string                 `json:"error,omitempty"`
+	DurationSeconds *float64               `json:"durationSeconds,omitempty"`
+}
+
+// BatchSummary is a batch with its runs and their aggregate status: pending
+// until a run starts, in progress until every run has finished, then
+// completed when all runs completed and failed otherwise.
+type BatchSummary struct {
+	Batch     JobBatch          `json:"batch"`
+	Status    JobStatus         `json:"status"`
+	Counts    map[JobStatus]int `json:"counts"`
+	Total     int               `json:"total"`
+	Runs      []BatchRun        `json:"runs"`
+	IndexPath string            `json:"indexPath"`
+}
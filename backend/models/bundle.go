@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+const JobBundleFormatVersion = 1
+
+// JobBundleManifest describes a reproducibility bundle: what ran, with which
+// files and in which environment. It is stored as manifest.json at the root
+// of the archive.
+type JobBundleManifest struct {
+	FormatVersion int                    `json:"formatVersion"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	Job           Job                    `json:"job"`
+	Argv          []string               `json:"argv"`
+	Parameters    map[string]interface{} `json:"parameters"`
+	Script        *BundleFile            `json:"script,omitempty"`
+	Plugin        *BundlePlugin          `json:"plugin,omitempty"`
+	Inputs        []BundleFile           `json:"inputs"`
+	Environment   BundleEnvironment      `json:"environment"`
+	Outputs       []BundleFile           `json:"outputs"`
+	LogFile       string                 `json:"logFile,omitempty"`
+}
+
+// BundleFile records a file by its original location and checksum.
+// ArchivePath is set when the file itself is included in the bundle.
+type BundleFile struct {
+	Name        string `json:"name,omitempty"`
+	Path        string `json:"path"`
+	ArchivePath string `json:"archivePath,omitempty"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256,omitempty"`
+	Missing     bool   `json:"missing,omitempty"`
+}
+
+type BundlePlugin struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Version          string `json:"version"`
+	DefinitionFile   string `json:"definitionFile"`
+	DefinitionSHA256 string `json:"definitionSha256"`
+	ScriptFile       string `json:"scriptFile"`
+	ScriptSHA256     string `json:"scriptSha256"`
+}
+
+// BundleEnvironment lists the interpreter and packages a job ran with.
+// Packages are "name==version" entries.
+type BundleEnvironment struct {
+	Runtime     string   `json:"runtime"`
+	Interpreter string   `json:"interpreter,omitempty"`
+	EnvPath     string   `json:"envPath,omitempty"`
+	EnvType     string   `json:"envType,omitempty"`
+	Packages    []string `json:"packages,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+const (
+	BundleDifferencePlugin  = "plugin"
+	BundleDifferenceScript  = "script"
+	BundleDifferencePackage = "package"
+	BundleDifferenceInput   = "input"
+	// BundleDifferenceEnvironment is an environment recorded in the bundle
+	// that is not registered here; Local is the interpreter used instead.
+	BundleDifferenceEnvironment = "environment"
+)
+
+// BundleDifference is one way this machine differs from what a bundle
+// recorded. Local is empty when the item is missing here.
+type BundleDifference struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Recorded string `json:"recorded"`
+	Local    string `json:"local"`
+}
+
+// JobBundleImport reports the outcome of importing a bundle. CanRerun is
+// false when the plugin, script or an input needed to re-run is missing.
+type JobBundleImport struct {
+	JobID         string             `json:"jobId"`
+	OriginalJobID string             `json:"originalJobId"`
+	OutputPath    string             `json:"outputPath"`
+	Differences   []BundleDifference `json:"differences"`
+	CanRerun      bool               `json:"canRerun"`
+}
@@ -7,4 +7,19 @@ type Config struct {
 	RPath             string `json:"rPath"`
 	RLibPath          string `json:"rLibPath"`
 	CurtainBackendURL string `json:"curtainBackendUrl"`
+	PythonWorkers     int    `json:"pythonWorkers"`
+	RWorkers          int    `json:"rWorkers"`
+	DirectWorkers     int    `json:"directWorkers"`
+
+	RetentionKeepPerPlugin int `json:"retentionKeepPerPlugin"`
+	RetentionFailedDays    int `json:"retentionFailedDays"`
+	RetentionMaxOutputMB   int `json:"retentionMaxOutputMb"`
+	TrashRetentionDays     int `json:"trashRetentionDays"`
+
+	RecoveryPolicy string `json:"recoveryPolicy"`
+	InputSnapshot  string `json:"inputSnapshot"`
+
+	DesktopNotifications bool     `json:"desktopNotifications"`
+	WebhookURLs          []string `json:"webhookUrls"`
+	WebhookSecret        string   `json:"webhookSecret"`
 }
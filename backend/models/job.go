@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type JobStatus string
@@ -13,6 +15,9 @@ const (
 	JobStatusInProgress JobStatus = "in_progress"
 	JobStatusCompleted  JobStatus = "completed"
 	JobStatusFailed     JobStatus = "failed"
+	JobStatusCancelled  JobStatus = "cancelled"
+	JobStatusBlocked    JobStatus = "blocked"
+	JobStatusSkipped    JobStatus = "skipped"
 )
 
 type StringArray []string
@@ -64,24 +69,94 @@ func (j JSONMap) Value() (driver.Value, error) {
 }
 
 type Job struct {
-	ID             string      `gorm:"primaryKey" json:"id"`
-	Type           string      `gorm:"not null" json:"type"`
-	Name           string      `gorm:"not null" json:"name"`
-	Status         JobStatus   `gorm:"not null;default:pending" json:"status"`
-	Progress       float64     `gorm:"default:0" json:"progress"`
-	Command        string      `gorm:"not null" json:"command"`
-	Args           StringArray `gorm:"type:text" json:"args"`
-	Parameters     JSONMap     `gorm:"type:text" json:"parameters"`
-	PythonEnvPath  string      `json:"pythonEnvPath,omitempty"`
-	PythonEnvType  string      `json:"pythonEnvType,omitempty"`
-	REnvPath       string      `json:"rEnvPath,omitempty"`
-	REnvType       string      `json:"rEnvType,omitempty"`
-	OutputPath     string      `json:"outputPath"`
-	TerminalOutput StringArray `gorm:"type:text" json:"terminalOutput"`
-	CreatedAt      time.Time   `gorm:"not null" json:"createdAt"`
-	StartedAt      *time.Time  `json:"startedAt,omitempty"`
-	CompletedAt    *time.Time  `json:"completedAt,omitempty"`
-	Error          string      `json:"error,omitempty"`
+	ID               string           `gorm:"primaryKey" json:"id"`
+	Type             string           `gorm:"not null" json:"type"`
+	Name             string           `gorm:"not null" json:"name"`
+	ProjectID        string           `gorm:"index" json:"projectId"`
+	Tags             StringArray      `gorm:"type:text" json:"tags"`
+	Status           JobStatus        `gorm:"not null;default:pending;index" json:"status"`
+	Priority         int              `gorm:"default:0;index" json:"priority"`
+	QueuePosition    int64            `gorm:"index" json:"queuePosition"`
+	LeaseOwner       string           `gorm:"->" json:"leaseOwner,omitempty"`
+	LeaseExpiresAt   *time.Time       `gorm:"->" json:"leaseExpiresAt,omitempty"`
+	Progress         float64          `gorm:"default:0" json:"progress"`
+	ProgressMessage  string           `json:"progressMessage,omitempty"`
+	Command          string           `gorm:"not null" json:"command"`
+	Args             StringArray      `gorm:"type:text" json:"args"`
+	Parameters       JSONMap          `gorm:"type:text" json:"parameters"`
+	PythonEnvPath    string           `json:"pythonEnvPath,omitempty"`
+	PythonEnvType    string           `json:"pythonEnvType,omitempty"`
+	REnvPath         string           `json:"rEnvPath,omitempty"`
+	REnvType         string           `json:"rEnvType,omitempty"`
+	Limits           ExecutionLimits  `gorm:"embedded" json:"limits"`
+	DependsOn        StringArray      `gorm:"type:text" json:"dependsOn"`
+	Retry            RetryPolicy      `gorm:"type:text" json:"retry"`
+	ProgressPatterns ProgressPatterns `gorm:"type:text" json:"progressPatterns,omitempty"`
+	DeclaredOutputs  PluginOutputs    `gorm:"type:text" json:"declaredOutputs,omitempty"`
+	Attempt          int              `gorm:"default:1" json:"attempt"`
+	Attempts         JobAttempts      `gorm:"type:text" json:"attempts"`
+	NextAttemptAt    *time.Time       `json:"nextAttemptAt,omitempty"`
+	RunAfter         *time.Time       `gorm:"index" json:"runAfter,omitempty"`
+	Schedule         string           `json:"schedule,omitempty"`
+	ScheduledFrom    string           `gorm:"index" json:"scheduledFrom,omitempty"`
+	BatchID          string           `gorm:"index" json:"batchId,omitempty"`
+	OutputPath       string           `json:"outputPath"`
+	Inputs           JobInputs        `gorm:"type:text" json:"inputs"`
+	ProcessID        int              `json:"processId,omitempty"`
+	ProcessStartTime int64            `json:"processStartTime,omitempty"`
+	ConsolePath      string           `json:"consolePath,omitempty"`
+	TerminalOutput   StringArray      `gorm:"type:text" json:"terminalOutput"`
+	CreatedAt        time.Time        `gorm:"not null" json:"createdAt"`
+	StartedAt        *time.Time       `json:"startedAt,omitempty"`
+	CompletedAt      *time.Time       `json:"completedAt,omitempty"`
+	DurationSeconds  *float64         `gorm:"index" json:"durationSeconds,omitempty"`
+	Metrics          ProcessMetrics   `gorm:"embedded;embeddedPrefix:metrics_" json:"metrics"`
+	Error            string           `json:"error,omitempty"`
+	ErrorDetails     JobError         `gorm:"type:text" json:"errorDetails"`
+	Warnings         StringArray      `gorm:"type:text" json:"warnings,omitempty"`
+	TrashPath        string           `json:"trashPath,omitempty"`
+	DeletedAt        gorm.DeletedAt   `gorm:"index" json:"deletedAt,omitempty"`
+}
+
+// BeforeSave keeps DurationSeconds in step with StartedAt and CompletedAt so
+// jobs can be sorted by how long they ran.
+func (j *Job) BeforeSave(tx *gorm.DB) error {
+	j.DurationSeconds = nil
+	if j.StartedAt != nil && j.CompletedAt != nil {
+		duration := j.CompletedAt.Sub(*j.StartedAt).Seconds()
+		if duration < 0 {
+			duration = 0
+		}
+		j.DurationSeconds = &duration
+	}
+	return nil
+}
+
+type WorkerInfo struct {
+	ID             string     `json:"id"`
+	Runtime        string     `json:"runtime"`
+	Busy           bool       `json:"busy"`
+	Retiring       bool       `json:"retiring"`
+	JobID          string     `json:"jobId,omitempty"`
+	JobName        string     `json:"jobName,omitempty"`
+	PID            int        `json:"pid,omitempty"`
+	StartedAt      *time.Time `json:"startedAt,omitempty"`
+	RunningSeconds float64    `json:"runningSeconds"`
+}
+
+type JobOptions struct {
+	ProjectID        string            `json:"projectId,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	Limits           ExecutionLimits   `json:"limits"`
+	DependsOn        []string          `json:"dependsOn,omitempty"`
+	Priority         int               `json:"priority"`
+	Retry            *RetryPolicy      `json:"retry,omitempty"`
+	ProgressPatterns []ProgressPattern `json:"progressPatterns,omitempty"`
+	DeclaredOutputs  []PluginOutputV2  `json:"declaredOutputs,omitempty"`
+	RunAfter         *time.Time        `json:"runAfter,omitempty"`
+	Schedule         string            `json:"schedule,omitempty"`
+	SnapshotInputs   string            `json:"snapshotInputs,omitempty"`
+	BatchID          string            `json:"batchId,omitempty"`
 }
 
 type JobRequest struct {
@@ -89,4 +164,5 @@ type JobRequest struct {
 	Name       string                 `json:"name"`
 	InputFiles []string               `json:"inputFiles"`
 	Parameters map[string]interface{} `json:"parameters"`
+	Options    JobOptions             `json:"options"`
 }
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+const (
+	ComparisonSectionJob         = "job"
+	ComparisonSectionPlugin      = "plugin"
+	ComparisonSectionEnvironment = "environment"
+	ComparisonSectionParameter   = "parameter"
+	ComparisonSectionMetric      = "metric"
+)
+
+type ComparedJob struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Status    JobStatus `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ComparisonField is one compared property, with one value per job in the
+// order the jobs were given. Empty values mean the job did not record it.
+type ComparisonField struct {
+	Section string   `json:"section"`
+	Name    string   `json:"name"`
+	Values  []string `json:"values"`
+	Differs bool     `json:"differs"`
+}
+
+// ColumnDifference summarises the numeric differences in one column over
+// the rows both tables share.
+type ColumnDifference struct {
+	Name        string  `json:"name"`
+	Compared    int     `json:"compared"`
+	Differing   int     `json:"differing"`
+	MaxAbsDiff  float64 `json:"maxAbsDiff"`
+	MeanAbsDiff float64 `json:"meanAbsDiff"`
+}
+
+// FeatureDifference lists, for one shared row, how much each numeric column
+// changed from the reference table (other minus reference).
+type FeatureDifference struct {
+	Key         string             `json:"key"`
+	MaxAbsDiff  float64            `json:"maxAbsDiff"`
+	Differences map[string]float64 `json:"differences"`
+}
+
+// TableComparison compares an output table of the reference job with the
+// table of the same name from another job. Rows are matched on KeyColumn;
+// RepeatedKeys counts the rows of either table left out because their key
+// repeated.
+type TableComparison struct {
+	Name                 string              `json:"name"`
+	ReferenceJobID       string              `json:"referenceJobId"`
+	JobID                string              `json:"jobId"`
+	KeyColumn            string              `json:"keyColumn"`
+	ReferenceRows        int                 `json:"referenceRows"`
+	Rows                 int                 `json:"rows"`
+	SharedRows           int                 `json:"sharedRows"`
+	OnlyReferenceRows    int                 `json:"onlyReferenceRows"`
+	OnlyRows             int                 `json:"onlyRows"`
+	RepeatedKeys         int                 `json:"repeatedKeys"`
+	SharedColumns        []string            `json:"sharedColumns"`
+	OnlyReferenceColumns []string            `json:"onlyReferenceColumns"`
+	OnlyColumns          []string            `json:"onlyColumns"`
+	Columns              []ColumnDifference  `json:"columns"`
+	Features             []FeatureDifference `json:"features"`
+	Error                string              `json:"error,omitempty"`
+}
+
+// JobComparison is the result of comparing jobs side by side. The first job
+// is the reference that output tables are compared against.
+type JobComparison struct {
+	Jobs   []ComparedJob     `json:"jobs"`
+	Fields []ComparisonField `json:"fields"`
+	Tables []TableComparison `json:"tables"`
+}
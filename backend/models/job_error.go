@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Error categories say what kind of problem made a job fail, so the UI can
+// suggest a fix.
+const (
+	ErrorCategoryMissingPackage = "missing_package"
+	ErrorCategoryFileNotFound   = "file_not_found"
+	ErrorCategoryMemory         = "memory"
+	ErrorCategoryUserInput      = "user_input"
+	ErrorCategoryTimeout        = "timeout"
+	ErrorCategoryScript         = "script"
+)
+
+// ErrorFrame is the place in a script where an error was raised.
+type ErrorFrame struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Function string `json:"function,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
+// JobError is the cause of a failed job as parsed from a Python traceback or
+// an R error in its stderr. Type is the exception class for Python and the
+// failing call for R. Package and Path name the missing package or file
+// when the category is about one; for Python, Package is the name to pip
+// install rather than the module that failed to import. Excerpt holds the lines it was parsed
+// from.
+type JobError struct {
+	Language   string      `json:"language,omitempty"`
+	Category   string      `json:"category,omitempty"`
+	Type       string      `json:"type,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Frame      *ErrorFrame `json:"frame,omitempty"`
+	Package    string      `json:"package,omitempty"`
+	Path       string      `json:"path,omitempty"`
+	Suggestion string      `json:"suggestion,omitempty"`
+	Excerpt    []string    `json:"excerpt,omitempty"`
+}
+
+func (e *JobError) Scan(value interface{}) error {
+	*e = JobError{}
+	bytes, ok := value.([]byte)
+	if !ok {
+		if str, isString := value.(string); isString {
+			bytes = []byte(str)
+		} else {
+			return nil
+		}
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+func (e JobError) Value() (driver.Value, error) {
+	if e.Category == "" && e.Message == "" {
+		return "{}", nil
+	}
+	return json.Marshal(e)
+}
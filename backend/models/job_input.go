@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Input snapshot modes. A snapshot copies or hardlinks a job's input files
+// into its output directory at submission, so the job can be rerun after the
+// originals change. A hardlink shares the original's data, so edits made in
+// place still reach it; the recorded hash shows when that happened.
+const (
+	InputSnapshotNone     = "none"
+	InputSnapshotCopy     = "copy"
+	InputSnapshotHardlink = "hardlink"
+)
+
+// Input states reported when a job's inputs are checked against the hashes
+// recorded at submission.
+const (
+	InputStatusUnchanged = "unchanged"
+	InputStatusModified  = "modified"
+	InputStatusMissing   = "missing"
+)
+
+// JobInput is an input file of a job with its hash at submission time and,
+// when the inputs were snapshotted, the path of its snapshot.
+type JobInput struct {
+	Path         string `json:"path"`
+	Size         int64  `json:"size"`
+	SHA256       string `json:"sha256"`
+	SnapshotPath string `json:"snapshotPath,omitempty"`
+}
+
+type JobInputs []JobInput
+
+func (i *JobInputs) Scan(value interface{}) error {
+	if value == nil {
+		*i = []JobInput{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		if str, isString := value.(string); isString {
+			bytes = []byte(str)
+		} else {
+			*i = []JobInput{}
+			return nil
+		}
+	}
+
+	return json.Unmarshal(bytes, i)
+}
+
+func (i JobInputs) Value() (driver.Value, error) {
+	if len(i) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(i)
+}
+
+// JobInputCheck compares an input with the hash recorded at submission.
+// Status describes the original file and SnapshotStatus the snapshot, which
+// is empty when the input was not snapshotted.
+type JobInputCheck struct {
+	JobInput
+	Status         string `json:"status"`
+	SnapshotStatus string `json:"snapshotStatus,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+)
+
+type JobLogLine struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	JobID     string    `gorm:"not null;index:idx_job_log_lines_job_seq,priority:1" json:"jobId"`
+	Seq       int64     `gorm:"not null;index:idx_job_log_lines_job_seq,priority:2" json:"seq"`
+	Attempt   int       `json:"attempt"`
+	Stream    string    `gorm:"not null" json:"stream"`
+	Line      string    `json:"line"`
+	Timestamp time.Time `gorm:"not null" json:"timestamp"`
+}
+
+type JobLogPage struct {
+	JobID  string       `json:"jobId"`
+	Lines  []JobLogLine `json:"lines"`
+	Total  int64        `json:"total"`
+	Offset int          `json:"offset"`
+	Limit  int          `json:"limit"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// ProcessMetrics is the resource usage of the process a job ran. ExitCode,
+// the CPU times and MaxRSSBytes come from the operating system when the
+// process exits; an exit code of -1 means it was killed by a signal.
+// PeakRSSBytes and PeakProcesses are sampled while it runs and include every
+// descendant process, so they also cover workers that were never waited for.
+type ProcessMetrics struct {
+	ExitCode         *int    `json:"exitCode,omitempty"`
+	UserCPUSeconds   float64 `json:"userCpuSeconds"`
+	SystemCPUSeconds float64 `json:"systemCpuSeconds"`
+	MaxRSSBytes      int64   `json:"maxRssBytes"`
+	PeakRSSBytes     int64   `json:"peakRssBytes"`
+	PeakProcesses    int     `json:"peakProcesses"`
+}
+
+// CPUSeconds is the total user and system CPU time.
+func (m ProcessMetrics) CPUSeconds() float64 {
+	return m.UserCPUSeconds + m.SystemCPUSeconds
+}
+
+// PeakMemoryBytes is the larger of the sampled and the reported peak memory.
+func (m ProcessMetrics) PeakMemoryBytes() int64 {
+	return max(m.MaxRSSBytes, m.PeakRSSBytes)
+}
+
+// JobRuntime is one run in a plugin's runtime history.
+type JobRuntime struct {
+	JobID           string         `json:"jobId"`
+	Name            string         `json:"name"`
+	ProjectID       string         `json:"projectId"`
+	Status          JobStatus      `json:"status"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	DurationSeconds *float64       `json:"durationSeconds,omitempty"`
+	Metrics         ProcessMetrics `json:"metrics"`
+}
+
+// PluginRuntimeHistory lists the most recent finished runs of a plugin,
+// newest first. The summary only counts runs that completed, since failed
+// runs often stop early.
+type PluginRuntimeHistory struct {
+	PluginID              string       `json:"pluginId"`
+	Runs                  []JobRuntime `json:"runs"`
+	Completed             int          `json:"completed"`
+	Failed                int          `json:"failed"`
+	MedianDurationSeconds float64      `json:"medianDurationSeconds"`
+	MaxDurationSeconds    float64      `json:"maxDurationSeconds"`
+	MedianCPUSeconds      float64      `json:"medianCpuSeconds"`
+	MaxMemoryBytes        int64        `json:"maxMemoryBytes"`
+}
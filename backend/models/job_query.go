@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	JobSortCreated  = "created"
+	JobSortStarted  = "started"
+	JobSortDuration = "duration"
+)
+
+// JobQuery filters and pages through job history. Empty fields match every
+// job. ProjectID defaults to the active project unless AllProjects is set.
+// Cursor is the NextCursor of the previous page.
+type JobQuery struct {
+	Statuses      []JobStatus `json:"statuses,omitempty"`
+	PluginID      string      `json:"pluginId,omitempty"`
+	EnvPath       string      `json:"envPath,omitempty"`
+	Tag           string      `json:"tag,omitempty"`
+	Search        string      `json:"search,omitempty"`
+	ProjectID     string      `json:"projectId,omitempty"`
+	AllProjects   bool        `json:"allProjects,omitempty"`
+	CreatedAfter  *time.Time  `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time  `json:"createdBefore,omitempty"`
+	SortBy        string      `json:"sortBy,omitempty"`
+	Ascending     bool        `json:"ascending,omitempty"`
+	Limit         int         `json:"limit,omitempty"`
+	Cursor        string      `json:"cursor,omitempty"`
+}
+
+// JobQueryPage is one page of a job query. NextCursor is empty on the last
+// page; Total counts every job matching the filters.
+type JobQueryPage struct {
+	Jobs       []Job  `json:"jobs"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Total      int64  `json:"total"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// JobSearchResult is a job matching a full-text search. Field names the
+// indexed field the snippet was taken from: name, parameters, inputs, error
+// or log. Higher scores are better matches.
+type JobSearchResult struct {
+	Job     Job           `json:"job"`
+	Score   float64       `json:"score"`
+	Field   string        `json:"field"`
+	Snippet []SnippetPart `json:"snippet"`
+}
+
+// SnippetPart is a piece of a search snippet. Match is set on the pieces
+// that matched the query, so they can be highlighted.
+type SnippetPart struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	WebhookEventJobFinished   = "job.finished"
+	WebhookEventBatchFinished = "batch.finished"
+	WebhookEventTest          = "test"
+)
+
+// WebhookPayload is the JSON body POSTed to webhook URLs when a job or batch
+// reaches a terminal state. Batch events leave the job fields empty and
+// fill in Total and Counts; OutputPath is then the batch directory.
+type WebhookPayload struct {
+	Event           string            `json:"event"`
+	JobID           string            `json:"jobId,omitempty"`
+	BatchID         string            `json:"batchId,omitempty"`
+	Name            string            `json:"name"`
+	Plugin          string            `json:"plugin"`
+	Status          JobStatus         `json:"status"`
+	DurationSeconds *float64          `json:"durationSeconds,omitempty"`
+	OutputPath      string            `json:"outputPath"`
+	Error           string            `json:"error,omitempty"`
+	Total           int               `json:"total,omitempty"`
+	Counts          map[JobStatus]int `json:"counts,omitempty"`
+	FinishedAt      time.Time         `json:"finishedAt"`
+}
@@ -61,4 +61,5 @@ type Plugin struct {
 type PluginExecutionRequest struct {
 	PluginID   string                 `json:"pluginId"`
 	Parameters map[string]interface{} `json:"parameters"`
+	Options    JobOptions             `json:"options"`
 }
@@ -60,6 +60,7 @@ type PluginOutputV2 struct {
 	Type        string `yaml:"type" json:"type"`
 	Description string `yaml:"description" json:"description"`
 	Format      string `yaml:"format" json:"format"`
+	Required    bool   `yaml:"required,omitempty" json:"required,omitempty"`
 }
 
 type PlotCustomization struct {
@@ -106,10 +107,33 @@ type Requirements struct {
 	Packages []string `yaml:"packages,omitempty" json:"packages,omitempty"`
 }
 
+type ExecutionLimits struct {
+	Timeout       int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	MaxMemoryMB   int `yaml:"maxMemoryMB,omitempty" json:"maxMemoryMB,omitempty"`
+	MaxCPUSeconds int `yaml:"maxCPUSeconds,omitempty" json:"maxCPUSeconds,omitempty"`
+}
+
+// WithDefaults returns a copy of l where every unset limit is taken from defaults.
+func (l ExecutionLimits) WithDefaults(defaults ExecutionLimits) ExecutionLimits {
+	if l.Timeout == 0 {
+		l.Timeout = defaults.Timeout
+	}
+	if l.MaxMemoryMB == 0 {
+		l.MaxMemoryMB = defaults.MaxMemoryMB
+	}
+	if l.MaxCPUSeconds == 0 {
+		l.MaxCPUSeconds = defaults.MaxCPUSeconds
+	}
+	return l
+}
+
 type PluginExecution struct {
 	ArgsMapping  map[string]interface{} `yaml:"argsMapping" json:"argsMapping"`
 	OutputDir    string                 `yaml:"outputDir" json:"outputDir"`
 	Requirements Requirements           `yaml:"requirements,omitempty" json:"requirements,omitempty"`
+	Limits       *ExecutionLimits       `yaml:"limits,omitempty" json:"limits,omitempty"`
+	Retry        *RetryPolicy           `yaml:"retry,omitempty" json:"retry,omitempty"`
+	Progress     []ProgressPattern      `yaml:"progress,omitempty" json:"progress,omitempty"`
 }
 
 type PluginRuntimeV2 struct {
@@ -151,4 +175,5 @@ type PluginV2 struct {
 type PluginExecutionRequestV2 struct {
 	PluginID   string                 `json:"pluginId"`
 	Parameters map[string]interface{} `json:"parameters"`
+	Options    JobOptions             `json:"options"`
 }
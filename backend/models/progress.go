@@ -0,0 +1,40 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// ProgressPattern extracts progress from the output of programs that do not
+// speak the ##cauldron protocol. The regular expression may use the named
+// groups "progress" (a percentage), "current" and "total" (a fraction) and
+// "message".
+type ProgressPattern struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Hide    bool   `yaml:"hide,omitempty" json:"hide,omitempty"`
+}
+
+type ProgressPatterns []ProgressPattern
+
+func (p *ProgressPatterns) Scan(value interface{}) error {
+	*p = nil
+	bytes, ok := value.([]byte)
+	if !ok {
+		if str, isString := value.(string); isString {
+			bytes = []byte(str)
+		} else {
+			return nil
+		}
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+func (p ProgressPatterns) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(p)
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// DefaultProjectID is the project that jobs and files created before
+// projects existed belong to. It cannot be deleted.
+const DefaultProjectID = "default"
+
+// Project is a named workspace that owns imported files, jobs and their
+// outputs, and sample annotations. An empty OutputDirectory means the
+// configured output directory.
+type Project struct {
+	ID              string    `gorm:"primaryKey" json:"id"`
+	Name            string    `gorm:"not null;uniqueIndex" json:"name"`
+	Description     string    `json:"description,omitempty"`
+	OutputDirectory string    `json:"outputDirectory"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// SampleAnnotation assigns a sample of a project to a condition and,
+// optionally, a batch and a plot color. Sample names are unique within a
+// project.
+type SampleAnnotation struct {
+	ID        uint   `gorm:"primaryKey" json:"-"`
+	ProjectID string `gorm:"not null;uniqueIndex:idx_sample_annotation" json:"-"`
+	Sample    string `gorm:"not null;uniqueIndex:idx_sample_annotation" json:"sample"`
+	Condition string `json:"condition"`
+	Batch     string `json:"batch,omitempty"`
+	Color     string `json:"color,omitempty"`
+}
+
+// ProjectSummary is a project with counts of what it owns.
+type ProjectSummary struct {
+	Project         Project `json:"project"`
+	JobCount        int64   `json:"jobCount"`
+	FileCount       int64   `json:"fileCount"`
+	AnnotationCount int64   `json:"annotationCount"`
+	Active          bool    `json:"active"`
+}
+
+// ProjectImport reports the outcome of importing a project archive.
+type ProjectImport struct {
+	Project         Project           `json:"project"`
+	Jobs            []JobBundleImport `json:"jobs"`
+	FileCount       int               `json:"fileCount"`
+	AnnotationCount int               `json:"annotationCount"`
+	MissingFiles    []string          `json:"missingFiles"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// Recovery policies decide what happens on startup to jobs that were running
+// when the app last exited.
+const (
+	RecoveryPolicyRestart = "restart"
+	RecoveryPolicyFail    = "fail"
+	RecoveryPolicyAsk     = "ask"
+)
+
+// Recovery actions for a single interrupted job. Reattach keeps watching a
+// process that is still running; restart and fail stop it first.
+const (
+	RecoveryActionReattach = "reattach"
+	RecoveryActionRestart  = "restart"
+	RecoveryActionFail     = "fail"
+)
+
+// InterruptedJob is a job left running by a previous session that is waiting
+// for a recovery decision. ProcessAlive reports whether its process is still
+// running. CanReattach also requires that its output went to console files
+// the new session can follow.
+type InterruptedJob struct {
+	Job          Job  `json:"job"`
+	ProcessAlive bool `json:"processAlive"`
+	CanReattach  bool `json:"canReattach"`
+}
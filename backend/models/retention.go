@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// RetentionPolicy decides which finished jobs a cleanup removes. A rule set
+// to zero is disabled.
+type RetentionPolicy struct {
+	KeepPerPlugin   int `json:"keepPerPlugin"`
+	FailedAfterDays int `json:"failedAfterDays"`
+	MaxOutputMB     int `json:"maxOutputMb"`
+}
+
+// CleanupCandidate is a job a cleanup would delete. RemovesOutput is false
+// when its output directory is kept because another job still uses it or
+// it lies outside the output directories Cauldron manages.
+type CleanupCandidate struct {
+	JobID         string    `json:"jobId"`
+	Name          string    `json:"name"`
+	Plugin        string    `json:"plugin"`
+	Status        JobStatus `json:"status"`
+	CreatedAt     time.Time `json:"createdAt"`
+	OutputPath    string    `json:"outputPath,omitempty"`
+	OutputBytes   int64     `json:"outputBytes"`
+	RemovesOutput bool      `json:"removesOutput"`
+	Reasons       []string  `json:"reasons"`
+}
+
+// CleanupPlan previews a cleanup. OutputBytes is the size of all finished
+// job outputs now and FreedBytes how much of it the cleanup would remove.
+type CleanupPlan struct {
+	Policy      RetentionPolicy    `json:"policy"`
+	Candidates  []CleanupCandidate `json:"candidates"`
+	OutputBytes int64              `json:"outputBytes"`
+	FreedBytes  int64              `json:"freedBytes"`
+}
+
+type CleanupSummary struct {
+	DeletedJobs        int      `json:"deletedJobs"`
+	DeletedDirectories int      `json:"deletedDirectories"`
+	FreedBytes         int64    `json:"freedBytes"`
+	Errors             []string `json:"errors,omitempty"`
+}
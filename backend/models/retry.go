@@ -0,0 +1,128 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+	"regexp"
+	"time"
+)
+
+const defaultRetryBackoffFactor = 2
+
+// RetryPolicy describes when a failed job is run again. With no exit codes or
+// patterns every failure except cancellation is retried.
+type RetryPolicy struct {
+	MaxAttempts      int      `yaml:"maxAttempts" json:"maxAttempts"`
+	Backoff          int      `yaml:"backoff,omitempty" json:"backoff,omitempty"`
+	BackoffFactor    float64  `yaml:"backoffFactor,omitempty" json:"backoffFactor,omitempty"`
+	MaxBackoff       int      `yaml:"maxBackoff,omitempty" json:"maxBackoff,omitempty"`
+	RetryOnExitCodes []int    `yaml:"retryOnExitCodes,omitempty" json:"retryOnExitCodes,omitempty"`
+	RetryOnPatterns  []string `yaml:"retryOnPatterns,omitempty" json:"retryOnPatterns,omitempty"`
+}
+
+func (r *RetryPolicy) Scan(value interface{}) error {
+	*r = RetryPolicy{}
+	bytes, ok := value.([]byte)
+	if !ok {
+		if str, isString := value.(string); isString {
+			bytes = []byte(str)
+		} else {
+			return nil
+		}
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+func (r RetryPolicy) Value() (driver.Value, error) {
+	if r.MaxAttempts == 0 {
+		return "{}", nil
+	}
+	return json.Marshal(r)
+}
+
+// Delay returns how long to wait before the given attempt, where attempt 2 is
+// the first retry. A zero backoff retries immediately.
+func (r RetryPolicy) Delay(attempt int) time.Duration {
+	backoff := float64(r.Backoff)
+	factor := r.BackoffFactor
+	if factor <= 0 {
+		factor = defaultRetryBackoffFactor
+	}
+
+	seconds := backoff * math.Pow(factor, float64(attempt-2))
+	if r.MaxBackoff > 0 && seconds > float64(r.MaxBackoff) {
+		seconds = float64(r.MaxBackoff)
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Retryable reports whether a failure with the given exit code and output
+// matches the policy. An exit code of -1 means the process did not exit
+// normally.
+func (r RetryPolicy) Retryable(exitCode int, output []string) bool {
+	if len(r.RetryOnExitCodes) == 0 && len(r.RetryOnPatterns) == 0 {
+		return true
+	}
+
+	for _, code := range r.RetryOnExitCodes {
+		if code == exitCode {
+			return true
+		}
+	}
+
+	for _, pattern := range r.RetryOnPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		for _, line := range output {
+			if re.MatchString(line) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type JobAttempt struct {
+	Attempt        int        `json:"attempt"`
+	Status         JobStatus  `json:"status"`
+	ExitCode       *int       `json:"exitCode,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	TerminalOutput []string   `json:"terminalOutput"`
+	StartedAt      *time.Time `json:"startedAt,omitempty"`
+	FinishedAt     time.Time  `json:"finishedAt"`
+}
+
+type JobAttempts []JobAttempt
+
+func (a *JobAttempts) Scan(value interface{}) error {
+	if value == nil {
+		*a = []JobAttempt{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		if str, isString := value.(string); isString {
+			bytes = []byte(str)
+		} else {
+			*a = []JobAttempt{}
+			return nil
+		}
+	}
+
+	return json.Unmarshal(bytes, a)
+}
+
+func (a JobAttempts) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(a)
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"gorm.io/gorm"
+)
+
+// maxArtifactsPerJob bounds how many files of an output directory are
+// recorded, so pointing a job at a large existing directory stays cheap.
+const maxArtifactsPerJob = 10000
+
+type ArtifactService struct {
+	db *DatabaseService
+}
+
+func NewArtifactService(db *DatabaseService) *ArtifactService {
+	return &ArtifactService{
+		db: db,
+	}
+}
+
+// ArtifactScan is the result of scanning a job's output directory.
+type ArtifactScan struct {
+	Artifacts       []models.JobArtifact
+	MissingRequired []string
+	MissingOptional []string
+}
+
+// ScanJobOutputs records every file under outputDir as an artifact of the
+// job, replacing any earlier manifest, and reports which declared outputs
+// were not produced.
+func (s *ArtifactService) ScanJobOutputs(jobID string, outputDir string, declared []models.PluginOutputV2) (*ArtifactScan, error) {
+	scan := &ArtifactScan{}
+	now := time.Now()
+
+	err := filepath.WalkDir(outputDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if filePath != outputDir && (entry.Name() == inputSnapshotDirName || entry.Name() == jobConsoleDirName) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if len(scan.Artifacts) >= maxArtifactsPerJob {
+			return fs.SkipAll
+		}
+
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(outputDir, filePath)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		checksum, mimeType, err := hashFile(filePath)
+		if err != nil {
+			log.Printf("[ScanJobOutputs] Failed to read %s: %v", filePath, err)
+			return nil
+		}
+
+		scan.Artifacts = append(scan.Artifacts, models.JobArtifact{
+			JobID:      jobID,
+			Path:       rel,
+			Size:       info.Size(),
+			SHA256:     checksum,
+			MimeType:   mimeType,
+			OutputName: matchDeclaredOutput(rel, declared),
+			ModifiedAt: info.ModTime(),
+			CreatedAt:  now,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan output directory: %w", err)
+	}
+
+	for _, output := range declared {
+		if hasArtifactFor(scan.Artifacts, output.Name) {
+			continue
+		}
+		if output.Required {
+			scan.MissingRequired = append(scan.MissingRequired, output.Path)
+		} else {
+			scan.MissingOptional = append(scan.MissingOptional, output.Path)
+		}
+	}
+
+	err = s.db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", jobID).Delete(&models.JobArtifact{}).Error; err != nil {
+			return err
+		}
+		if len(scan.Artifacts) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(scan.Artifacts, 200).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store artifacts: %w", err)
+	}
+
+	return scan, nil
+}
+
+func (s *ArtifactService) GetJobArtifacts(jobID string) ([]models.JobArtifact, error) {
+	artifacts := []models.JobArtifact{}
+	err := s.db.GetDB().Where("job_id = ?", jobID).Order("path ASC").Find(&artifacts).Error
+	return artifacts, err
+}
+
+// GetArtifactsForJobs returns the artifacts of several jobs at once, by job
+// ID, each job's sorted by path.
+func (s *ArtifactService) GetArtifactsForJobs(jobIDs []string) (map[string][]models.JobArtifact, error) {
+	byJob := make(map[string][]models.JobArtifact, len(jobIDs))
+	if len(jobIDs) == 0 {
+		return byJob, nil
+	}
+	var artifacts []models.JobArtifact
+	if err := s.db.GetDB().Where("job_id IN ?", jobIDs).Order("job_id ASC, path ASC").Find(&artifacts).Error; err != nil {
+		return nil, err
+	}
+	for _, artifact := range artifacts {
+		byJob[artifact.JobID] = append(byJob[artifact.JobID], artifact)
+	}
+	return byJob, nil
+}
+
+func (s *ArtifactService) DeleteJobArtifacts(jobID string) error {
+	return s.db.GetDB().Where("job_id = ?", jobID).Delete(&models.JobArtifact{}).Error
+}
+
+// matchDeclaredOutput returns the name of the first declared output whose
+// path, which may contain wildcards, matches the relative file path.
+func matchDeclaredOutput(rel string, declared []models.PluginOutputV2) string {
+	for _, output := range declared {
+		pattern := filepath.ToSlash(output.Path)
+		if matched, err := path.Match(pattern, rel); err == nil && matched {
+			return output.Name
+		}
+	}
+	return ""
+}
+
+func hasArtifactFor(artifacts []models.JobArtifact, outputName string) bool {
+	for _, artifact := range artifacts {
+		if artifact.OutputName == outputName {
+			return true
+		}
+	}
+	return false
+}
+
+func hashFile(filePath string) (string, string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", "", err
+	}
+	head = head[:n]
+
+	hash := sha256.New()
+	hash.Write(head)
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), detectMimeType(filePath, head), nil
+}
+
+var artifactMimeTypes = map[string]string{
+	".tsv":  "text/tab-separated-values",
+	".csv":  "text/csv",
+	".txt":  "text/plain",
+	".json": "application/json",
+	".svg":  "image/svg+xml",
+	".nwk":  "text/plain",
+	".aln":  "text/plain",
+}
+
+func detectMimeType(filePath string, head []byte) string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if mimeType, ok := artifactMimeTypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(head)
+}
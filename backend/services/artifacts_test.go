@@ -0,0 +1,139 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestScanJobOutputsMatchesDeclaredOutputs(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "plots"), 0755)
+	os.WriteFile(filepath.Join(dir, "result.txt"), []byte("a\tb\n1\t2\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "plots", "pca.svg"), []byte("<svg></svg>"), 0644)
+
+	declared := []models.PluginOutputV2{
+		{Name: "result", Path: "result.txt", Required: true},
+		{Name: "plots", Path: "plots/*.svg"},
+		{Name: "summary", Path: "summary.txt"},
+		{Name: "model", Path: "model.json", Required: true},
+	}
+
+	service := NewArtifactService(db)
+	scan, err := service.ScanJobOutputs("job-1", dir, declared)
+	if err != nil {
+		t.Fatalf("ScanJobOutputs failed: %v", err)
+	}
+
+	if len(scan.MissingRequired) != 1 || scan.MissingRequired[0] != "model.json" {
+		t.Errorf("Expected model.json to be reported as missing, got %v", scan.MissingRequired)
+	}
+	if len(scan.MissingOptional) != 1 || scan.MissingOptional[0] != "summary.txt" {
+		t.Errorf("Expected summary.txt to be reported as missing, got %v", scan.MissingOptional)
+	}
+
+	artifacts, err := service.GetJobArtifacts("job-1")
+	if err != nil {
+		t.Fatalf("GetJobArtifacts failed: %v", err)
+	}
+	if len(artifacts) != 2 {
+		t.Fatalf("Expected 2 artifacts, got %+v", artifacts)
+	}
+
+	plot, result := artifacts[0], artifacts[1]
+	if plot.Path != "plots/pca.svg" || plot.OutputName != "plots" || plot.MimeType != "image/svg+xml" {
+		t.Errorf("Unexpected plot artifact: %+v", plot)
+	}
+	checksum := sha256.Sum256([]byte("a\tb\n1\t2\n"))
+	if result.OutputName != "result" || result.Size != 8 || result.SHA256 != hex.EncodeToString(checksum[:]) {
+		t.Errorf("Unexpected result artifact: %+v", result)
+	}
+
+	os.Remove(filepath.Join(dir, "plots", "pca.svg"))
+	if _, err := service.ScanJobOutputs("job-1", dir, declared); err != nil {
+		t.Fatalf("Rescan failed: %v", err)
+	}
+	if artifacts, _ := service.GetJobArtifacts("job-1"); len(artifacts) != 1 {
+		t.Errorf("Expected a rescan to replace the manifest, got %+v", artifacts)
+	}
+}
+
+func TestGetArtifactsForJobs(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	service := NewArtifactService(db)
+	for _, jobID := range []string{"job-1", "job-2"} {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "b.txt"), []byte(jobID), 0644)
+		os.WriteFile(filepath.Join(dir, "a.txt"), []byte(jobID), 0644)
+		if _, err := service.ScanJobOutputs(jobID, dir, nil); err != nil {
+			t.Fatalf("ScanJobOutputs failed: %v", err)
+		}
+	}
+
+	byJob, err := service.GetArtifactsForJobs([]string{"job-1", "job-2", "job-3"})
+	if err != nil {
+		t.Fatalf("GetArtifactsForJobs failed: %v", err)
+	}
+	if len(byJob) != 2 || len(byJob["job-1"]) != 2 || len(byJob["job-3"]) != 0 {
+		t.Fatalf("Expected the artifacts of two jobs, got %+v", byJob)
+	}
+	if byJob["job-2"][0].Path != "a.txt" || byJob["job-2"][0].JobID != "job-2" {
+		t.Errorf("Expected each job's artifacts sorted by path, got %+v", byJob["job-2"])
+	}
+}
+
+func TestJobFailsWhenRequiredOutputIsMissing(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	outputDir := t.TempDir()
+	jobID, err := jobQueue.CreateJobWithOptions("test", "Forgetful", "direct",
+		[]string{"sh", "-c", "echo done > optional.txt"},
+		map[string]interface{}{"outputDir": outputDir},
+		models.JobOptions{DeclaredOutputs: []models.PluginOutputV2{
+			{Name: "optional", Path: "optional.txt"},
+			{Name: "result", Path: "result.txt", Required: true},
+		}})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	job := waitForJobStatus(t, db, jobID, models.JobStatusFailed)
+	if !strings.Contains(job.Error, "result.txt") {
+		t.Errorf("Expected the error to name the missing output, got %q", job.Error)
+	}
+
+	artifacts, err := jobQueue.Artifacts().GetJobArtifacts(jobID)
+	if err != nil {
+		t.Fatalf("GetJobArtifacts failed: %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].OutputName != "optional" {
+		t.Errorf("Expected the produced file in the manifest, got %+v", artifacts)
+	}
+}
+
+func TestJobWarnsWhenOptionalOutputIsMissing(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	jobID, err := jobQueue.CreateJobWithOptions("test", "Partial", "direct",
+		[]string{"true"},
+		map[string]interface{}{"outputDir": t.TempDir()},
+		models.JobOptions{DeclaredOutputs: []models.PluginOutputV2{{Name: "summary", Path: "summary.txt"}}})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	job := waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+	if len(job.Warnings) != 1 || !strings.Contains(job.Warnings[0], "summary.txt") {
+		t.Errorf("Expected a warning about summary.txt, got %v", job.Warnings)
+	}
+}
@@ -0,0 +1,491 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+// maxBatchRuns caps how many runs one batch may expand to, so a mistyped
+// sweep cannot flood the queue.
+const maxBatchRuns = 1000
+
+const batchIndexFile = "index.tsv"
+
+// batchIndexDelay is how long the index of a batch waits after a run
+// finishes before it is rewritten.
+const batchIndexDelay = 500 * time.Millisecond
+
+// BatchService fans a plugin out over many input files or a parameter grid
+// and tracks the runs as one batch. Every run writes to its own folder of
+// the batch directory, and index.tsv there lists the runs, the values they
+// were given, their status and the files they produced.
+type BatchService struct {
+	queue         *JobQueueService
+	plugins       *PluginLoaderV2
+	executor      *PluginExecutor
+	indexMu       sync.Mutex
+	finishedHooks []func(summary *models.BatchSummary)
+
+	pendingMu      sync.Mutex
+	pendingIndexes map[string]bool
+}
+
+func NewBatchService(queue *JobQueueService, plugins *PluginLoaderV2, executor *PluginExecutor) *BatchService {
+	service := &BatchService{
+		queue:          queue,
+		plugins:        plugins,
+		executor:       executor,
+		pendingIndexes: make(map[string]bool),
+	}
+	queue.OnJobFinished(service.jobFinished)
+	return service
+}
+
+// OnBatchFinished registers a hook that is called once when every run of a
+// batch has finished. Hooks should be registered before batches run.
+func (b *BatchService) OnBatchFinished(hook func(summary *models.BatchSummary)) {
+	b.indexMu.Lock()
+	b.finishedHooks = append(b.finishedHooks, hook)
+	b.indexMu.Unlock()
+}
+
+// batchDimension is one list being fanned out: the files of a file input or
+// the values of a parameter.
+type batchDimension struct {
+	name   string
+	values []interface{}
+}
+
+// ExecutePluginBatch expands a batch request into runs, validates every run
+// and only then queues them, all together, so a batch is either submitted
+// whole or not at all: if the runs cannot be queued, the batch is withdrawn.
+// The batch directory is created below baseOutputDir.
+func (b *BatchService) ExecutePluginBatch(req models.BatchExecutionRequest, baseOutputDir string) (*models.BatchSummary, error) {
+	plugin, err := b.plugins.GetPlugin(req.PluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.BatchModeCartesian
+	}
+	dimensions, err := batchDimensions(req)
+	if err != nil {
+		return nil, err
+	}
+	combinations, err := expandBatch(dimensions, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &models.JobBatch{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		PluginID:  plugin.Definition.Plugin.ID,
+		ProjectID: req.Options.ProjectID,
+		Mode:      mode,
+		RunCount:  len(combinations),
+		CreatedAt: time.Now(),
+	}
+	if batch.Name == "" {
+		batch.Name = plugin.Definition.Plugin.Name + " batch"
+	}
+	if batch.ProjectID == "" {
+		batch.ProjectID = b.queue.Projects().ActiveProjectID()
+	}
+	for _, dimension := range dimensions {
+		batch.Varied = append(batch.Varied, dimension.name)
+	}
+	batch.OutputDir = filepath.Join(baseOutputDir, fmt.Sprintf("batch_%s_%s",
+		plugin.Definition.Plugin.ID, batch.CreatedAt.Format("20060102_150405")))
+
+	prepared := make([]*PluginJob, len(combinations))
+	for i, values := range combinations {
+		parameters := make(map[string]interface{}, len(req.Parameters)+len(values))
+		for k, v := range req.Parameters {
+			parameters[k] = v
+		}
+		for k, v := range values {
+			parameters[k] = v
+		}
+
+		options := req.Options
+		options.ProjectID = batch.ProjectID
+		options.BatchID = batch.ID
+		job, err := b.executor.PrepareJob(plugin, parameters, options, batchRunDir(batch, i))
+		if err != nil {
+			return nil, fmt.Errorf("run %d (%s): %w", i+1, describeValues(batch.Varied, values), err)
+		}
+		prepared[i] = job
+	}
+
+	if err := b.queue.db.GetDB().Create(batch).Error; err != nil {
+		return nil, fmt.Errorf("failed to save batch: %w", err)
+	}
+
+	names := make([]string, len(prepared))
+	for i := range prepared {
+		names[i] = fmt.Sprintf("%s %d/%d: %s", batch.Name, i+1, len(prepared), describeValues(batch.Varied, combinations[i]))
+	}
+	if _, err := b.queue.QueuePluginJobs(plugin, names, prepared); err != nil {
+		log.Printf("[ExecutePluginBatch] Failed to queue batch %s: %v", batch.ID, err)
+		b.discard(batch)
+		return nil, fmt.Errorf("failed to queue the runs: %w", err)
+	}
+
+	log.Printf("[ExecutePluginBatch] Queued batch %s with %d runs of %s", batch.ID, len(prepared), batch.PluginID)
+	b.writeIndex(batch.ID)
+	return b.GetBatch(batch.ID)
+}
+
+// discard withdraws a batch whose runs could not be queued. No run was
+// stored, so only the batch and the run folders created for it are removed.
+func (b *BatchService) discard(batch *models.JobBatch) {
+	if err := b.queue.db.GetDB().Delete(&models.JobBatch{}, "id = ?", batch.ID).Error; err != nil {
+		log.Printf("[ExecutePluginBatch] Failed to delete batch %s: %v", batch.ID, err)
+	}
+	if err := os.RemoveAll(batch.OutputDir); err != nil {
+		log.Printf("[ExecutePluginBatch] Failed to remove %s: %v", batch.OutputDir, err)
+	}
+}
+
+// GetBatch returns a batch with its runs and aggregate status.
+func (b *BatchService) GetBatch(id string) (*models.BatchSummary, error) {
+	var batch models.JobBatch
+	if err := b.queue.db.GetDB().Where("id = ?", id).First(&batch).Error; err != nil {
+		return nil, fmt.Errorf("batch not found: %s", id)
+	}
+	return b.summarize(&batch)
+}
+
+// ListBatches returns the batches of a project, newest first. An empty
+// projectID means the active project.
+func (b *BatchService) ListBatches(projectID string) ([]models.BatchSummary, error) {
+	if projectID == "" {
+		projectID = b.queue.Projects().ActiveProjectID()
+	}
+
+	var batches []models.JobBatch
+	if err := b.queue.db.GetDB().Where("project_id = ?", projectID).
+		Order("created_at DESC").Find(&batches).Error; err != nil {
+		return nil, fmt.Errorf("failed to load batches: %w", err)
+	}
+
+	summaries := make([]models.BatchSummary, 0, len(batches))
+	for i := range batches {
+		summary, err := b.summarize(&batches[i])
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, *summary)
+	}
+	return summaries, nil
+}
+
+// CancelBatch cancels every run of a batch that has not finished yet.
+func (b *BatchService) CancelBatch(id string) error {
+	summary, err := b.GetBatch(id)
+	if err != nil {
+		return err
+	}
+	for _, run := range summary.Runs {
+		if isFinishedStatus(run.Status) {
+			continue
+		}
+		if err := b.queue.CancelJob(run.JobID); err != nil {
+			log.Printf("[CancelBatch] Failed to cancel run %s: %v", run.JobID, err)
+		}
+	}
+	return nil
+}
+
+func (b *BatchService) summarize(batch *models.JobBatch) (*models.BatchSummary, error) {
+	var jobs []models.Job
+	if err := b.queue.db.GetDB().Where("batch_id = ?", batch.ID).
+		Order("created_at ASC, id ASC").Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load batch runs: %w", err)
+	}
+
+	jobIDs := make([]string, len(jobs))
+	for i, job := range jobs {
+		jobIDs[i] = job.ID
+	}
+	artifacts, err := b.queue.Artifacts().GetArtifactsForJobs(jobIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load batch outputs: %w", err)
+	}
+
+	summary := &models.BatchSummary{
+		Batch:     *batch,
+		Counts:    make(map[models.JobStatus]int),
+		Total:     len(jobs),
+		Runs:      make([]models.BatchRun, 0, len(jobs)),
+		IndexPath: filepath.Join(batch.OutputDir, batchIndexFile),
+	}
+	for i, job := range jobs {
+		run := models.BatchRun{
+			Index:           i + 1,
+			JobID:           job.ID,
+			Name:            job.Name,
+			Status:          job.Status,
+			Values:          make(map[string]interface{}, len(batch.Varied)),
+			OutputDir:       jobOutputDir(&job),
+			Outputs:         []string{},
+			Error:           job.Error,
+			DurationSeconds: job.DurationSeconds,
+		}
+		for _, name := range batch.Varied {
+			run.Values[name] = job.Parameters[name]
+		}
+		for _, artifact := range artifacts[job.ID] {
+			run.Outputs = append(run.Outputs, artifact.Path)
+		}
+		summary.Counts[job.Status]++
+		summary.Runs = append(summary.Runs, run)
+	}
+	summary.Status = batchStatus(summary.Counts, summary.Total)
+	return summary, nil
+}
+
+// jobFinished schedules a rewrite of the index of the batch a finished job
+// belongs to, which also marks the batch finished once all of its runs are.
+func (b *BatchService) jobFinished(jobID string) {
+	var job models.Job
+	if err := b.queue.db.GetDB().Unscoped().Select("batch_id").Where("id = ?", jobID).First(&job).Error; err != nil {
+		return
+	}
+	if job.BatchID != "" {
+		b.scheduleIndex(job.BatchID)
+	}
+}
+
+// scheduleIndex rewrites the index of a batch after batchIndexDelay, away
+// from the worker that finished a run. Runs that finish in the meantime share
+// the rewrite, so a batch of many short runs is not reloaded for every one.
+func (b *BatchService) scheduleIndex(batchID string) {
+	b.pendingMu.Lock()
+	defer b.pendingMu.Unlock()
+	if b.pendingIndexes[batchID] {
+		return
+	}
+	b.pendingIndexes[batchID] = true
+	time.AfterFunc(batchIndexDelay, func() {
+		b.pendingMu.Lock()
+		delete(b.pendingIndexes, batchID)
+		b.pendingMu.Unlock()
+		b.writeIndex(batchID)
+	})
+}
+
+// writeIndex writes index.tsv to the batch directory: one row per run with
+// its status, the values that vary, its output folder and its output files.
+// When the last run has finished it also marks the batch finished and calls
+// the finished hooks, exactly once per batch.
+func (b *BatchService) writeIndex(batchID string) {
+	b.indexMu.Lock()
+	defer b.indexMu.Unlock()
+
+	summary, err := b.GetBatch(batchID)
+	if err != nil {
+		log.Printf("[writeIndex] %v", err)
+		return
+	}
+	if b.markFinished(summary) {
+		for _, hook := range b.finishedHooks {
+			hook(summary)
+		}
+	}
+
+	header := append([]string{"run", "job_id", "status"}, summary.Batch.Varied...)
+	header = append(header, "output_dir", "outputs", "error")
+	lines := []string{strings.Join(header, "\t")}
+	for _, run := range summary.Runs {
+		row := []string{fmt.Sprint(run.Index), run.JobID, string(run.Status)}
+		for _, name := range summary.Batch.Varied {
+			row = append(row, fmt.Sprint(run.Values[name]))
+		}
+		row = append(row, run.OutputDir, strings.Join(run.Outputs, ","), run.Error)
+		for i, cell := range row {
+			row[i] = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(cell)
+		}
+		lines = append(lines, strings.Join(row, "\t"))
+	}
+
+	if err := os.MkdirAll(summary.Batch.OutputDir, 0755); err != nil {
+		log.Printf("[writeIndex] Failed to create batch directory: %v", err)
+		return
+	}
+	tmp := summary.IndexPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		log.Printf("[writeIndex] Failed to write index of batch %s: %v", batchID, err)
+		return
+	}
+	if err := os.Rename(tmp, summary.IndexPath); err != nil {
+		log.Printf("[writeIndex] Failed to write index of batch %s: %v", batchID, err)
+	}
+}
+
+// markFinished records when a batch whose runs have all been queued and
+// finished did so. It reports whether this call was the one that did.
+func (b *BatchService) markFinished(summary *models.BatchSummary) bool {
+	if summary.Batch.FinishedAt != nil || summary.Total < summary.Batch.RunCount {
+		return false
+	}
+	for _, run := range summary.Runs {
+		if !isFinishedStatus(run.Status) {
+			return false
+		}
+	}
+
+	now := time.Now()
+	result := b.queue.db.GetDB().Model(&models.JobBatch{}).
+		Where("id = ? AND finished_at IS NULL", summary.Batch.ID).
+		Update("finished_at", now)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false
+	}
+	summary.Batch.FinishedAt = &now
+	return true
+}
+
+func batchDimensions(req models.BatchExecutionRequest) ([]batchDimension, error) {
+	var dimensions []batchDimension
+	if len(req.Files) > 0 {
+		if req.FileInput == "" {
+			return nil, fmt.Errorf("files were given without the file input they are for")
+		}
+		values := make([]interface{}, len(req.Files))
+		for i, file := range req.Files {
+			values[i] = file
+		}
+		dimensions = append(dimensions, batchDimension{name: req.FileInput, values: values})
+	}
+
+	names := make([]string, 0, len(req.Sweep))
+	for name := range req.Sweep {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == req.FileInput && len(req.Files) > 0 {
+			return nil, fmt.Errorf("%s is given both as the file input and as a sweep", name)
+		}
+		if len(req.Sweep[name]) == 0 {
+			return nil, fmt.Errorf("sweep over %s has no values", name)
+		}
+		dimensions = append(dimensions, batchDimension{name: name, values: req.Sweep[name]})
+	}
+
+	if len(dimensions) == 0 {
+		return nil, fmt.Errorf("a batch needs files or parameter values to fan out over")
+	}
+	return dimensions, nil
+}
+
+// expandBatch turns the dimensions into the parameter values of each run.
+func expandBatch(dimensions []batchDimension, mode string) ([]map[string]interface{}, error) {
+	switch mode {
+	case models.BatchModeZip:
+		count := len(dimensions[0].values)
+		for _, dimension := range dimensions[1:] {
+			if len(dimension.values) != count {
+				return nil, fmt.Errorf("zipped lists must have the same length: %s has %d values, %s has %d",
+					dimensions[0].name, count, dimension.name, len(dimension.values))
+			}
+		}
+		if count > maxBatchRuns {
+			return nil, fmt.Errorf("batch expands to %d runs, more than the limit of %d", count, maxBatchRuns)
+		}
+		runs := make([]map[string]interface{}, count)
+		for i := range runs {
+			runs[i] = make(map[string]interface{}, len(dimensions))
+			for _, dimension := range dimensions {
+				runs[i][dimension.name] = dimension.values[i]
+			}
+		}
+		return runs, nil
+
+	case models.BatchModeCartesian:
+		count := 1
+		for _, dimension := range dimensions {
+			count *= len(dimension.values)
+			if count > maxBatchRuns {
+				return nil, fmt.Errorf("batch expands to more than the limit of %d runs", maxBatchRuns)
+			}
+		}
+		runs := []map[string]interface{}{{}}
+		for _, dimension := range dimensions {
+			expanded := make([]map[string]interface{}, 0, len(runs)*len(dimension.values))
+			for _, run := range runs {
+				for _, value := range dimension.values {
+					next := make(map[string]interface{}, len(run)+1)
+					for k, v := range run {
+						next[k] = v
+					}
+					next[dimension.name] = value
+					expanded = append(expanded, next)
+				}
+			}
+			runs = expanded
+		}
+		return runs, nil
+	}
+	return nil, fmt.Errorf("unknown batch mode: %s", mode)
+}
+
+// batchStatus aggregates the statuses of a batch's runs.
+func batchStatus(counts map[models.JobStatus]int, total int) models.JobStatus {
+	if total == 0 {
+		return models.JobStatusCompleted
+	}
+	finished := 0
+	for _, status := range finishedJobStatuses {
+		finished += counts[status]
+	}
+	switch {
+	case counts[models.JobStatusCompleted] == total:
+		return models.JobStatusCompleted
+	case finished == total:
+		return models.JobStatusFailed
+	case finished > 0 || counts[models.JobStatusInProgress] > 0:
+		return models.JobStatusInProgress
+	}
+	return models.JobStatusPending
+}
+
+func isFinishedStatus(status models.JobStatus) bool {
+	for _, finished := range finishedJobStatuses {
+		if status == finished {
+			return true
+		}
+	}
+	return false
+}
+
+func batchRunDir(batch *models.JobBatch, index int) string {
+	return filepath.Join(batch.OutputDir, fmt.Sprintf("run_%03d", index+1))
+}
+
+// describeValues names a run by its varied values, using the file name for
+// paths.
+func describeValues(names []string, values map[string]interface{}) string {
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := fmt.Sprint(values[name])
+		if filepath.IsAbs(value) {
+			value = filepath.Base(value)
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", name, value))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"gorm.io/gorm"
+)
+
+func newTestBatchService(t *testing.T) (*BatchService, *JobQueueService, *DatabaseService) {
+	jobQueue, db := newTestJobQueue(t)
+	threshold := 1.0
+	loader := &PluginLoaderV2{plugins: map[string]*models.PluginV2{
+		"sweep": {
+			ScriptPath: "sweep.R",
+			Definition: models.PluginDefinition{
+				Plugin:  models.PluginMetadata{ID: "sweep", Name: "Sweep"},
+				Runtime: models.PluginRuntimeV2{Type: "r"},
+				Inputs: []models.PluginInputV2{
+					{Name: "input", Type: models.PluginInputTypeFile, Required: true},
+					{Name: "alpha", Type: models.PluginInputTypeNumber, Max: &threshold},
+					{Name: "method", Type: models.PluginInputTypeText},
+				},
+				Execution: models.PluginExecution{ArgsMapping: map[string]interface{}{
+					"input":  map[string]interface{}{"flag": "--input"},
+					"alpha":  map[string]interface{}{"flag": "--alpha"},
+					"method": map[string]interface{}{"flag": "--method"},
+				}},
+			},
+		},
+	}}
+	return NewBatchService(jobQueue, loader, NewPluginExecutor()), jobQueue, db
+}
+
+func TestExecutePluginBatchCartesian(t *testing.T) {
+	batches, _, db := newTestBatchService(t)
+	dir := t.TempDir()
+	finished := make(chan *models.BatchSummary, 4)
+	batches.OnBatchFinished(func(summary *models.BatchSummary) { finished <- summary })
+
+	summary, err := batches.ExecutePluginBatch(models.BatchExecutionRequest{
+		Name:      "Grid",
+		PluginID:  "sweep",
+		FileInput: "input",
+		Files:     []string{"/data/a.tsv", "/data/b.tsv"},
+		Sweep:     map[string][]interface{}{"alpha": {0.01, 0.05}, "method": {"bh"}},
+	}, dir)
+	if err != nil {
+		t.Fatalf("ExecutePluginBatch failed: %v", err)
+	}
+	if summary.Total != 4 || len(summary.Runs) != 4 {
+		t.Fatalf("Expected four runs, got %+v", summary)
+	}
+	first := summary.Runs[0]
+	if first.Values["input"] != "/data/a.tsv" || first.Values["alpha"] != 0.01 || first.Values["method"] != "bh" {
+		t.Errorf("Unexpected values for the first run: %+v", first.Values)
+	}
+	if !strings.HasSuffix(first.OutputDir, "run_001") {
+		t.Errorf("Expected the first run to write to run_001, got %s", first.OutputDir)
+	}
+
+	for _, run := range summary.Runs {
+		waitForJobStatus(t, db, run.JobID, models.JobStatusFailed)
+	}
+	summary, err = batches.GetBatch(summary.Batch.ID)
+	if err != nil || summary.Status != models.JobStatusFailed || summary.Counts[models.JobStatusFailed] != 4 {
+		t.Fatalf("Expected the batch to have failed, got %+v (%v)", summary, err)
+	}
+
+	var index string
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		content, _ := os.ReadFile(filepath.Join(summary.Batch.OutputDir, batchIndexFile))
+		index = string(content)
+		if strings.Count(index, "\tfailed\t") == 4 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	lines := strings.Split(strings.TrimSpace(index), "\n")
+	if len(lines) != 5 || lines[0] != "run\tjob_id\tstatus\tinput\talpha\tmethod\toutput_dir\toutputs\terror" {
+		t.Fatalf("Unexpected index:\n%s", index)
+	}
+	if strings.Count(index, "\tfailed\t") != 4 {
+		t.Errorf("Expected every run to be failed in the index:\n%s", index)
+	}
+
+	select {
+	case done := <-finished:
+		if done.Status != models.JobStatusFailed || done.Batch.FinishedAt == nil {
+			t.Errorf("Expected the finished hook to get the failed batch, got %+v", done)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the batch finished hook to be called")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if len(finished) != 0 {
+		t.Errorf("Expected the batch finished hook to be called once, got %d more calls", len(finished))
+	}
+}
+
+func TestExecutePluginBatchRejectsBeforeQueueing(t *testing.T) {
+	batches, _, db := newTestBatchService(t)
+	dir := t.TempDir()
+
+	_, err := batches.ExecutePluginBatch(models.BatchExecutionRequest{
+		PluginID: "sweep",
+		Mode:     models.BatchModeZip,
+		Sweep:    map[string][]interface{}{"input": {"/data/a.tsv", "/data/b.tsv"}, "alpha": {0.5}},
+	}, dir)
+	if err == nil || !strings.Contains(err.Error(), "same length") {
+		t.Errorf("Expected zipped lists of different lengths to fail, got %v", err)
+	}
+
+	_, err = batches.ExecutePluginBatch(models.BatchExecutionRequest{
+		PluginID: "sweep",
+		Mode:     models.BatchModeZip,
+		Sweep:    map[string][]interface{}{"input": {"/data/a.tsv", "/data/b.tsv"}, "alpha": {0.5, 2.0}},
+	}, dir)
+	if err == nil || !strings.Contains(err.Error(), "run 2") {
+		t.Errorf("Expected the out of range run to be reported, got %v", err)
+	}
+
+	var count int64
+	db.GetDB().Model(&models.Job{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected no jobs to be queued for a rejected batch, got %d", count)
+	}
+}
+
+func TestExecutePluginBatchWithdrawsOnQueueFailure(t *testing.T) {
+	batches, jobQueue, db := newTestBatchService(t)
+	dir := t.TempDir()
+	finished := make(chan string, 3)
+	jobQueue.OnJobFinished(func(jobID string) { finished <- jobID })
+
+	created := 0
+	db.GetDB().Callback().Create().Before("gorm:create").Register("fail_second_run", func(tx *gorm.DB) {
+		if tx.Statement.Table != "jobs" {
+			return
+		}
+		created++
+		if created == 2 {
+			tx.AddError(errors.New("disk full"))
+		}
+	})
+
+	_, err := batches.ExecutePluginBatch(models.BatchExecutionRequest{
+		PluginID:  "sweep",
+		FileInput: "input",
+		Files:     []string{"/data/a.tsv", "/data/b.tsv", "/data/c.tsv"},
+	}, dir)
+	if err == nil || !strings.Contains(err.Error(), "2/3") || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("Expected queueing the second run to fail, got %v", err)
+	}
+
+	var jobs, batchRows int64
+	db.GetDB().Unscoped().Model(&models.Job{}).Count(&jobs)
+	db.GetDB().Model(&models.JobBatch{}).Count(&batchRows)
+	if jobs != 0 || batchRows != 0 {
+		t.Errorf("Expected the batch and its first run to be withdrawn, got %d jobs and %d batches", jobs, batchRows)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected the batch directory to be removed, found %d entries", len(entries))
+	}
+	time.Sleep(100 * time.Millisecond)
+	if len(finished) != 0 {
+		t.Errorf("Expected no run of a withdrawn batch to finish, got %d", len(finished))
+	}
+}
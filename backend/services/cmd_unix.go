@@ -4,8 +4,76 @@
 package services
 
 import (
+	"os"
 	"os/exec"
+	"syscall"
+	"time"
 )
 
+// exitStatusScript runs its arguments once a line arrives on stdin and writes
+// their exit status to the file named by its first argument. A command killed
+// by a signal has the signal raised again, so whoever waits for the script
+// sees the same status as without it.
+const exitStatusScript = `status_file=$1
+shift
+read -r _
+"$@" </dev/null
+status=$?
+echo "$status" >"$status_file.tmp" && mv "$status_file.tmp" "$status_file"
+if [ "$status" -gt 128 ]; then kill -$((status - 128)) $$ 2>/dev/null; fi
+exit "$status"`
+
 func hideConsoleWindow(cmd *exec.Cmd) {
 }
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func terminateProcessTree(pid int, grace time.Duration, done <-chan struct{}) {
+	syscall.Kill(-pid, syscall.SIGTERM)
+
+	select {
+	case <-done:
+	case <-time.After(grace):
+	}
+
+	syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// recordExitStatus wraps cmd in a shell that writes its exit status to path,
+// so the status outlives the app if the app exits first. The command only
+// starts once the returned function is called, so rlimits set on the wrapper
+// in the meantime are inherited by it. The function must be called once
+// cmd.Start has returned, whether or not it succeeded.
+func recordExitStatus(cmd *exec.Cmd, path string) (func(), error) {
+	os.Remove(path)
+	if cmd.Err != nil {
+		return func() {}, nil
+	}
+
+	ready, proceed, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Args = append([]string{"sh", "-c", exitStatusScript, "sh", path, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = "/bin/sh"
+	cmd.Stdin = ready
+	return func() {
+		ready.Close()
+		proceed.Write([]byte("\n"))
+		proceed.Close()
+	}, nil
+}
+
+// watchExitCode returns a function that reports the exit code of a process
+// this session did not start. Other processes' exit codes cannot be read on
+// Unix, where they are collected through recordExitStatus instead.
+func watchExitCode(pid int, startTime int64) func() (int, bool) {
+	return func() (int, bool) {
+		return 0, false
+	}
+}
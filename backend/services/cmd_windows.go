@@ -4,13 +4,48 @@
 package services
 
 import (
+	"os"
 	"os/exec"
+	"strconv"
 	"syscall"
+	"time"
 )
 
+const createNewProcessGroup = 0x00000200
+
 func hideConsoleWindow(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		HideWindow:    true,
 		CreationFlags: 0x08000000,
 	}
 }
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= createNewProcessGroup
+}
+
+func terminateProcessTree(pid int, grace time.Duration, done <-chan struct{}) {
+	taskkill := exec.Command("taskkill", "/T", "/PID", strconv.Itoa(pid))
+	hideConsoleWindow(taskkill)
+	taskkill.Run()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(grace):
+	}
+
+	taskkill = exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid))
+	hideConsoleWindow(taskkill)
+	taskkill.Run()
+}
+
+// recordExitStatus leaves cmd as it is on Windows. A re-attached process's
+// exit code is read through a handle to it instead, see watchExitCode.
+func recordExitStatus(cmd *exec.Cmd, path string) (func(), error) {
+	os.Remove(path)
+	return func() {}, nil
+}
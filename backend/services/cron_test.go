@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 7, 30, 0, time.UTC) // a Friday
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.March, 15, 10, 15, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, time.March, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 22 * * 1-5", time.Date(2024, time.March, 15, 22, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1", time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 * 0", time.Date(2024, time.March, 17, 12, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := parseCronSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("parseCronSchedule(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		next, err := schedule.Next(base)
+		if err != nil {
+			t.Errorf("Next for %q failed: %v", tt.expr, err)
+			continue
+		}
+		if !next.Equal(tt.expected) {
+			t.Errorf("Next for %q: expected %s, got %s", tt.expr, tt.expected, next)
+		}
+	}
+}
+
+func TestCronScheduleRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "0 0 31 2 *", "a * * * *"} {
+		if _, err := parseCronSchedule(expr); err == nil {
+			t.Errorf("Expected an error for %q", expr)
+		}
+	}
+}
@@ -26,6 +26,7 @@ type Setting struct {
 
 type ImportedFile struct {
 	ID         uint   `gorm:"primaryKey"`
+	ProjectID  string `gorm:"index"`
 	Name       string `gorm:"not null"`
 	Path       string `gorm:"not null"`
 	Size       int64  `gorm:"not null"`
@@ -122,14 +123,61 @@ func NewDatabaseService(ctx context.Context) (*DatabaseService, error) {
 }
 
 func (d *DatabaseService) autoMigrate() error {
-	return d.db.AutoMigrate(
+	if err := d.db.AutoMigrate(
 		&Setting{},
 		&ImportedFile{},
 		&VirtualEnvironment{},
 		&PythonEnvironmentDB{},
 		&REnvironmentDB{},
 		&models.Job{},
-	)
+		&models.JobLogLine{},
+		&models.JobArtifact{},
+		&models.Project{},
+		&models.JobBatch{},
+		&models.SampleAnnotation{},
+	); err != nil {
+		return err
+	}
+	if err := d.migrateProjects(); err != nil {
+		return err
+	}
+	return d.migrateJobDurations()
+}
+
+// migrateProjects creates the default project and assigns it everything
+// recorded before projects existed.
+func (d *DatabaseService) migrateProjects() error {
+	project := models.Project{ID: models.DefaultProjectID, Name: "Default"}
+	if err := d.db.Where("id = ?", project.ID).FirstOrCreate(&project).Error; err != nil {
+		return err
+	}
+	for _, table := range []interface{}{&models.Job{}, &ImportedFile{}} {
+		if err := d.db.Model(table).Where("project_id IS NULL OR project_id = ''").
+			Update("project_id", models.DefaultProjectID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateJobDurations fills in the duration of jobs that finished before it
+// was recorded.
+func (d *DatabaseService) migrateJobDurations() error {
+	var jobs []models.Job
+	err := d.db.Select("id", "started_at", "completed_at").
+		Where("duration_seconds IS NULL AND started_at IS NOT NULL AND completed_at IS NOT NULL").
+		Find(&jobs).Error
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		duration := jobs[i].CompletedAt.Sub(*jobs[i].StartedAt).Seconds()
+		if err := d.db.Model(&models.Job{}).Where("id = ?", jobs[i].ID).
+			UpdateColumn("duration_seconds", duration).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (d *DatabaseService) GetDB() *gorm.DB {
@@ -177,9 +225,9 @@ func (d *DatabaseService) SaveImportedFile(file *ImportedFile) error {
 	return d.db.Create(file).Error
 }
 
-func (d *DatabaseService) GetImportedFiles() ([]ImportedFile, error) {
+func (d *DatabaseService) GetImportedFiles(projectID string) ([]ImportedFile, error) {
 	var files []ImportedFile
-	err := d.db.Order("imported_at DESC").Find(&files).Error
+	err := d.db.Where("project_id = ?", projectID).Order("imported_at DESC").Find(&files).Error
 	return files, err
 }
 
@@ -63,6 +63,7 @@ func TestDatabaseCreation(t *testing.T) {
 	// Test ImportedFile table
 	t.Run("ImportedFile Table", func(t *testing.T) {
 		file := &ImportedFile{
+			ProjectID:  models.DefaultProjectID,
 			Name:       "test.csv",
 			Path:       "/tmp/test.csv",
 			Size:       1024,
@@ -86,7 +87,7 @@ func TestDatabaseCreation(t *testing.T) {
 			t.Errorf("Expected 'test.csv', got '%s'", retrieved.Name)
 		}
 
-		files, err := db.GetImportedFiles()
+		files, err := db.GetImportedFiles(models.DefaultProjectID)
 		if err != nil {
 			t.Fatalf("Failed to get imported files: %v", err)
 		}
@@ -1,11 +1,13 @@
 package services
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
 )
 
 type DirectRunner struct {
@@ -25,7 +27,8 @@ func NewDirectRunner() *DirectRunner {
 	}
 }
 
-func (d *DirectRunner) ExecuteProgram(programPath string, args []string, workingDir string, outputCallback func(line string)) error {
+// Argv returns the command line ExecuteProgram runs for a program.
+func (d *DirectRunner) Argv(programPath string, args []string) []string {
 	var executablePath string
 
 	if filepath.IsAbs(programPath) {
@@ -47,46 +50,18 @@ func (d *DirectRunner) ExecuteProgram(programPath string, args []string, working
 		}
 	}
 
-	cmd := exec.Command(executablePath, args...)
-	hideConsoleWindow(cmd)
+	return append([]string{executablePath}, args...)
+}
+
+func (d *DirectRunner) ExecuteProgram(ctx context.Context, programPath string, args []string, workingDir string, limits models.ExecutionLimits, outputCallback func(stream string, line string)) error {
+	argv := d.Argv(programPath, args)
+	cmd := exec.Command(argv[0], argv[1:]...)
 
 	if workingDir != "" {
 		cmd.Dir = workingDir
 	}
 
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return err
-	}
-
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return err
-	}
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start program %s: %v", programPath, err)
-	}
-
-	go func() {
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			if outputCallback != nil {
-				outputCallback(scanner.Text())
-			}
-		}
-	}()
-
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			if outputCallback != nil {
-				outputCallback("[ERROR] " + scanner.Text())
-			}
-		}
-	}()
-
-	return cmd.Wait()
+	return runProcess(ctx, cmd, limits, outputCallback)
 }
 
 func (d *DirectRunner) ValidateProgram(programName string) error {
@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 )
@@ -698,6 +699,28 @@ func (e *EnvironmentService) ListRPackages(rPath string) ([]string, error) {
 	return packages, nil
 }
 
+// ListRPackageVersions lists installed R packages as name==version.
+func (e *EnvironmentService) ListRPackageVersions(rPath string) ([]string, error) {
+	listCmd := "ip <- installed.packages(); cat(paste(ip[,1], ip[,3], sep='=='), sep='\\n')"
+	cmd := exec.Command(rPath, "-e", listCmd)
+	hideConsoleWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		log.Printf("[ListRPackageVersions] ERROR: %v\n", err)
+		return nil, err
+	}
+
+	var packages []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, "==") {
+			packages = append(packages, line)
+		}
+	}
+	sort.Strings(packages)
+	return packages, nil
+}
+
 func (e *EnvironmentService) CreatePythonVirtualEnv(basePythonPath string, venvPath string) error {
 	log.Printf("[CreatePythonVirtualEnv] Creating virtual environment at %s using %s\n", venvPath, basePythonPath)
 
@@ -5,15 +5,19 @@ import (
 	"bufio"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/noatgnu/cauldron-go/backend/models"
 	"github.com/ulikunitz/xz"
@@ -160,7 +164,10 @@ func (f *FileService) ExtractTarGz(archivePath string, destPath string) error {
 			return err
 		}
 
-		target := filepath.Join(destPath, header.Name)
+		target, err := archiveEntryPath(destPath, header.Name)
+		if err != nil {
+			return err
+		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
@@ -235,7 +242,10 @@ func (f *FileService) ExtractTarXz(archivePath string, destPath string) error {
 			return err
 		}
 
-		target := filepath.Join(destPath, header.Name)
+		target, err := archiveEntryPath(destPath, header.Name)
+		if err != nil {
+			return err
+		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
@@ -275,6 +285,129 @@ func (f *FileService) ExtractTarXz(archivePath string, destPath string) error {
 	return nil
 }
 
+// archiveEntryPath resolves an archive entry below destPath, rejecting names
+// that would escape it.
+func archiveEntryPath(destPath string, name string) (string, error) {
+	target := filepath.Join(destPath, name)
+	rel, err := filepath.Rel(destPath, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry %q escapes the destination directory", name)
+	}
+	return target, nil
+}
+
+// TarXzWriter writes a .tar.xz archive one entry at a time. Parent
+// directories are added before the first file inside them so the archive
+// can be unpacked with ExtractTarXz.
+type TarXzWriter struct {
+	file *os.File
+	xz   *xz.Writer
+	tar  *tar.Writer
+	dirs map[string]bool
+}
+
+func (f *FileService) CreateTarXz(archivePath string) (*TarXzWriter, error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	xzWriter, err := xz.NewWriter(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &TarXzWriter{
+		file: file,
+		xz:   xzWriter,
+		tar:  tar.NewWriter(xzWriter),
+		dirs: make(map[string]bool),
+	}, nil
+}
+
+func (w *TarXzWriter) addParentDirs(name string) error {
+	dir := path.Dir(name)
+	if dir == "." || dir == "/" || w.dirs[dir] {
+		return nil
+	}
+	if err := w.addParentDirs(dir); err != nil {
+		return err
+	}
+	w.dirs[dir] = true
+	return w.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     dir + "/",
+		Mode:     0755,
+		ModTime:  time.Now(),
+	})
+}
+
+// AddBytes stores data under name, a slash separated path in the archive.
+func (w *TarXzWriter) AddBytes(name string, data []byte) error {
+	if err := w.addParentDirs(name); err != nil {
+		return err
+	}
+	if err := w.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := w.tar.Write(data)
+	return err
+}
+
+// AddFile copies the file at srcPath into the archive under name and returns
+// the SHA-256 of what was written.
+func (w *TarXzWriter) AddFile(name string, srcPath string) (string, error) {
+	file, err := os.Open(srcPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if err := w.addParentDirs(name); err != nil {
+		return "", err
+	}
+	if err := w.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+	}); err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	if _, err := io.CopyN(io.MultiWriter(w.tar, hash), file, info.Size()); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (w *TarXzWriter) Close() error {
+	tarErr := w.tar.Close()
+	xzErr := w.xz.Close()
+	fileErr := w.file.Close()
+	if tarErr != nil {
+		return tarErr
+	}
+	if xzErr != nil {
+		return xzErr
+	}
+	return fileErr
+}
+
 func (f *FileService) DownloadFile(url string, destPath string) error {
 	resp, err := http.Get(url)
 	if err != nil {
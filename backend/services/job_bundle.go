@@ -0,0 +1,382 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const (
+	JobBundleExtension    = ".cauldron.tar.xz"
+	bundleManifestFile    = "manifest.json"
+	bundleLogFile         = "log.txt"
+	bundlePackagesFile    = "environment/packages.txt"
+	bundleOutputsDir      = "outputs"
+	bundlePluginDir       = "plugin"
+	bundleScriptDir       = "script"
+	bundleLogPageSize     = 5000
+	bundleNameLengthLimit = 40
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// JobBundleService exports finished jobs as self-describing archives that
+// record everything needed to reproduce them, and imports them again.
+type JobBundleService struct {
+	queue    *JobQueueService
+	plugins  *PluginLoaderV2
+	env      *EnvironmentService
+	files    *FileService
+	settings *SettingsService
+}
+
+func NewJobBundleService(queue *JobQueueService, plugins *PluginLoaderV2, env *EnvironmentService, files *FileService, settings *SettingsService) *JobBundleService {
+	return &JobBundleService{
+		queue:    queue,
+		plugins:  plugins,
+		env:      env,
+		files:    files,
+		settings: settings,
+	}
+}
+
+// ExportJobBundle writes the bundle of a finished job to destPath, or to a
+// file named after the job when destPath is a directory, and returns the
+// path written.
+func (b *JobBundleService) ExportJobBundle(jobID string, destPath string) (string, error) {
+	job, err := b.queue.GetJob(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	b.queue.mu.RLock()
+	snapshot := *job
+	b.queue.mu.RUnlock()
+
+	switch snapshot.Status {
+	case models.JobStatusCompleted, models.JobStatusFailed, models.JobStatusCancelled:
+	default:
+		return "", fmt.Errorf("job %s has not finished (status: %s)", jobID, snapshot.Status)
+	}
+
+	if info, err := os.Stat(destPath); err == nil && info.IsDir() {
+		destPath = filepath.Join(destPath, bundleFileName(&snapshot))
+	}
+
+	archive, err := b.files.CreateTarXz(destPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	manifest, err := b.writeBundle(archive, &snapshot)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(destPath)
+		return "", fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	log.Printf("[ExportJobBundle] Wrote bundle of job %s to %s (%d inputs, %d outputs)",
+		jobID, destPath, len(manifest.Inputs), len(manifest.Outputs))
+	return destPath, nil
+}
+
+func (b *JobBundleService) writeBundle(archive *TarXzWriter, job *models.Job) (*models.JobBundleManifest, error) {
+	manifest := &models.JobBundleManifest{
+		FormatVersion: models.JobBundleFormatVersion,
+		CreatedAt:     time.Now(),
+		Job:           *job,
+		Argv:          b.argv(job),
+		Parameters:    job.Parameters,
+		Inputs:        bundleInputs(job.Parameters),
+	}
+
+	if plugin := b.pluginFor(job); plugin != nil {
+		bundled, err := b.addPlugin(archive, plugin)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Plugin = bundled
+	} else if script := scriptPath(job, manifest.Argv); script != "" {
+		file := models.BundleFile{Path: script}
+		if _, err := os.Stat(script); err != nil {
+			file.Missing = true
+		} else {
+			file.ArchivePath = bundleScriptDir + "/" + filepath.Base(script)
+			if file.SHA256, err = archive.AddFile(file.ArchivePath, script); err != nil {
+				return nil, err
+			}
+		}
+		manifest.Script = &file
+	}
+
+	manifest.Environment = b.environment(job, manifest.Argv)
+	if len(manifest.Environment.Packages) > 0 {
+		packages := strings.Join(manifest.Environment.Packages, "\n") + "\n"
+		if err := archive.AddBytes(bundlePackagesFile, []byte(packages)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := b.addLog(archive, job.ID); err != nil {
+		return nil, err
+	}
+	manifest.LogFile = bundleLogFile
+
+	outputs, err := b.addOutputs(archive, job)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Outputs = outputs
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := archive.AddBytes(bundleManifestFile, data); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// argv reconstructs the command line the job's runner executes.
+func (b *JobBundleService) argv(job *models.Job) []string {
+	if len(job.Args) == 0 {
+		return nil
+	}
+
+	if command, err := b.queue.resolveExecution(job); err == nil {
+		return command.Argv
+	}
+
+	return append([]string{job.Command}, job.Args...)
+}
+
+func (b *JobBundleService) pluginFor(job *models.Job) *models.PluginV2 {
+	pluginID, _ := job.Parameters["pluginId"].(string)
+	if pluginID == "" || b.plugins == nil {
+		return nil
+	}
+	plugin, err := b.plugins.GetPlugin(pluginID)
+	if err != nil {
+		return nil
+	}
+	return plugin
+}
+
+func (b *JobBundleService) addPlugin(archive *TarXzWriter, plugin *models.PluginV2) (*models.BundlePlugin, error) {
+	definitionPath := b.plugins.getPlatformSpecificConfig(plugin.FolderPath)
+
+	bundled := &models.BundlePlugin{
+		ID:             plugin.Definition.Plugin.ID,
+		Name:           plugin.Definition.Plugin.Name,
+		Version:        plugin.Definition.Plugin.Version,
+		DefinitionFile: bundlePluginDir + "/" + filepath.Base(definitionPath),
+		ScriptFile:     bundlePluginDir + "/" + filepath.ToSlash(plugin.Definition.Runtime.Script),
+	}
+
+	var err error
+	if bundled.DefinitionSHA256, err = archive.AddFile(bundled.DefinitionFile, definitionPath); err != nil {
+		return nil, fmt.Errorf("failed to add plugin definition: %w", err)
+	}
+	if bundled.ScriptSHA256, err = archive.AddFile(bundled.ScriptFile, plugin.ScriptPath); err != nil {
+		return nil, fmt.Errorf("failed to add plugin script: %w", err)
+	}
+	return bundled, nil
+}
+
+func (b *JobBundleService) environment(job *models.Job, argv []string) models.BundleEnvironment {
+	env := models.BundleEnvironment{Runtime: job.Command}
+
+	switch job.Command {
+	case "r":
+		env.EnvPath, env.EnvType = job.REnvPath, job.REnvType
+	case "direct":
+	default:
+		env.Runtime = "python"
+		env.EnvPath, env.EnvType = job.PythonEnvPath, job.PythonEnvType
+	}
+
+	if len(argv) == 0 || env.Runtime == "direct" {
+		return env
+	}
+	env.Interpreter = argv[0]
+
+	if b.env == nil {
+		return env
+	}
+
+	var err error
+	if env.Runtime == "r" {
+		env.Packages, err = b.env.ListRPackageVersions(env.Interpreter)
+	} else {
+		env.Packages, err = b.env.ListPythonPackages(env.Interpreter)
+	}
+	if err != nil {
+		env.Error = fmt.Sprintf("failed to list packages: %v", err)
+	}
+	sort.Strings(env.Packages)
+	return env
+}
+
+// addLog stores the job log as one "timestamp stream line" entry per line.
+func (b *JobBundleService) addLog(archive *TarXzWriter, jobID string) error {
+	var buf bytes.Buffer
+	for offset := 0; ; offset += bundleLogPageSize {
+		page, err := b.queue.logs.GetJobLog(jobID, offset, bundleLogPageSize, "")
+		if err != nil {
+			return fmt.Errorf("failed to read job log: %w", err)
+		}
+		for _, line := range page.Lines {
+			fmt.Fprintf(&buf, "%s %s %s\n", line.Timestamp.Format(time.RFC3339Nano), line.Stream, line.Line)
+		}
+		if len(page.Lines) < bundleLogPageSize {
+			break
+		}
+	}
+	return archive.AddBytes(bundleLogFile, buf.Bytes())
+}
+
+func (b *JobBundleService) addOutputs(archive *TarXzWriter, job *models.Job) ([]models.BundleFile, error) {
+	outputs := []models.BundleFile{}
+	if job.OutputPath == "" {
+		return outputs, nil
+	}
+
+	artifacts, err := b.queue.artifacts.GetJobArtifacts(job.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts) == 0 {
+		if info, statErr := os.Stat(job.OutputPath); statErr == nil && info.IsDir() {
+			scan, err := b.queue.artifacts.ScanJobOutputs(job.ID, job.OutputPath, job.DeclaredOutputs)
+			if err != nil {
+				return nil, err
+			}
+			artifacts = scan.Artifacts
+		}
+	}
+
+	for _, artifact := range artifacts {
+		srcPath := filepath.Join(job.OutputPath, filepath.FromSlash(artifact.Path))
+		file := models.BundleFile{
+			Name: artifact.OutputName,
+			Path: artifact.Path,
+			Size: artifact.Size,
+		}
+		if info, err := os.Stat(srcPath); err != nil {
+			file.Missing = true
+			file.SHA256 = artifact.SHA256
+		} else {
+			file.Size = info.Size()
+			file.ArchivePath = bundleOutputsDir + "/" + artifact.Path
+			if file.SHA256, err = archive.AddFile(file.ArchivePath, srcPath); err != nil {
+				return nil, fmt.Errorf("failed to add output %s: %w", artifact.Path, err)
+			}
+		}
+		outputs = append(outputs, file)
+	}
+	return outputs, nil
+}
+
+// bundleInputs records every parameter that names an existing file, and
+// absolute paths that no longer exist, by checksum. Input contents are not
+// copied into the bundle.
+func bundleInputs(parameters map[string]interface{}) []models.BundleFile {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		if key != "outputDir" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	inputs := []models.BundleFile{}
+	for _, key := range keys {
+		for _, value := range parameterPaths(parameters[key]) {
+			info, err := os.Stat(value)
+			if err != nil {
+				if filepath.IsAbs(value) {
+					inputs = append(inputs, models.BundleFile{Name: key, Path: value, Missing: true})
+				}
+				continue
+			}
+			if !info.Mode().IsRegular() {
+				continue
+			}
+
+			checksum, _, err := hashFile(value)
+			if err != nil {
+				log.Printf("[bundleInputs] Failed to hash %s: %v", value, err)
+				continue
+			}
+			inputs = append(inputs, models.BundleFile{
+				Name:   key,
+				Path:   value,
+				Size:   info.Size(),
+				SHA256: checksum,
+			})
+		}
+	}
+	return inputs
+}
+
+func parameterPaths(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []interface{}:
+		var paths []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				paths = append(paths, s)
+			}
+		}
+		return paths
+	case []string:
+		return v
+	}
+	return nil
+}
+
+func scriptPath(job *models.Job, argv []string) string {
+	if job.Command == "direct" || len(argv) < 2 {
+		return ""
+	}
+	return argv[1]
+}
+
+func bundleFileName(job *models.Job) string {
+	return fmt.Sprintf("%s_%s%s", safeFileName(job.Name), shortJobID(job.ID), JobBundleExtension)
+}
+
+func safeFileName(name string) string {
+	name = strings.Trim(unsafeFileNameChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > bundleNameLengthLimit {
+		name = name[:bundleNameLengthLimit]
+	}
+	if name == "" {
+		name = "job"
+	}
+	return name
+}
+
+func shortJobID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
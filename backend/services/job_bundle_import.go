@@ -0,0 +1,400 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+// ImportJobBundle recreates the job recorded in a bundle in the active
+// project, unpacks its outputs into the project's output directory and
+// reports how this machine differs from the one that produced it.
+func (b *JobBundleService) ImportJobBundle(bundlePath string) (*models.JobBundleImport, error) {
+	return b.importJobBundle(bundlePath, b.queue.projects.ActiveProjectID())
+}
+
+func (b *JobBundleService) importJobBundle(bundlePath string, projectID string) (*models.JobBundleImport, error) {
+	tempDir, err := os.MkdirTemp("", "cauldron-bundle-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := b.files.ExtractTarXz(bundlePath, tempDir); err != nil {
+		return nil, fmt.Errorf("failed to extract bundle: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, bundleManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("bundle has no manifest: %w", err)
+	}
+	var manifest models.JobBundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if manifest.FormatVersion > models.JobBundleFormatVersion {
+		return nil, fmt.Errorf("bundle format version %d is newer than supported version %d",
+			manifest.FormatVersion, models.JobBundleFormatVersion)
+	}
+
+	job := manifest.Job
+	job.ProjectID = projectID
+	if _, err := b.queue.GetJob(job.ID); err == nil || job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+
+	outputDir := filepath.Join(b.outputRoot(projectID), fmt.Sprintf("%s_%s_imported", safeFileName(job.Type), shortJobID(job.ID)))
+	if err := unpackBundleOutputs(tempDir, outputDir, manifest.Outputs); err != nil {
+		os.RemoveAll(outputDir)
+		return nil, err
+	}
+
+	result := &models.JobBundleImport{
+		JobID:         job.ID,
+		OriginalJobID: manifest.Job.ID,
+		OutputPath:    outputDir,
+		Differences:   []models.BundleDifference{},
+		CanRerun:      true,
+	}
+
+	relocateImportedJob(&job, outputDir)
+	b.localizeEnvironment(&job, result)
+	b.comparePlugin(&manifest, &job, result)
+	b.compareScript(&manifest, &job, result)
+	compareInputs(manifest.Inputs, result)
+	b.comparePackages(manifest.Environment, &job, result)
+
+	if err := b.queue.ImportJob(&job); err != nil {
+		os.RemoveAll(outputDir)
+		return nil, fmt.Errorf("failed to store imported job: %w", err)
+	}
+
+	if manifest.LogFile != "" {
+		if logPath, err := archiveEntryPath(tempDir, manifest.LogFile); err == nil {
+			if data, err := os.ReadFile(logPath); err == nil {
+				if err := b.queue.logs.ImportLines(job.ID, parseBundleLog(data, job.Attempt)); err != nil {
+					log.Printf("[ImportJobBundle] Failed to import log of job %s: %v", job.ID, err)
+				}
+			}
+		}
+	}
+
+	if _, err := b.queue.artifacts.ScanJobOutputs(job.ID, outputDir, job.DeclaredOutputs); err != nil {
+		log.Printf("[ImportJobBundle] Failed to record outputs of job %s: %v", job.ID, err)
+	}
+	b.queue.search.IndexJob(&job)
+
+	log.Printf("[ImportJobBundle] Imported job %s as %s with %d difference(s)",
+		result.OriginalJobID, job.ID, len(result.Differences))
+	return result, nil
+}
+
+// outputRoot returns the directory imported outputs of a project go to.
+func (b *JobBundleService) outputRoot(projectID string) string {
+	return b.queue.projects.OutputDirectory(projectID, b.configuredOutputDirectory())
+}
+
+func (b *JobBundleService) configuredOutputDirectory() string {
+	if b.settings != nil {
+		if dir := b.settings.GetConfig().OutputDirectory; dir != "" {
+			return dir
+		}
+	}
+	return "outputs"
+}
+
+// unpackBundleOutputs copies the bundled outputs into outputDir and checks
+// each against its recorded checksum.
+func unpackBundleOutputs(bundleDir string, outputDir string, outputs []models.BundleFile) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	for _, output := range outputs {
+		if output.ArchivePath == "" {
+			continue
+		}
+		src, err := archiveEntryPath(bundleDir, output.ArchivePath)
+		if err != nil {
+			return err
+		}
+		dst, err := archiveEntryPath(outputDir, output.Path)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(src, dst); err != nil {
+			return fmt.Errorf("failed to unpack output %s: %w", output.Path, err)
+		}
+
+		checksum, _, err := hashFile(dst)
+		if err != nil {
+			return err
+		}
+		if checksum != output.SHA256 {
+			return fmt.Errorf("output %s does not match its recorded checksum", output.Path)
+		}
+	}
+	return nil
+}
+
+// relocateImportedJob points the job at its new output directory and clears
+// the scheduling state that only made sense on the original machine.
+func relocateImportedJob(job *models.Job, outputDir string) {
+	previous := job.OutputPath
+	if dir, ok := job.Parameters["outputDir"].(string); ok && dir != "" {
+		previous = dir
+		job.Parameters["outputDir"] = outputDir
+	}
+	if previous != "" {
+		for i, arg := range job.Args {
+			if arg == previous {
+				job.Args[i] = outputDir
+			}
+		}
+	}
+
+	job.Name += " (Imported)"
+	job.OutputPath = outputDir
+	job.DependsOn = nil
+	job.RunAfter = nil
+	job.NextAttemptAt = nil
+	job.Schedule = ""
+	job.ScheduledFrom = ""
+	job.LeaseOwner = ""
+	job.LeaseExpiresAt = nil
+}
+
+// localizeEnvironment keeps the Python and R environments recorded on the job
+// only if they are registered on this machine. Others are cleared, so the job
+// and a re-run of it use the default interpreter, and reported; the
+// environment of the original machine stays in the manifest.
+func (b *JobBundleService) localizeEnvironment(job *models.Job, result *models.JobBundleImport) {
+	pythonPath, rPath := job.PythonEnvPath, job.REnvPath
+	if pythonPath != "" && !b.pythonEnvironmentRegistered(pythonPath) {
+		job.PythonEnvPath, job.PythonEnvType = "", ""
+	}
+	if rPath != "" && !b.rEnvironmentRegistered(rPath) {
+		job.REnvPath, job.REnvType = "", ""
+	}
+
+	interpreter := ""
+	if argv := b.argv(job); len(argv) > 0 {
+		interpreter = argv[0]
+	}
+	runtime := jobRuntime(job.Command)
+	if pythonPath != job.PythonEnvPath {
+		difference := models.BundleDifference{Kind: models.BundleDifferenceEnvironment, Name: "Python environment", Recorded: pythonPath}
+		if runtime == WorkerRuntimePython {
+			difference.Local = interpreter
+		}
+		result.Differences = append(result.Differences, difference)
+	}
+	if rPath != job.REnvPath {
+		difference := models.BundleDifference{Kind: models.BundleDifferenceEnvironment, Name: "R environment", Recorded: rPath}
+		if runtime == WorkerRuntimeR {
+			difference.Local = interpreter
+		}
+		result.Differences = append(result.Differences, difference)
+	}
+}
+
+func (b *JobBundleService) pythonEnvironmentRegistered(path string) bool {
+	envs, err := b.queue.db.GetPythonEnvironments()
+	if err != nil {
+		return false
+	}
+	for _, env := range envs {
+		if env.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *JobBundleService) rEnvironmentRegistered(path string) bool {
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+	envs, err := b.queue.db.GetREnvironments()
+	if err != nil {
+		return false
+	}
+	for _, env := range envs {
+		if env.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *JobBundleService) comparePlugin(manifest *models.JobBundleManifest, job *models.Job, result *models.JobBundleImport) {
+	recorded := manifest.Plugin
+	if recorded == nil {
+		return
+	}
+
+	var plugin *models.PluginV2
+	if b.plugins != nil {
+		plugin, _ = b.plugins.GetPlugin(recorded.ID)
+	}
+	if plugin == nil {
+		result.CanRerun = false
+		result.Differences = append(result.Differences, models.BundleDifference{
+			Kind:     models.BundleDifferencePlugin,
+			Name:     recorded.ID,
+			Recorded: recorded.Version,
+		})
+		return
+	}
+
+	if len(job.Args) > 0 && filepath.Base(job.Args[0]) == filepath.Base(recorded.ScriptFile) {
+		job.Args[0] = plugin.ScriptPath
+	}
+
+	if version := plugin.Definition.Plugin.Version; version != recorded.Version {
+		result.Differences = append(result.Differences, models.BundleDifference{
+			Kind:     models.BundleDifferencePlugin,
+			Name:     recorded.ID + " version",
+			Recorded: recorded.Version,
+			Local:    version,
+		})
+	}
+
+	files := []struct {
+		name, recorded, path string
+	}{
+		{filepath.Base(recorded.DefinitionFile), recorded.DefinitionSHA256, b.plugins.getPlatformSpecificConfig(plugin.FolderPath)},
+		{filepath.Base(recorded.ScriptFile), recorded.ScriptSHA256, plugin.ScriptPath},
+	}
+	for _, file := range files {
+		checksum, _, _ := hashFile(file.path)
+		if checksum != file.recorded {
+			result.Differences = append(result.Differences, models.BundleDifference{
+				Kind:     models.BundleDifferencePlugin,
+				Name:     file.name,
+				Recorded: file.recorded,
+				Local:    checksum,
+			})
+		}
+	}
+}
+
+func (b *JobBundleService) compareScript(manifest *models.JobBundleManifest, job *models.Job, result *models.JobBundleImport) {
+	recorded := manifest.Script
+	if recorded == nil || recorded.Missing {
+		return
+	}
+
+	local := scriptPath(job, b.argv(job))
+	checksum := ""
+	if local != "" {
+		checksum, _, _ = hashFile(local)
+	}
+	if checksum == "" {
+		result.CanRerun = false
+	}
+	if checksum != recorded.SHA256 {
+		result.Differences = append(result.Differences, models.BundleDifference{
+			Kind:     models.BundleDifferenceScript,
+			Name:     filepath.Base(recorded.Path),
+			Recorded: recorded.SHA256,
+			Local:    checksum,
+		})
+	}
+}
+
+func compareInputs(inputs []models.BundleFile, result *models.JobBundleImport) {
+	for _, input := range inputs {
+		if input.Missing {
+			continue
+		}
+		checksum, _, _ := hashFile(input.Path)
+		if checksum == "" {
+			result.CanRerun = false
+		}
+		if checksum != input.SHA256 {
+			result.Differences = append(result.Differences, models.BundleDifference{
+				Kind:     models.BundleDifferenceInput,
+				Name:     input.Path,
+				Recorded: input.SHA256,
+				Local:    checksum,
+			})
+		}
+	}
+}
+
+// comparePackages lists recorded packages that are missing or installed at
+// another version in the environment a re-run would use.
+func (b *JobBundleService) comparePackages(recorded models.BundleEnvironment, job *models.Job, result *models.JobBundleImport) {
+	if len(recorded.Packages) == 0 {
+		return
+	}
+
+	local := b.environment(job, b.argv(job))
+	if local.Error != "" || local.Interpreter == "" {
+		result.Differences = append(result.Differences, models.BundleDifference{
+			Kind:     models.BundleDifferencePackage,
+			Name:     "installed packages",
+			Recorded: fmt.Sprintf("%d packages", len(recorded.Packages)),
+			Local:    local.Error,
+		})
+		return
+	}
+
+	installed := packageVersions(local.Packages)
+	var differences []models.BundleDifference
+	for name, version := range packageVersions(recorded.Packages) {
+		if installed[name] != version {
+			differences = append(differences, models.BundleDifference{
+				Kind:     models.BundleDifferencePackage,
+				Name:     name,
+				Recorded: version,
+				Local:    installed[name],
+			})
+		}
+	}
+	sort.Slice(differences, func(i, k int) bool {
+		return differences[i].Name < differences[k].Name
+	})
+	result.Differences = append(result.Differences, differences...)
+}
+
+func packageVersions(packages []string) map[string]string {
+	versions := make(map[string]string, len(packages))
+	for _, entry := range packages {
+		name, version, _ := strings.Cut(entry, "==")
+		versions[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(version)
+	}
+	return versions
+}
+
+// parseBundleLog reads the "timestamp stream line" entries written by
+// addLog.
+func parseBundleLog(data []byte, attempt int) []models.JobLogLine {
+	var lines []models.JobLogLine
+	for _, entry := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		parts := strings.SplitN(entry, " ", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+		if err != nil {
+			continue
+		}
+		lines = append(lines, models.JobLogLine{
+			Attempt:   attempt,
+			Stream:    parts[1],
+			Line:      parts[2],
+			Timestamp: timestamp,
+		})
+	}
+	return lines
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"github.com/ulikunitz/xz"
+)
+
+func readBundle(t *testing.T, bundlePath string) map[string]string {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		t.Fatalf("Failed to open bundle: %v", err)
+	}
+	defer file.Close()
+
+	xzReader, err := xz.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to read bundle: %v", err)
+	}
+
+	entries := map[string]string{}
+	tarReader := tar.NewReader(xzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read bundle entry: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", header.Name, err)
+		}
+		entries[header.Name] = string(data)
+	}
+	return entries
+}
+
+func TestExportJobBundle(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	inputPath := filepath.Join(t.TempDir(), "input.tsv")
+	os.WriteFile(inputPath, []byte("a\tb\n"), 0644)
+
+	outputDir := t.TempDir()
+	jobID, err := jobQueue.CreateJobWithOptions("test", "Bundled job", "direct",
+		[]string{"sh", "-c", "echo working; mkdir -p plots; echo result > result.txt; echo plot > plots/a.svg"},
+		map[string]interface{}{"outputDir": outputDir, "inputFile": inputPath, "missing": "/nonexistent/input.tsv"},
+		models.JobOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+	waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+
+	bundles := NewJobBundleService(jobQueue, nil, nil, NewFileService(jobQueue.ctx), nil)
+	bundlePath, err := bundles.ExportJobBundle(jobID, t.TempDir())
+	if err != nil {
+		t.Fatalf("ExportJobBundle failed: %v", err)
+	}
+	if !strings.HasPrefix(filepath.Base(bundlePath), "Bundled_job_") || !strings.HasSuffix(bundlePath, JobBundleExtension) {
+		t.Errorf("Unexpected bundle name %s", bundlePath)
+	}
+
+	entries := readBundle(t, bundlePath)
+	if entries["outputs/result.txt"] != "result\n" || entries["outputs/plots/a.svg"] != "plot\n" {
+		t.Errorf("Expected the outputs in the bundle, got entries %v", entries)
+	}
+	if !strings.Contains(entries["log.txt"], " stdout working\n") {
+		t.Errorf("Expected the job log in the bundle, got %q", entries["log.txt"])
+	}
+
+	var manifest models.JobBundleManifest
+	if err := json.Unmarshal([]byte(entries["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	if manifest.Job.ID != jobID || manifest.FormatVersion != models.JobBundleFormatVersion {
+		t.Errorf("Unexpected manifest header: %+v", manifest)
+	}
+	if len(manifest.Argv) != 3 || filepath.Base(manifest.Argv[0]) != "sh" || manifest.Argv[1] != "-c" {
+		t.Errorf("Expected the resolved argument vector, got %v", manifest.Argv)
+	}
+	if len(manifest.Inputs) != 2 {
+		t.Fatalf("Expected two inputs, got %+v", manifest.Inputs)
+	}
+	input, missing := manifest.Inputs[0], manifest.Inputs[1]
+	if input.Path != inputPath || input.Size != 4 || len(input.SHA256) != 64 || input.ArchivePath != "" {
+		t.Errorf("Unexpected input entry: %+v", input)
+	}
+	if !missing.Missing || missing.Name != "missing" {
+		t.Errorf("Expected the missing input to be flagged, got %+v", missing)
+	}
+	if len(manifest.Outputs) != 2 || manifest.Outputs[0].ArchivePath != "outputs/plots/a.svg" {
+		t.Errorf("Unexpected outputs: %+v", manifest.Outputs)
+	}
+}
+
+func TestExportJobBundleRequiresFinishedJob(t *testing.T) {
+	jobQueue, _ := newTestJobQueueWithDirectRunner(t)
+
+	runAfter := time.Now().Add(time.Hour)
+	jobID, err := jobQueue.CreateJobWithOptions("test", "Waiting", "direct", []string{"true"}, nil,
+		models.JobOptions{RunAfter: &runAfter})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	bundles := NewJobBundleService(jobQueue, nil, nil, NewFileService(jobQueue.ctx), nil)
+	if _, err := bundles.ExportJobBundle(jobID, filepath.Join(t.TempDir(), "job.tar.xz")); err == nil {
+		t.Error("Expected exporting an unfinished job to fail")
+	}
+}
+
+func TestImportJobBundle(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	inputPath := filepath.Join(t.TempDir(), "input.tsv")
+	os.WriteFile(inputPath, []byte("a\tb\n"), 0644)
+
+	jobID, err := jobQueue.CreateJobWithOptions("test", "Portable", "direct",
+		[]string{"sh", "-c", "echo exported; echo result > result.txt"},
+		map[string]interface{}{"outputDir": t.TempDir(), "inputFile": inputPath},
+		models.JobOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+	waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+
+	settings := NewSettingsService(jobQueue.ctx, db)
+	outputRoot := t.TempDir()
+	settings.Set("outputDirectory", outputRoot)
+
+	bundles := NewJobBundleService(jobQueue, nil, nil, NewFileService(jobQueue.ctx), settings)
+	bundlePath, err := bundles.ExportJobBundle(jobID, filepath.Join(t.TempDir(), "portable"+JobBundleExtension))
+	if err != nil {
+		t.Fatalf("ExportJobBundle failed: %v", err)
+	}
+
+	os.WriteFile(inputPath, []byte("changed\n"), 0644)
+
+	result, err := bundles.ImportJobBundle(bundlePath)
+	if err != nil {
+		t.Fatalf("ImportJobBundle failed: %v", err)
+	}
+	if result.OriginalJobID != jobID || result.JobID == jobID {
+		t.Errorf("Expected the import to get a new ID, got %+v", result)
+	}
+	if !strings.HasPrefix(result.OutputPath, outputRoot) {
+		t.Errorf("Expected outputs under %s, got %s", outputRoot, result.OutputPath)
+	}
+	if data, _ := os.ReadFile(filepath.Join(result.OutputPath, "result.txt")); string(data) != "result\n" {
+		t.Errorf("Expected the output to be unpacked, got %q", data)
+	}
+
+	if len(result.Differences) != 1 || result.Differences[0].Kind != models.BundleDifferenceInput || result.Differences[0].Local == "" {
+		t.Errorf("Expected the changed input to be reported, got %+v", result.Differences)
+	}
+	if !result.CanRerun {
+		t.Error("Expected a changed input to still allow a re-run")
+	}
+
+	imported, err := jobQueue.GetJob(result.JobID)
+	if err != nil {
+		t.Fatalf("Imported job not found: %v", err)
+	}
+	if imported.Status != models.JobStatusCompleted || imported.OutputPath != result.OutputPath || imported.Parameters["outputDir"] != result.OutputPath {
+		t.Errorf("Unexpected imported job: %+v", imported)
+	}
+
+	page, err := jobQueue.Logs().GetJobLog(result.JobID, 0, 100, "")
+	if err != nil || len(page.Lines) != 1 || page.Lines[0].Line != "exported" {
+		t.Errorf("Expected the log to be imported, got %+v (%v)", page, err)
+	}
+	if artifacts, _ := jobQueue.Artifacts().GetJobArtifacts(result.JobID); len(artifacts) != 1 {
+		t.Errorf("Expected the imported output to be recorded, got %+v", artifacts)
+	}
+
+	os.Remove(inputPath)
+	result, err = bundles.ImportJobBundle(bundlePath)
+	if err != nil {
+		t.Fatalf("Second import failed: %v", err)
+	}
+	if result.CanRerun || result.Differences[0].Local != "" {
+		t.Errorf("Expected a missing input to prevent a re-run, got %+v", result)
+	}
+}
+
+func TestImportedJobUsesLocalEnvironment(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	jobQueue.pythonRunner = &PythonRunner{pythonPath: "/usr/bin/python3", scriptDir: t.TempDir()}
+	local := filepath.Join(t.TempDir(), "bin", "python")
+	if err := db.SavePythonEnvironment(PythonEnvironment{Name: "local", Path: local, Type: "venv", Version: "3.12"}); err != nil {
+		t.Fatalf("Failed to register environment: %v", err)
+	}
+	bundles := NewJobBundleService(jobQueue, nil, nil, nil, nil)
+
+	job := &models.Job{Command: "python", Args: []string{"/plugins/qc/qc.py"}, PythonEnvPath: "/home/elsewhere/envs/ms/bin/python", PythonEnvType: "venv"}
+	result := &models.JobBundleImport{}
+	bundles.localizeEnvironment(job, result)
+	if job.PythonEnvPath != "" || job.PythonEnvType != "" {
+		t.Errorf("Expected the foreign environment to be cleared, got %q", job.PythonEnvPath)
+	}
+	if len(result.Differences) != 1 || result.Differences[0].Kind != models.BundleDifferenceEnvironment ||
+		result.Differences[0].Recorded != "/home/elsewhere/envs/ms/bin/python" || result.Differences[0].Local != "/usr/bin/python3" {
+		t.Errorf("Expected the environment change to be reported, got %+v", result.Differences)
+	}
+
+	job = &models.Job{Command: "python", Args: []string{"/plugins/qc/qc.py"}, PythonEnvPath: local}
+	result = &models.JobBundleImport{}
+	bundles.localizeEnvironment(job, result)
+	if job.PythonEnvPath != local || len(result.Differences) != 0 {
+		t.Errorf("Expected a registered environment to be kept, got %q %+v", job.PythonEnvPath, result.Differences)
+	}
+}
@@ -0,0 +1,501 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const (
+	maxComparedJobs = 10
+	// maxComparedTableBytes keeps a comparison from loading huge tables
+	// into memory; larger tables are listed with an error instead.
+	maxComparedTableBytes = 200 << 20
+	// maxFeatureDifferences caps how many rows are reported per table,
+	// keeping those that changed the most.
+	maxFeatureDifferences = 100
+)
+
+// internalParameters are recorded with every plugin job but are not
+// settings the user chose, so they are left out of parameter comparisons.
+var internalParameters = map[string]bool{
+	"outputDir":     true,
+	"pluginId":      true,
+	"pluginVersion": true,
+}
+
+var tableExtensions = map[string]rune{
+	".tsv": '\t',
+	".txt": '\t',
+	".tab": '\t',
+	".csv": ',',
+}
+
+// CompareJobs puts jobs side by side: their parameters, plugin versions,
+// environments and runtime metrics, and the output tables they have in
+// common. The first job is the reference that tables are compared against.
+func (j *JobQueueService) CompareJobs(ids []string) (*models.JobComparison, error) {
+	if len(ids) < 2 {
+		return nil, fmt.Errorf("select at least two jobs to compare")
+	}
+	if len(ids) > maxComparedJobs {
+		return nil, fmt.Errorf("at most %d jobs can be compared at once", maxComparedJobs)
+	}
+
+	jobs := make([]*models.Job, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for i, id := range ids {
+		if seen[id] {
+			return nil, fmt.Errorf("job %s is listed twice", id)
+		}
+		seen[id] = true
+		job, err := j.GetJob(id)
+		if err != nil {
+			return nil, err
+		}
+		jobs[i] = job
+	}
+
+	comparison := &models.JobComparison{
+		Jobs:   make([]models.ComparedJob, len(jobs)),
+		Fields: compareFields(jobs),
+		Tables: []models.TableComparison{},
+	}
+	for i, job := range jobs {
+		comparison.Jobs[i] = models.ComparedJob{ID: job.ID, Name: job.Name, Status: job.Status, CreatedAt: job.CreatedAt}
+	}
+
+	tables := make([]map[string]string, len(jobs))
+	for i, job := range jobs {
+		tables[i] = j.outputTables(job)
+	}
+	names := make([]string, 0, len(tables[0]))
+	for name := range tables[0] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for i := 1; i < len(jobs); i++ {
+			path, ok := tables[i][name]
+			if !ok {
+				continue
+			}
+			table := compareTables(tables[0][name], path)
+			table.Name = name
+			table.ReferenceJobID = jobs[0].ID
+			table.JobID = jobs[i].ID
+			comparison.Tables = append(comparison.Tables, table)
+		}
+	}
+
+	return comparison, nil
+}
+
+func compareFields(jobs []*models.Job) []models.ComparisonField {
+	var fields []models.ComparisonField
+	add := func(section, name string, value func(job *models.Job) string) {
+		field := models.ComparisonField{Section: section, Name: name, Values: make([]string, len(jobs))}
+		for i, job := range jobs {
+			field.Values[i] = value(job)
+			if field.Values[i] != field.Values[0] {
+				field.Differs = true
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	add(models.ComparisonSectionJob, "status", func(job *models.Job) string { return string(job.Status) })
+	add(models.ComparisonSectionJob, "error", func(job *models.Job) string { return job.Error })
+
+	add(models.ComparisonSectionPlugin, "plugin", jobPlugin)
+	add(models.ComparisonSectionPlugin, "version", func(job *models.Job) string {
+		version, _ := job.Parameters["pluginVersion"].(string)
+		return version
+	})
+	add(models.ComparisonSectionPlugin, "script", func(job *models.Job) string {
+		if len(job.Args) == 0 {
+			return ""
+		}
+		return job.Args[0]
+	})
+
+	add(models.ComparisonSectionEnvironment, "runtime", func(job *models.Job) string { return job.Command })
+	add(models.ComparisonSectionEnvironment, "python", func(job *models.Job) string {
+		return describeEnvironment(job.PythonEnvPath, job.PythonEnvType)
+	})
+	add(models.ComparisonSectionEnvironment, "r", func(job *models.Job) string {
+		return describeEnvironment(job.REnvPath, job.REnvType)
+	})
+
+	var parameters []string
+	known := make(map[string]bool)
+	for _, job := range jobs {
+		for name := range job.Parameters {
+			if !internalParameters[name] && !known[name] {
+				known[name] = true
+				parameters = append(parameters, name)
+			}
+		}
+	}
+	sort.Strings(parameters)
+	for _, name := range parameters {
+		name := name
+		add(models.ComparisonSectionParameter, name, func(job *models.Job) string {
+			return formatParameter(job.Parameters[name])
+		})
+	}
+
+	add(models.ComparisonSectionMetric, "duration seconds", func(job *models.Job) string {
+		if job.DurationSeconds == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*job.DurationSeconds, 'f', 1, 64)
+	})
+	add(models.ComparisonSectionMetric, "exit code", func(job *models.Job) string {
+		if job.Metrics.ExitCode == nil {
+			return ""
+		}
+		return strconv.Itoa(*job.Metrics.ExitCode)
+	})
+	add(models.ComparisonSectionMetric, "cpu seconds", func(job *models.Job) string {
+		return strconv.FormatFloat(job.Metrics.CPUSeconds(), 'f', 1, 64)
+	})
+	add(models.ComparisonSectionMetric, "peak memory MB", func(job *models.Job) string {
+		return strconv.FormatFloat(float64(job.Metrics.PeakRSSBytes)/(1<<20), 'f', 1, 64)
+	})
+	add(models.ComparisonSectionMetric, "attempts", func(job *models.Job) string { return strconv.Itoa(job.Attempt) })
+
+	return fields
+}
+
+func describeEnvironment(path, envType string) string {
+	if path == "" {
+		return ""
+	}
+	if envType == "" {
+		return path
+	}
+	return fmt.Sprintf("%s (%s)", path, envType)
+}
+
+func formatParameter(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// outputTables maps each tabular output, by its path within the job's output
+// folder, to its location on disk.
+func (j *JobQueueService) outputTables(job *models.Job) map[string]string {
+	tables := make(map[string]string)
+	outputDir := jobOutputDir(job)
+	if outputDir == "" {
+		return tables
+	}
+	artifacts, err := j.Artifacts().GetJobArtifacts(job.ID)
+	if err != nil {
+		return tables
+	}
+	for _, artifact := range artifacts {
+		if _, ok := tableExtensions[strings.ToLower(filepath.Ext(artifact.Path))]; ok {
+			tables[artifact.Path] = filepath.Join(outputDir, filepath.FromSlash(artifact.Path))
+		}
+	}
+	return tables
+}
+
+// rowNamesColumn names the first column of a table whose header has no field
+// for it, as R writes tables with row names.
+const rowNamesColumn = "row.names"
+
+// outputTable is a table read for comparison. Rows are keyed on the first
+// column, together with its label columns when that alone repeats.
+type outputTable struct {
+	header  []string
+	records [][]string
+	rows    map[string][]string
+}
+
+func readOutputTable(path string) (*outputTable, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxComparedTableBytes {
+		return nil, fmt.Errorf("table is too large to compare (%d MB)", info.Size()>>20)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = tableExtensions[strings.ToLower(filepath.Ext(path))]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	table := &outputTable{header: header}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row %d: %w", len(table.records)+2, err)
+		}
+		if len(record) == 0 {
+			continue
+		}
+		// A header one field shorter than the rows leaves out the row names.
+		if len(table.records) == 0 && len(record) == len(header)+1 {
+			table.header = append([]string{rowNamesColumn}, header...)
+		}
+		table.records = append(table.records, record)
+	}
+	if len(table.header) < 2 {
+		return nil, fmt.Errorf("not a table")
+	}
+	return table, nil
+}
+
+// repeatsKeys reports whether a value of the first column appears in more
+// than one row.
+func (t *outputTable) repeatsKeys() bool {
+	seen := make(map[string]bool, len(t.records))
+	for _, record := range t.records {
+		if seen[record[0]] {
+			return true
+		}
+		seen[record[0]] = true
+	}
+	return false
+}
+
+// labelColumns returns the columns other than the first that hold text,
+// such as the contrast of stacked per-comparison results.
+func (t *outputTable) labelColumns() map[string]bool {
+	labels := make(map[string]bool)
+	for i, name := range t.header[1:] {
+		for _, record := range t.records {
+			if i+1 >= len(record) {
+				continue
+			}
+			value := strings.TrimSpace(record[i+1])
+			if value == "" || value == "NA" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				labels[name] = true
+				break
+			}
+		}
+	}
+	return labels
+}
+
+// index keys the rows on the first column and the named label columns. It
+// returns how many rows it left out because their key repeated.
+func (t *outputTable) index(keyColumns []string) int {
+	positions := make([]int, 0, len(keyColumns)+1)
+	positions = append(positions, 0)
+	for _, name := range keyColumns {
+		positions = append(positions, slices.Index(t.header, name))
+	}
+
+	t.rows = make(map[string][]string, len(t.records))
+	repeated := 0
+	for _, record := range t.records {
+		parts := make([]string, len(positions))
+		for i, position := range positions {
+			if position < len(record) {
+				parts[i] = record[position]
+			}
+		}
+		key := strings.Join(parts, " | ")
+		if _, ok := t.rows[key]; ok {
+			repeated++
+			continue
+		}
+		t.rows[key] = record
+	}
+	return repeated
+}
+
+// compareTables matches the rows of two tables on their first column and
+// reports which rows and columns they share and how the numeric values of
+// shared rows changed.
+func compareTables(referencePath, path string) models.TableComparison {
+	result := models.TableComparison{
+		SharedColumns:        []string{},
+		OnlyReferenceColumns: []string{},
+		OnlyColumns:          []string{},
+		Columns:              []models.ColumnDifference{},
+		Features:             []models.FeatureDifference{},
+	}
+
+	reference, err := readOutputTable(referencePath)
+	if err != nil {
+		result.Error = fmt.Sprintf("%s: %v", referencePath, err)
+		return result
+	}
+	other, err := readOutputTable(path)
+	if err != nil {
+		result.Error = fmt.Sprintf("%s: %v", path, err)
+		return result
+	}
+
+	// Stacked results repeat each feature once per comparison, so when the
+	// first column repeats, the text columns both tables share join the key.
+	var keyColumns []string
+	if reference.repeatsKeys() || other.repeatsKeys() {
+		referenceLabels, otherLabels := reference.labelColumns(), other.labelColumns()
+		for _, name := range reference.header[1:] {
+			if referenceLabels[name] && otherLabels[name] && !slices.Contains(keyColumns, name) {
+				keyColumns = append(keyColumns, name)
+			}
+		}
+	}
+	result.RepeatedKeys = reference.index(keyColumns) + other.index(keyColumns)
+	result.KeyColumn = strings.Join(append([]string{reference.header[0]}, keyColumns...), " + ")
+	result.ReferenceRows = len(reference.records)
+	result.Rows = len(other.records)
+
+	otherColumns := make(map[string]int, len(other.header))
+	for i, name := range other.header[1:] {
+		if _, ok := otherColumns[name]; !ok {
+			otherColumns[name] = i + 1
+		}
+	}
+	type sharedColumn struct {
+		name             string
+		reference, other int
+	}
+	var shared []sharedColumn
+	referenceColumns := make(map[string]bool, len(reference.header))
+	for i, name := range reference.header[1:] {
+		if referenceColumns[name] {
+			continue
+		}
+		referenceColumns[name] = true
+		if index, ok := otherColumns[name]; ok {
+			shared = append(shared, sharedColumn{name: name, reference: i + 1, other: index})
+			result.SharedColumns = append(result.SharedColumns, name)
+		} else {
+			result.OnlyReferenceColumns = append(result.OnlyReferenceColumns, name)
+		}
+	}
+	for _, name := range other.header[1:] {
+		if !referenceColumns[name] {
+			result.OnlyColumns = append(result.OnlyColumns, name)
+			referenceColumns[name] = true
+		}
+	}
+
+	columns := make([]models.ColumnDifference, len(shared))
+	totals := make([]float64, len(shared))
+	for i, column := range shared {
+		columns[i].Name = column.name
+	}
+
+	keys := make([]string, 0, len(reference.rows))
+	for key := range reference.rows {
+		if _, ok := other.rows[key]; ok {
+			keys = append(keys, key)
+		} else {
+			result.OnlyReferenceRows++
+		}
+	}
+	sort.Strings(keys)
+	result.SharedRows = len(keys)
+	result.OnlyRows = len(other.rows) - len(keys)
+
+	var features []models.FeatureDifference
+	for _, key := range keys {
+		referenceRow, otherRow := reference.rows[key], other.rows[key]
+		feature := models.FeatureDifference{Key: key, Differences: make(map[string]float64)}
+		for i, column := range shared {
+			a, okA := numericCell(referenceRow, column.reference)
+			b, okB := numericCell(otherRow, column.other)
+			if !okA || !okB {
+				continue
+			}
+			diff := b - a
+			columns[i].Compared++
+			totals[i] += math.Abs(diff)
+			if !valuesDiffer(a, b) {
+				continue
+			}
+			columns[i].Differing++
+			columns[i].MaxAbsDiff = math.Max(columns[i].MaxAbsDiff, math.Abs(diff))
+			feature.Differences[column.name] = diff
+			feature.MaxAbsDiff = math.Max(feature.MaxAbsDiff, math.Abs(diff))
+		}
+		if len(feature.Differences) > 0 {
+			features = append(features, feature)
+		}
+	}
+
+	for i := range columns {
+		if columns[i].Compared > 0 {
+			columns[i].MeanAbsDiff = totals[i] / float64(columns[i].Compared)
+			result.Columns = append(result.Columns, columns[i])
+		}
+	}
+
+	sort.SliceStable(features, func(a, b int) bool {
+		return features[a].MaxAbsDiff > features[b].MaxAbsDiff
+	})
+	if len(features) > maxFeatureDifferences {
+		features = features[:maxFeatureDifferences]
+	}
+	result.Features = append(result.Features, features...)
+	return result
+}
+
+func numericCell(row []string, index int) (float64, bool) {
+	if index >= len(row) {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(row[index]), 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+// valuesDiffer ignores differences at the level of floating point noise, as
+// left by writing the same result with a different number of digits.
+func valuesDiffer(a, b float64) bool {
+	scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	return math.Abs(a-b) > 1e-12*scale
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestCompareJobs(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	dir := t.TempDir()
+
+	tables := map[string]string{
+		"yesterday": "Protein\tlogFC\tP.Value\tGene\nP1\t1.5\t0.01\tA\nP2\t-0.5\t0.2\tB\nP3\t2\t0.001\tC\n",
+		"today":     "Protein\tlogFC\tP.Value\tadj.P.Val\nP1\t1.5\t0.01\t0.03\nP2\t-0.75\t0.2\t0.4\nP4\t3\t0.0001\t0.001\n",
+	}
+	ids := []string{"yesterday", "today"}
+	for i, id := range ids {
+		outputDir := filepath.Join(dir, id)
+		os.MkdirAll(outputDir, 0755)
+		writeInput(t, filepath.Join(outputDir, "differential_analysis.txt"), tables[id])
+		writeInput(t, filepath.Join(outputDir, "notes.md"), id)
+
+		job := models.Job{
+			ID:             id,
+			Type:           "limma",
+			Name:           id,
+			Command:        "r",
+			Status:         models.JobStatusCompleted,
+			REnvPath:       "/envs/r-4.3",
+			Args:           models.StringArray{"limma.R"},
+			Parameters:     models.JSONMap{"outputDir": outputDir, "pluginId": "limma", "pluginVersion": []string{"1.0.0", "1.1.0"}[i], "alpha": 0.05},
+			OutputPath:     outputDir,
+			CreatedAt:      time.Now(),
+			TerminalOutput: models.StringArray{},
+		}
+		if err := db.GetDB().Create(&job).Error; err != nil {
+			t.Fatal(err)
+		}
+		if _, err := jobQueue.Artifacts().ScanJobOutputs(id, outputDir, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	comparison, err := jobQueue.CompareJobs(ids)
+	if err != nil {
+		t.Fatalf("CompareJobs failed: %v", err)
+	}
+
+	fields := make(map[string]models.ComparisonField)
+	for _, field := range comparison.Fields {
+		fields[field.Section+"/"+field.Name] = field
+	}
+	if version := fields["plugin/version"]; !version.Differs || version.Values[1] != "1.1.0" {
+		t.Errorf("Expected the plugin versions to differ, got %+v", version)
+	}
+	if alpha := fields["parameter/alpha"]; alpha.Differs || alpha.Values[0] != "0.05" {
+		t.Errorf("Expected alpha to be the same, got %+v", alpha)
+	}
+	if _, ok := fields["parameter/outputDir"]; ok {
+		t.Error("Expected the output folder to be left out of the parameters")
+	}
+
+	if len(comparison.Tables) != 1 {
+		t.Fatalf("Expected one shared table, got %+v", comparison.Tables)
+	}
+	table := comparison.Tables[0]
+	if table.Name != "differential_analysis.txt" || table.KeyColumn != "Protein" || table.Error != "" {
+		t.Fatalf("Unexpected table comparison: %+v", table)
+	}
+	if table.SharedRows != 2 || table.OnlyReferenceRows != 1 || table.OnlyRows != 1 {
+		t.Errorf("Expected 2 shared rows and one unique to each, got %+v", table)
+	}
+	if len(table.SharedColumns) != 2 || table.OnlyReferenceColumns[0] != "Gene" || table.OnlyColumns[0] != "adj.P.Val" {
+		t.Errorf("Unexpected column overlap: %+v", table)
+	}
+	if len(table.Columns) != 2 || table.Columns[0].Name != "logFC" || table.Columns[0].Differing != 1 || table.Columns[1].Differing != 0 {
+		t.Errorf("Expected only logFC to differ, got %+v", table.Columns)
+	}
+	if len(table.Features) != 1 || table.Features[0].Key != "P2" || math.Abs(table.Features[0].Differences["logFC"]+0.25) > 1e-9 {
+		t.Errorf("Expected P2 to have changed by -0.25, got %+v", table.Features)
+	}
+
+	if _, err := jobQueue.CompareJobs([]string{"today"}); err == nil {
+		t.Error("Expected comparing a single job to fail")
+	}
+}
+
+func TestCompareTablesWithRowNamesAndStackedComparisons(t *testing.T) {
+	dir := t.TempDir()
+	// Written by write.table with row names: the header has no field for them.
+	reference := filepath.Join(dir, "reference.txt")
+	other := filepath.Join(dir, "other.txt")
+	writeInput(t, reference, "logFC\tP.Value\tcomparison\nP1\t1.5\t0.01\tB-A\nP2\t-0.5\t0.2\tB-A\nP1\t0.5\t0.3\tC-A\nP2\t1\t0.04\tC-A\n")
+	writeInput(t, other, "logFC\tP.Value\tcomparison\nP1\t1.5\t0.01\tB-A\nP2\t-0.5\t0.2\tB-A\nP1\t0.75\t0.3\tC-A\nP2\t1\t0.04\tC-A\n")
+
+	table := compareTables(reference, other)
+	if table.Error != "" {
+		t.Fatalf("compareTables failed: %s", table.Error)
+	}
+	if table.KeyColumn != "row.names + comparison" || table.SharedRows != 4 || table.RepeatedKeys != 0 {
+		t.Fatalf("Expected the rows to be keyed on their row names and comparison, got %+v", table)
+	}
+	if len(table.SharedColumns) != 3 || table.SharedColumns[0] != "logFC" {
+		t.Errorf("Expected the columns to line up with their values, got %v", table.SharedColumns)
+	}
+	if len(table.Features) != 1 || table.Features[0].Key != "P1 | C-A" || table.Features[0].Differences["logFC"] != 0.25 {
+		t.Errorf("Expected only the logFC of P1 in C-A to differ, got %+v", table.Features)
+	}
+
+	duplicated := filepath.Join(dir, "duplicated.txt")
+	writeInput(t, duplicated, "Protein\tlogFC\nP1\t1\nP1\t2\nP2\t3\n")
+	table = compareTables(duplicated, duplicated)
+	if table.RepeatedKeys != 2 || table.SharedRows != 2 || table.ReferenceRows != 3 {
+		t.Errorf("Expected the repeated row to be reported, got %+v", table)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jobConsoleDirName is the folder of a job's output directory its process
+// writes stdout and stderr to. Artifact scans leave it out.
+const jobConsoleDirName = ".console"
+
+const consoleFollowInterval = 250 * time.Millisecond
+
+// exitStatusFileName is the file in a job's console folder its process's
+// exit status is written to, so a later session can collect it.
+const exitStatusFileName = "exit_status"
+
+// errExitStatusUnknown is the error of a re-attached job whose process
+// exited without leaving an exit status behind.
+var errExitStatusUnknown = errors.New("exit status unknown: the process finished after the app restarted")
+
+type consoleSpoolKey struct{}
+
+// withConsoleSpool returns a context that makes runProcess send the output
+// of the process it starts to files in dir rather than to pipes, and follow
+// those files. A process writing to files keeps running, and its output can
+// be followed again, if the app exits while it runs.
+func withConsoleSpool(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, consoleSpoolKey{}, dir)
+}
+
+func consoleFile(dir string, stream string) string {
+	return filepath.Join(dir, stream+".log")
+}
+
+// readExitStatus returns the exit status written to the console folder dir,
+// and false when none was.
+func readExitStatus(dir string) (int, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, exitStatusFileName))
+	if err != nil {
+		return 0, false
+	}
+	status, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	return status, err == nil
+}
+
+// openConsoleSpool creates the stdout and stderr files of a job attempt in
+// dir, truncating those of an earlier attempt.
+func openConsoleSpool(dir string, streams ...string) ([]*os.File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	files := make([]*os.File, 0, len(streams))
+	for _, stream := range streams {
+		file, err := os.OpenFile(consoleFile(dir, stream), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			for _, opened := range files {
+				opened.Close()
+			}
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// followConsole copies what is written to path into w as it grows, until
+// done is closed and everything written so far has been copied.
+func followConsole(path string, w io.Writer, done <-chan struct{}) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	ticker := time.NewTicker(consoleFollowInterval)
+	defer ticker.Stop()
+	for {
+		io.Copy(w, file)
+		select {
+		case <-done:
+			io.Copy(w, file)
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+// maxErrorExcerpt caps how many lines of a traceback are kept with the
+// parsed error.
+const maxErrorExcerpt = 40
+
+var (
+	pythonFramePattern     = regexp.MustCompile(`^\s*File "([^"]+)", line (\d+)(?:, in (.+))?$`)
+	pythonExceptionPattern = regexp.MustCompile(`^([A-Za-z_][\w.]*)(?::\s?(.*))?$`)
+	pythonMissingModule    = regexp.MustCompile(`No module named '([^']+)'`)
+	argparseErrorPattern   = regexp.MustCompile(`^\S+: error: (.+)$`)
+	quotedPathPattern      = regexp.MustCompile(`'([^']+)'`)
+
+	rErrorPattern       = regexp.MustCompile(`^Error(?: in (.+?) :|:)\s*(.*)$`)
+	rMissingPackage     = regexp.MustCompile(`there is no package called [‘'"]([^’'"]+)[’'"]`)
+	rMissingFile        = regexp.MustCompile(`cannot open file [‘'"]([^’'"]+)[’'"]: No such file or directory`)
+	rMissingPathPattern = regexp.MustCompile(`[‘'"]([^’'"]+)[’'"] does not exist`)
+)
+
+// pythonPackageNames maps import names to the pip packages that provide
+// them where the two differ.
+var pythonPackageNames = map[string]string{
+	"sklearn":  "scikit-learn",
+	"cv2":      "opencv-python",
+	"yaml":     "PyYAML",
+	"PIL":      "Pillow",
+	"Bio":      "biopython",
+	"skimage":  "scikit-image",
+	"dateutil": "python-dateutil",
+}
+
+var pythonUserInputErrors = map[string]bool{
+	"KeyError":                     true,
+	"ValueError":                   true,
+	"UnicodeDecodeError":           true,
+	"pandas.errors.ParserError":    true,
+	"pandas.errors.EmptyDataError": true,
+}
+
+var rUserInputMarkers = []string{
+	"undefined columns selected",
+	"more columns than column names",
+	"duplicate 'row.names' are not allowed",
+	"arguments imply differing number of rows",
+	"non-numeric argument",
+}
+
+// parseJobError works out why a job failed from the stderr it wrote and the
+// error its runner returned. It reads a Python traceback or an R error,
+// whichever the job's runtime makes likelier first, and falls back to the
+// runner error for limits and timeouts.
+func parseJobError(job *models.Job, runErr error, stderr []string) models.JobError {
+	var parsed models.JobError
+	var ok bool
+	if job.Command == "r" {
+		if parsed, ok = parseRError(stderr); !ok {
+			parsed, ok = parsePythonError(stderr)
+		}
+	} else {
+		if parsed, ok = parsePythonError(stderr); !ok {
+			parsed, ok = parseRError(stderr)
+		}
+	}
+
+	switch {
+	case errors.Is(runErr, ErrMemoryLimitExceeded):
+		parsed.Category = models.ErrorCategoryMemory
+	case errors.Is(runErr, ErrProcessTimedOut), errors.Is(runErr, ErrCPULimitExceeded):
+		parsed.Category = models.ErrorCategoryTimeout
+	case !ok && runErr == nil:
+		return models.JobError{}
+	}
+	if parsed.Message == "" && runErr != nil {
+		parsed.Message = runErr.Error()
+	}
+	if parsed.Category == "" {
+		parsed.Category = models.ErrorCategoryScript
+	}
+	parsed.Suggestion = errorSuggestion(job, &parsed)
+	return parsed
+}
+
+// summarizeJobError is the one-line Error of a failed job: the parsed cause
+// followed by what the runner reported.
+func summarizeJobError(details models.JobError, runErr error) string {
+	cause := details.Message
+	if details.Language == "python" && details.Type != "" {
+		cause = details.Type + ": " + details.Message
+	} else if details.Language == "r" && details.Type != "" {
+		cause = "Error in " + details.Type + ": " + details.Message
+	}
+	if cause == "" || cause == runErr.Error() {
+		return runErr.Error()
+	}
+	return fmt.Sprintf("%s (%v)", cause, runErr)
+}
+
+// parsePythonError reads the last traceback in stderr: its exception type and
+// message, and the innermost frame that is not library code. Without a
+// traceback it recognises argparse usage errors.
+func parsePythonError(lines []string) (models.JobError, bool) {
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "Traceback (most recent call last)") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		for i := len(lines) - 1; i >= 0; i-- {
+			if match := argparseErrorPattern.FindStringSubmatch(lines[i]); match != nil {
+				return models.JobError{
+					Language: "python",
+					Category: models.ErrorCategoryUserInput,
+					Type:     "ArgumentError",
+					Message:  match[1],
+					Excerpt:  excerpt(lines, max(0, i-5), i+1),
+				}, true
+			}
+		}
+		return models.JobError{}, false
+	}
+
+	parsed := models.JobError{Language: "python"}
+	var frames []models.ErrorFrame
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		if match := pythonFramePattern.FindStringSubmatch(line); match != nil {
+			number, _ := strconv.Atoi(match[2])
+			frames = append(frames, models.ErrorFrame{File: match[1], Line: number, Function: match[3]})
+			continue
+		}
+		if strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t' {
+			code := strings.TrimSpace(line)
+			if len(frames) > 0 && frames[len(frames)-1].Code == "" && code != "" && strings.Trim(code, "^~ ") != "" {
+				frames[len(frames)-1].Code = code
+			}
+			continue
+		}
+		if match := pythonExceptionPattern.FindStringSubmatch(line); match != nil {
+			parsed.Type, parsed.Message = match[1], strings.TrimSpace(match[2])
+		} else {
+			parsed.Message = strings.TrimSpace(line)
+		}
+		end = i + 1
+		break
+	}
+	parsed.Frame = failingFrame(frames)
+	parsed.Excerpt = excerpt(lines, start, end)
+
+	typeName := parsed.Type[strings.LastIndex(parsed.Type, ".")+1:]
+	switch {
+	case pythonMissingModule.MatchString(parsed.Message) && (typeName == "ModuleNotFoundError" || typeName == "ImportError"):
+		module := pythonMissingModule.FindStringSubmatch(parsed.Message)[1]
+		parsed.Category = models.ErrorCategoryMissingPackage
+		parsed.Package = strings.SplitN(module, ".", 2)[0]
+		if name, ok := pythonPackageNames[parsed.Package]; ok {
+			parsed.Package = name
+		}
+	case typeName == "FileNotFoundError" || strings.Contains(parsed.Message, "No such file or directory"):
+		parsed.Category = models.ErrorCategoryFileNotFound
+		if paths := quotedPathPattern.FindAllStringSubmatch(parsed.Message, -1); len(paths) > 0 {
+			parsed.Path = paths[len(paths)-1][1]
+		}
+	case typeName == "MemoryError" || strings.HasSuffix(typeName, "MemoryError") || strings.Contains(parsed.Message, "Unable to allocate"):
+		parsed.Category = models.ErrorCategoryMemory
+	case pythonUserInputErrors[parsed.Type] || pythonUserInputErrors[typeName]:
+		parsed.Category = models.ErrorCategoryUserInput
+	}
+	return parsed, true
+}
+
+// failingFrame is the innermost frame outside installed packages, which is
+// usually the line of the plugin script that made the failing call.
+func failingFrame(frames []models.ErrorFrame) *models.ErrorFrame {
+	if len(frames) == 0 {
+		return nil
+	}
+	for i := len(frames) - 1; i >= 0; i-- {
+		file := strings.ReplaceAll(frames[i].File, `\`, "/")
+		if !strings.Contains(file, "site-packages/") && !strings.Contains(file, "dist-packages/") && !strings.HasPrefix(file, "<") {
+			return &frames[i]
+		}
+	}
+	return &frames[len(frames)-1]
+}
+
+// parseRError reads the last "Error in ..." or "Error: ..." block of an R
+// script, up to its "Calls:" line or "Execution halted".
+func parseRError(lines []string) (models.JobError, bool) {
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if rErrorPattern.MatchString(lines[i]) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return models.JobError{}, false
+	}
+
+	match := rErrorPattern.FindStringSubmatch(lines[start])
+	parsed := models.JobError{Language: "r", Type: strings.TrimSpace(match[1])}
+	message := []string{strings.TrimSpace(match[2])}
+	end := start + 1
+	for ; end < len(lines); end++ {
+		line := strings.TrimSpace(lines[end])
+		if strings.HasPrefix(line, "Calls:") {
+			calls := strings.Split(strings.TrimPrefix(line, "Calls:"), "->")
+			parsed.Frame = &models.ErrorFrame{Function: strings.TrimSpace(calls[len(calls)-1])}
+			end++
+			break
+		}
+		if line == "Execution halted" || strings.HasPrefix(line, "In addition:") {
+			break
+		}
+		message = append(message, line)
+	}
+	parsed.Message = strings.TrimSpace(strings.Join(message, " "))
+	parsed.Excerpt = excerpt(lines, start, end)
+
+	text := strings.Join(lines, "\n")
+	switch {
+	case rMissingPackage.MatchString(parsed.Message):
+		parsed.Category = models.ErrorCategoryMissingPackage
+		parsed.Package = rMissingPackage.FindStringSubmatch(parsed.Message)[1]
+	case rMissingFile.MatchString(text):
+		parsed.Category = models.ErrorCategoryFileNotFound
+		parsed.Path = rMissingFile.FindStringSubmatch(text)[1]
+	case rMissingPathPattern.MatchString(parsed.Message):
+		parsed.Category = models.ErrorCategoryFileNotFound
+		parsed.Path = rMissingPathPattern.FindStringSubmatch(parsed.Message)[1]
+	case strings.Contains(parsed.Message, "cannot allocate vector of size"):
+		parsed.Category = models.ErrorCategoryMemory
+	default:
+		for _, marker := range rUserInputMarkers {
+			if strings.Contains(parsed.Message, marker) {
+				parsed.Category = models.ErrorCategoryUserInput
+				break
+			}
+		}
+	}
+	return parsed, true
+}
+
+func errorSuggestion(job *models.Job, parsed *models.JobError) string {
+	switch parsed.Category {
+	case models.ErrorCategoryMissingPackage:
+		if parsed.Language == "r" {
+			return fmt.Sprintf("Install the R package %s into the job's R environment%s, for example with BiocManager::install(\"%s\"), then rerun the job.",
+				parsed.Package, environmentSuffix(job.REnvPath), parsed.Package)
+		}
+		return fmt.Sprintf("Install %s into the job's Python environment%s with \"pip install %s\", then rerun the job.",
+			parsed.Package, environmentSuffix(job.PythonEnvPath), parsed.Package)
+	case models.ErrorCategoryFileNotFound:
+		if parsed.Path != "" {
+			return fmt.Sprintf("Check that %s exists and was not moved or renamed since the job was set up.", parsed.Path)
+		}
+		return "Check that the input files exist and were not moved or renamed since the job was set up."
+	case models.ErrorCategoryMemory:
+		if job.Limits.MaxMemoryMB > 0 {
+			return fmt.Sprintf("The job ran out of memory under its %d MB limit. Raise the limit, close other programs or run on a smaller input.", job.Limits.MaxMemoryMB)
+		}
+		return "The job ran out of memory. Close other programs or run on a smaller input."
+	case models.ErrorCategoryTimeout:
+		return "The job ran past its time limit. Raise the limit or run on a smaller input."
+	case models.ErrorCategoryUserInput:
+		return "Check the job's parameters and that the input files have the columns and format the plugin expects."
+	}
+	return ""
+}
+
+func environmentSuffix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " (" + path + ")"
+}
+
+func excerpt(lines []string, start, end int) []string {
+	if end-start > maxErrorExcerpt {
+		start = end - maxErrorExcerpt
+	}
+	return append([]string{}, lines[start:end]...)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestParseJobError(t *testing.T) {
+	exitErr := fmt.Errorf("exit status 1")
+	tests := []struct {
+		name     string
+		job      models.Job
+		runErr   error
+		stderr   string
+		expected models.JobError
+		suggests string
+	}{
+		{
+			name:   "missing python module",
+			job:    models.Job{Command: "python", PythonEnvPath: "/envs/ms/bin/python"},
+			runErr: exitErr,
+			stderr: `Traceback (most recent call last):
+  File "/plugins/pca/pca.py", line 3, in <module>
+    import sklearn.decomposition
+ModuleNotFoundError: No module named 'sklearn'`,
+			expected: models.JobError{
+				Language: "python", Category: models.ErrorCategoryMissingPackage, Type: "ModuleNotFoundError",
+				Message: "No module named 'sklearn'", Package: "scikit-learn",
+				Frame: &models.ErrorFrame{File: "/plugins/pca/pca.py", Line: 3, Function: "<module>", Code: "import sklearn.decomposition"},
+			},
+			suggests: `pip install scikit-learn`,
+		},
+		{
+			name:   "file not found inside pandas",
+			job:    models.Job{Command: "python"},
+			runErr: exitErr,
+			stderr: `Loading data
+Traceback (most recent call last):
+  File "/plugins/limma/run.py", line 41, in main
+    df = pd.read_csv(args.input, sep="\t")
+         ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^
+  File "/envs/ms/lib/python3.12/site-packages/pandas/io/common.py", line 873, in get_handle
+    handle = open(
+FileNotFoundError: [Errno 2] No such file or directory: '/data/report.tsv'`,
+			expected: models.JobError{
+				Language: "python", Category: models.ErrorCategoryFileNotFound, Type: "FileNotFoundError",
+				Message: "[Errno 2] No such file or directory: '/data/report.tsv'", Path: "/data/report.tsv",
+				Frame: &models.ErrorFrame{File: "/plugins/limma/run.py", Line: 41, Function: "main", Code: `df = pd.read_csv(args.input, sep="\t")`},
+			},
+			suggests: "/data/report.tsv",
+		},
+		{
+			name:   "argparse usage error",
+			job:    models.Job{Command: "python"},
+			runErr: fmt.Errorf("exit status 2"),
+			stderr: "usage: run.py [-h] --input INPUT\nrun.py: error: the following arguments are required: --input",
+			expected: models.JobError{
+				Language: "python", Category: models.ErrorCategoryUserInput, Type: "ArgumentError",
+				Message: "the following arguments are required: --input",
+			},
+		},
+		{
+			name:   "missing R package",
+			job:    models.Job{Command: "r", REnvPath: "/usr/bin/Rscript"},
+			runErr: exitErr,
+			stderr: "Loading required package: stats\nError in library(limma) : there is no package called ‘limma’\nExecution halted",
+			expected: models.JobError{
+				Language: "r", Category: models.ErrorCategoryMissingPackage, Type: "library(limma)",
+				Message: "there is no package called ‘limma’", Package: "limma",
+			},
+			suggests: `BiocManager::install("limma")`,
+		},
+		{
+			name:   "missing R input with call stack",
+			job:    models.Job{Command: "r"},
+			runErr: exitErr,
+			stderr: `Error in file(file, "rt") : cannot open the connection
+Calls: main -> read.delim -> read.table -> file
+In addition: Warning message:
+In file(file, "rt") :
+  cannot open file 'samples.tsv': No such file or directory
+Execution halted`,
+			expected: models.JobError{
+				Language: "r", Category: models.ErrorCategoryFileNotFound, Type: `file(file, "rt")`,
+				Message: "cannot open the connection", Path: "samples.tsv", Frame: &models.ErrorFrame{Function: "file"},
+			},
+		},
+		{
+			name:   "memory limit without a traceback",
+			job:    models.Job{Command: "python", Limits: models.ExecutionLimits{MaxMemoryMB: 512}},
+			runErr: fmt.Errorf("%w (512 MB)", ErrMemoryLimitExceeded),
+			stderr: "Killed",
+			expected: models.JobError{
+				Category: models.ErrorCategoryMemory, Message: "memory limit exceeded (512 MB)",
+			},
+			suggests: "512 MB limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := parseJobError(&tt.job, tt.runErr, strings.Split(tt.stderr, "\n"))
+			if parsed.Language != tt.expected.Language || parsed.Category != tt.expected.Category || parsed.Type != tt.expected.Type ||
+				parsed.Message != tt.expected.Message || parsed.Package != tt.expected.Package || parsed.Path != tt.expected.Path {
+				t.Errorf("Unexpected error:\n got %+v\nwant %+v", parsed, tt.expected)
+			}
+			if (parsed.Frame == nil) != (tt.expected.Frame == nil) || (parsed.Frame != nil && *parsed.Frame != *tt.expected.Frame) {
+				t.Errorf("Unexpected frame: got %+v, want %+v", parsed.Frame, tt.expected.Frame)
+			}
+			if !strings.Contains(parsed.Suggestion, tt.suggests) {
+				t.Errorf("Expected the suggestion to mention %q, got %q", tt.suggests, parsed.Suggestion)
+			}
+		})
+	}
+}
+
+func TestFailedJobRecordsErrorDetails(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	script := `echo "Traceback (most recent call last):" >&2
+echo '  File "/plugins/qc/qc.py", line 12, in <module>' >&2
+echo "    import pyarrow" >&2
+echo "ModuleNotFoundError: No module named 'pyarrow'" >&2
+exit 1`
+	id, err := jobQueue.CreateJobWithOptions("test", "Missing module", "direct", []string{"sh", "-c", script}, nil, models.JobOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	job := waitForJobStatus(t, db, id, models.JobStatusFailed)
+	if job.Error != "ModuleNotFoundError: No module named 'pyarrow' (exit status 1)" {
+		t.Errorf("Unexpected error summary: %q", job.Error)
+	}
+	details := job.ErrorDetails
+	if details.Category != models.ErrorCategoryMissingPackage || details.Package != "pyarrow" || details.Frame == nil || details.Frame.Line != 12 {
+		t.Errorf("Unexpected error details: %+v", details)
+	}
+}
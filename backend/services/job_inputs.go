@@ -0,0 +1,240 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+// inputSnapshotDirName is the folder of a job's output directory that holds
+// the snapshot of its inputs. Artifact scans leave it out.
+const inputSnapshotDirName = ".inputs"
+
+// recordInputs hashes the input files of a job and, unless mode is none,
+// copies or hardlinks them into the job's output directory. A job without an
+// output directory only gets its hashes recorded.
+func recordInputs(job *models.Job, mode string) error {
+	if mode == "" {
+		mode = models.InputSnapshotNone
+	}
+	if !isInputSnapshotMode(mode) {
+		return fmt.Errorf("unknown input snapshot mode: %s", mode)
+	}
+
+	outputDir, _ := job.Parameters["outputDir"].(string)
+	snapshotDir := ""
+	if mode != models.InputSnapshotNone {
+		if outputDir == "" {
+			log.Printf("[recordInputs] Job %s has no output directory, its inputs are not snapshotted", job.ID)
+		} else {
+			snapshotDir = filepath.Join(outputDir, inputSnapshotDirName)
+		}
+	}
+
+	job.Inputs = models.JobInputs{}
+	used := make(map[string]bool)
+	for _, path := range jobInputFiles(job.Args, job.Parameters) {
+		input := models.JobInput{Path: path}
+		var err error
+		if snapshotDir == "" {
+			input.SHA256, input.Size, err = hashInput(path)
+		} else {
+			input.SnapshotPath = snapshotPath(snapshotDir, path, used)
+			input.SHA256, input.Size, err = snapshotInput(path, input.SnapshotPath, mode)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record input %s: %w", path, err)
+		}
+		job.Inputs = append(job.Inputs, input)
+	}
+	return nil
+}
+
+// VerifyJobInputs compares the input files of a job, and their snapshots,
+// with the hashes recorded when it was submitted.
+func (j *JobQueueService) VerifyJobInputs(id string) ([]models.JobInputCheck, error) {
+	job, err := j.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	j.mu.RLock()
+	inputs := append(models.JobInputs{}, job.Inputs...)
+	j.mu.RUnlock()
+
+	checks := make([]models.JobInputCheck, 0, len(inputs))
+	for _, input := range inputs {
+		check := models.JobInputCheck{JobInput: input, Status: inputStatus(input.Path, input.SHA256)}
+		if input.SnapshotPath != "" {
+			check.SnapshotStatus = inputStatus(input.SnapshotPath, input.SHA256)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// snapshotReplacements maps each input of a job to its snapshot, failing when
+// an input was not snapshotted or its snapshot no longer matches.
+func snapshotReplacements(checks []models.JobInputCheck) (map[string]string, error) {
+	replacements := make(map[string]string, len(checks))
+	for _, check := range checks {
+		if check.SnapshotPath == "" {
+			return nil, fmt.Errorf("input %s has no snapshot", check.Path)
+		}
+		if check.SnapshotStatus != models.InputStatusUnchanged {
+			return nil, fmt.Errorf("snapshot of input %s is %s", check.Path, check.SnapshotStatus)
+		}
+		replacements[check.Path] = check.SnapshotPath
+	}
+	return replacements, nil
+}
+
+// replaceInputPaths returns a copy of a parameter value with every path found
+// in replacements swapped for its replacement.
+func replaceInputPaths(value interface{}, replacements map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if replacement, ok := replacements[v]; ok {
+			return replacement
+		}
+		return v
+	case []string:
+		replaced := make([]string, len(v))
+		for i, item := range v {
+			replaced[i] = replaceInputPaths(item, replacements).(string)
+		}
+		return replaced
+	case []interface{}:
+		replaced := make([]interface{}, len(v))
+		for i, item := range v {
+			replaced[i] = replaceInputPaths(item, replacements)
+		}
+		return replaced
+	case map[string]interface{}:
+		replaced := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			replaced[key] = replaceInputPaths(item, replacements)
+		}
+		return replaced
+	case models.JSONMap:
+		return models.JSONMap(replaceInputPaths(map[string]interface{}(v), replacements).(map[string]interface{}))
+	}
+	return value
+}
+
+// jobInputFiles returns the absolute paths of existing files among a job's
+// arguments and parameters. The first argument is the script or executable,
+// and files inside the output directory are outputs, except for snapshots.
+func jobInputFiles(args []string, parameters map[string]interface{}) []string {
+	outputDir, _ := parameters["outputDir"].(string)
+
+	candidates := inputPaths(parameters)
+	if len(args) > 1 {
+		candidates = append(candidates, args[1:]...)
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, path := range candidates {
+		if !filepath.IsAbs(path) || seen[path] {
+			continue
+		}
+		seen[path] = true
+		if outputDir != "" && isWithin(path, outputDir) && !isWithin(path, filepath.Join(outputDir, inputSnapshotDirName)) {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files
+}
+
+func isWithin(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// snapshotPath picks the snapshot location of an input, keeping its file name
+// and moving it into a numbered folder when another input has the same name.
+func snapshotPath(snapshotDir string, path string, used map[string]bool) string {
+	name := filepath.Base(path)
+	target := filepath.Join(snapshotDir, name)
+	for i := 2; used[target]; i++ {
+		target = filepath.Join(snapshotDir, fmt.Sprint(i), name)
+	}
+	used[target] = true
+	return target
+}
+
+// snapshotInput hardlinks or copies an input to target and returns its hash
+// and size. A hardlink falls back to a copy when the two paths are on
+// different volumes.
+func snapshotInput(path string, target string, mode string) (string, int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", 0, err
+	}
+	os.Remove(target)
+
+	if mode == models.InputSnapshotHardlink {
+		err := os.Link(path, target)
+		if err == nil {
+			return hashInput(path)
+		}
+		log.Printf("[snapshotInput] Failed to hardlink %s, copying it instead: %v", path, err)
+	}
+
+	source, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer source.Close()
+
+	destination, err := os.Create(target)
+	if err != nil {
+		return "", 0, err
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(destination, hash), source)
+	if closeErr := destination.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+func hashInput(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+func inputStatus(path string, recorded string) string {
+	hash, _, err := hashInput(path)
+	if err != nil {
+		return models.InputStatusMissing
+	}
+	if hash != recorded {
+		return models.InputStatusModified
+	}
+	return models.InputStatusUnchanged
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func writeInput(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordInputsSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	outputDir := filepath.Join(dir, "out")
+	first := filepath.Join(dir, "a", "data.tsv")
+	second := filepath.Join(dir, "b", "data.tsv")
+	for _, path := range []string{first, second} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		writeInput(t, path, "sample\tvalue\n"+path)
+	}
+	os.MkdirAll(outputDir, 0755)
+	writeInput(t, filepath.Join(outputDir, "previous.tsv"), "output")
+
+	for _, mode := range []string{models.InputSnapshotCopy, models.InputSnapshotHardlink} {
+		job := &models.Job{
+			ID:         mode,
+			Args:       models.StringArray{"/opt/script.py", "--input", first, "--previous", filepath.Join(outputDir, "previous.tsv")},
+			Parameters: models.JSONMap{"outputDir": outputDir, "files": []interface{}{second, "relative.tsv"}},
+		}
+		if err := recordInputs(job, mode); err != nil {
+			t.Fatalf("recordInputs(%s) failed: %v", mode, err)
+		}
+		if len(job.Inputs) != 2 {
+			t.Fatalf("Expected two inputs, got %+v", job.Inputs)
+		}
+		for _, input := range job.Inputs {
+			content, err := os.ReadFile(input.SnapshotPath)
+			if err != nil || !strings.HasSuffix(string(content), input.Path) {
+				t.Errorf("Expected %s to hold a snapshot of %s (%v)", input.SnapshotPath, input.Path, err)
+			}
+			if input.SHA256 == "" || input.Size != int64(len(content)) {
+				t.Errorf("Expected the hash and size of %s to be recorded, got %+v", input.Path, input)
+			}
+		}
+		if job.Inputs[0].SnapshotPath == job.Inputs[1].SnapshotPath {
+			t.Error("Expected inputs with the same name to get separate snapshots")
+		}
+	}
+
+	if err := recordInputs(&models.Job{}, "mirror"); err == nil {
+		t.Error("Expected an unknown snapshot mode to fail")
+	}
+}
+
+func TestRerunJobFromSnapshot(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	dir := t.TempDir()
+	input := filepath.Join(dir, "data.tsv")
+	outputDir := filepath.Join(dir, "out")
+	writeInput(t, input, "original")
+
+	id, err := jobQueue.CreateJobWithOptions("test", "Snapshot", "r", []string{"script.R", input},
+		map[string]interface{}{"outputDir": outputDir, "file": input},
+		models.JobOptions{SnapshotInputs: models.InputSnapshotCopy})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+	waitForJobStatus(t, db, id, models.JobStatusFailed)
+
+	writeInput(t, input, "edited")
+	checks, err := jobQueue.VerifyJobInputs(id)
+	if err != nil {
+		t.Fatalf("VerifyJobInputs failed: %v", err)
+	}
+	if len(checks) != 1 || checks[0].Status != models.InputStatusModified || checks[0].SnapshotStatus != models.InputStatusUnchanged {
+		t.Fatalf("Expected the input to be modified and its snapshot intact, got %+v", checks)
+	}
+
+	rerunID, err := jobQueue.RerunJob(id, true, "", "", true)
+	if err != nil {
+		t.Fatalf("RerunJob from snapshot failed: %v", err)
+	}
+	rerun, _ := jobQueue.GetJob(rerunID)
+	snapshot := checks[0].SnapshotPath
+	if rerun.Args[1] != snapshot || rerun.Parameters["file"] != snapshot {
+		t.Errorf("Expected the rerun to read the snapshot, got args %v and parameters %v", rerun.Args, rerun.Parameters)
+	}
+	if len(rerun.Inputs) != 1 || rerun.Inputs[0].Path != snapshot {
+		t.Errorf("Expected the rerun to record the snapshot as its input, got %+v", rerun.Inputs)
+	}
+
+	rerunID, err = jobQueue.RerunJob(id, true, "", "", false)
+	if err != nil {
+		t.Fatalf("RerunJob failed: %v", err)
+	}
+	page, err := jobQueue.Logs().GetJobLog(rerunID, 0, 10, "")
+	if err != nil || len(page.Lines) == 0 || !strings.Contains(page.Lines[0].Line, "modified") {
+		t.Errorf("Expected a warning about the modified input in the rerun's log, got %+v (%v)", page, err)
+	}
+
+	os.Remove(snapshot)
+	if _, err := jobQueue.RerunJob(id, true, "", "", true); err == nil {
+		t.Error("Expected a rerun from a missing snapshot to fail")
+	}
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+)
+
+const (
+	jobLogFlushInterval = 250 * time.Millisecond
+	jobLogBatchSize     = 500
+	jobLogTailSize      = 200
+	defaultJobLogLimit  = 1000
+	legacyErrorPrefix   = "[ERROR] "
+)
+
+// JobLogService stores job output one row per line, separate from the job
+// row, so that appending output does not rewrite the whole job.
+type JobLogService struct {
+	ctx context.Context
+	db  *DatabaseService
+}
+
+func NewJobLogService(ctx context.Context, db *DatabaseService) *JobLogService {
+	return &JobLogService{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// JobLogWriter buffers output lines of one job attempt and writes them to the
+// database and the UI in batches.
+type JobLogWriter struct {
+	service *JobLogService
+	jobID   string
+	attempt int
+
+	mu      sync.Mutex
+	flushMu sync.Mutex
+	seq     int64
+	pending []models.JobLogLine
+	tail    []string
+	errTail []string
+	done    chan struct{}
+	stopped chan struct{}
+	closed  bool
+}
+
+func (s *JobLogService) NewWriter(jobID string, attempt int) *JobLogWriter {
+	w := &JobLogWriter{
+		service: s,
+		jobID:   jobID,
+		attempt: attempt,
+		seq:     s.nextSeq(jobID),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.flushLoop()
+	return w
+}
+
+func (s *JobLogService) nextSeq(jobID string) int64 {
+	var maxSeq *int64
+	s.db.GetDB().Model(&models.JobLogLine{}).Where("job_id = ?", jobID).Select("MAX(seq)").Scan(&maxSeq)
+	if maxSeq == nil {
+		return 0
+	}
+	return *maxSeq + 1
+}
+
+// Write records a line of output. It is safe to call from the stdout and
+// stderr readers at the same time.
+func (w *JobLogWriter) Write(stream string, line string) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+
+	w.pending = append(w.pending, models.JobLogLine{
+		JobID:     w.jobID,
+		Seq:       w.seq,
+		Attempt:   w.attempt,
+		Stream:    stream,
+		Line:      line,
+		Timestamp: time.Now(),
+	})
+	w.seq++
+
+	w.tail = append(w.tail, line)
+	if len(w.tail) > jobLogTailSize {
+		w.tail = w.tail[len(w.tail)-jobLogTailSize:]
+	}
+	if stream == models.LogStreamStderr {
+		w.errTail = append(w.errTail, line)
+		if len(w.errTail) > jobLogTailSize {
+			w.errTail = w.errTail[len(w.errTail)-jobLogTailSize:]
+		}
+	}
+
+	full := len(w.pending) >= jobLogBatchSize
+	w.mu.Unlock()
+
+	if full {
+		w.flush()
+	}
+}
+
+// Tail returns the most recent lines written, oldest first.
+func (w *JobLogWriter) Tail() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string{}, w.tail...)
+}
+
+// StderrTail returns the most recent lines written to stderr, oldest first.
+func (w *JobLogWriter) StderrTail() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string{}, w.errTail...)
+}
+
+// Close flushes any buffered lines and stops the writer.
+func (w *JobLogWriter) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+	<-w.stopped
+	w.flush()
+}
+
+func (w *JobLogWriter) flushLoop() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(jobLogFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.flush()
+		}
+	}
+}
+
+func (w *JobLogWriter) flush() {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	lines := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	if len(lines) == 0 {
+		return
+	}
+
+	if err := w.service.db.GetDB().CreateInBatches(lines, 200).Error; err != nil {
+		log.Printf("[JobLogWriter] Failed to store %d log lines for job %s: %v", len(lines), w.jobID, err)
+	}
+
+	if w.service.ctx.Value("wails-test") == nil {
+		runtime.EventsEmit(w.service.ctx, "job:log", map[string]interface{}{
+			"jobId": w.jobID,
+			"lines": lines,
+		})
+	}
+}
+
+// AppendLine stores a single line immediately, for output that does not come
+// from a running process.
+func (s *JobLogService) AppendLine(jobID string, attempt int, stream string, line string) error {
+	entry := models.JobLogLine{
+		JobID:     jobID,
+		Seq:       s.nextSeq(jobID),
+		Attempt:   attempt,
+		Stream:    stream,
+		Line:      line,
+		Timestamp: time.Now(),
+	}
+	if err := s.db.GetDB().Create(&entry).Error; err != nil {
+		return err
+	}
+
+	if s.ctx.Value("wails-test") == nil {
+		runtime.EventsEmit(s.ctx, "job:log", map[string]interface{}{
+			"jobId": jobID,
+			"lines": []models.JobLogLine{entry},
+		})
+	}
+	return nil
+}
+
+// lineCounts returns how many lines of each stream a job attempt has logged.
+func (s *JobLogService) lineCounts(jobID string, attempt int) map[string]int {
+	var rows []struct {
+		Stream string
+		Count  int
+	}
+	s.db.GetDB().Model(&models.JobLogLine{}).Select("stream, COUNT(*) AS count").
+		Where("job_id = ? AND attempt = ?", jobID, attempt).Group("stream").Scan(&rows)
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Stream] = row.Count
+	}
+	return counts
+}
+
+// ImportLines appends previously recorded lines to a job's log, keeping
+// their timestamps and streams.
+func (s *JobLogService) ImportLines(jobID string, lines []models.JobLogLine) error {
+	seq := s.nextSeq(jobID)
+	for i := range lines {
+		lines[i].ID = 0
+		lines[i].JobID = jobID
+		lines[i].Seq = seq
+		seq++
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return s.db.GetDB().CreateInBatches(lines, 200).Error
+}
+
+// GetJobLog returns a page of a job's output in order. stream filters to
+// "stdout" or "stderr"; an empty stream or "all" returns both. A negative
+// offset counts back from the end, so -1 with a limit of 100 returns the last
+// 100 lines.
+func (s *JobLogService) GetJobLog(jobID string, offset int, limit int, stream string) (*models.JobLogPage, error) {
+	if limit <= 0 {
+		limit = defaultJobLogLimit
+	}
+	if stream == "all" {
+		stream = ""
+	}
+
+	query := s.db.GetDB().Model(&models.JobLogLine{}).Where("job_id = ?", jobID)
+	if stream != "" {
+		query = query.Where("stream = ?", stream)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if total == 0 {
+		legacy, err := s.legacyLines(jobID, stream)
+		if err != nil {
+			return nil, err
+		}
+		if len(legacy) > 0 {
+			return pageOf(jobID, legacy, offset, limit), nil
+		}
+	}
+
+	if offset < 0 {
+		offset = int(total) - limit
+		if offset < 0 {
+			offset = 0
+		}
+	}
+
+	lines := []models.JobLogLine{}
+	if err := query.Order("seq ASC").Offset(offset).Limit(limit).Find(&lines).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.JobLogPage{
+		JobID:  jobID,
+		Lines:  lines,
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+	}, nil
+}
+
+// legacyLines converts the terminal output stored on jobs created before the
+// log store existed, including jobs in the trash. A job that is gone has no
+// legacy lines.
+func (s *JobLogService) legacyLines(jobID string, stream string) ([]models.JobLogLine, error) {
+	var job models.Job
+	err := s.db.GetDB().Unscoped().Select("id", "terminal_output", "created_at").First(&job, "id = ?", jobID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []models.JobLogLine{}
+	for i, line := range job.TerminalOutput {
+		lineStream := models.LogStreamStdout
+		if strings.HasPrefix(line, legacyErrorPrefix) {
+			lineStream = models.LogStreamStderr
+			line = strings.TrimPrefix(line, legacyErrorPrefix)
+		}
+		if stream != "" && stream != lineStream {
+			continue
+		}
+		lines = append(lines, models.JobLogLine{
+			JobID:     jobID,
+			Seq:       int64(i),
+			Attempt:   1,
+			Stream:    lineStream,
+			Line:      line,
+			Timestamp: job.CreatedAt,
+		})
+	}
+	return lines, nil
+}
+
+func pageOf(jobID string, lines []models.JobLogLine, offset int, limit int) *models.JobLogPage {
+	total := len(lines)
+	if offset < 0 {
+		offset = total - limit
+		if offset < 0 {
+			offset = 0
+		}
+	}
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	return &models.JobLogPage{
+		JobID:  jobID,
+		Lines:  lines[offset:end],
+		Total:  int64(total),
+		Offset: offset,
+		Limit:  limit,
+	}
+}
+
+func (s *JobLogService) DeleteJobLog(jobID string) error {
+	return s.db.GetDB().Where("job_id = ?", jobID).Delete(&models.JobLogLine{}).Error
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestJobLogStoresStreamsAndPages(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	script := "for i in 1 2 3 4 5; do echo out$i; done; echo err1 >&2"
+	jobID, err := jobQueue.CreateJob("test", "Chatty", "direct", []string{"sh", "-c", script})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+	waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+
+	all, err := jobQueue.Logs().GetJobLog(jobID, 0, 0, "all")
+	if err != nil {
+		t.Fatalf("GetJobLog failed: %v", err)
+	}
+	if all.Total != 6 || len(all.Lines) != 6 {
+		t.Fatalf("Expected 6 lines, got total %d with %d lines", all.Total, len(all.Lines))
+	}
+	for i := 1; i < len(all.Lines); i++ {
+		if all.Lines[i].Seq <= all.Lines[i-1].Seq {
+			t.Errorf("Lines are not in sequence order: %+v", all.Lines)
+		}
+	}
+
+	stderr, err := jobQueue.Logs().GetJobLog(jobID, 0, 0, models.LogStreamStderr)
+	if err != nil {
+		t.Fatalf("GetJobLog failed: %v", err)
+	}
+	if stderr.Total != 1 || stderr.Lines[0].Line != "err1" || stderr.Lines[0].Stream != models.LogStreamStderr {
+		t.Errorf("Expected only the stderr line, got %+v", stderr.Lines)
+	}
+
+	page, err := jobQueue.Logs().GetJobLog(jobID, 1, 2, models.LogStreamStdout)
+	if err != nil {
+		t.Fatalf("GetJobLog failed: %v", err)
+	}
+	if page.Total != 5 || len(page.Lines) != 2 || page.Lines[0].Line != "out2" || page.Lines[1].Line != "out3" {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+
+	tail, err := jobQueue.Logs().GetJobLog(jobID, -1, 2, models.LogStreamStdout)
+	if err != nil {
+		t.Fatalf("GetJobLog failed: %v", err)
+	}
+	if tail.Offset != 3 || len(tail.Lines) != 2 || tail.Lines[1].Line != "out5" {
+		t.Errorf("Unexpected tail: %+v", tail)
+	}
+
+	var job models.Job
+	db.GetDB().First(&job, "id = ?", jobID)
+	if len(job.TerminalOutput) != 0 {
+		t.Errorf("Expected output to stay out of the job row, got %v", job.TerminalOutput)
+	}
+}
+
+func TestJobLogWriterBatchesLines(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+
+	writer := jobQueue.Logs().NewWriter("job-1", 1)
+	for i := 0; i < jobLogBatchSize+10; i++ {
+		writer.Write(models.LogStreamStdout, fmt.Sprintf("line %d", i))
+	}
+	writer.Close()
+
+	var count int64
+	db.GetDB().Model(&models.JobLogLine{}).Where("job_id = ?", "job-1").Count(&count)
+	if count != jobLogBatchSize+10 {
+		t.Errorf("Expected %d stored lines, got %d", jobLogBatchSize+10, count)
+	}
+	if tail := writer.Tail(); len(tail) != jobLogTailSize || tail[len(tail)-1] != fmt.Sprintf("line %d", jobLogBatchSize+9) {
+		t.Errorf("Unexpected tail of %d lines ending %q", len(tail), tail[len(tail)-1])
+	}
+
+	next := jobQueue.Logs().NewWriter("job-1", 2)
+	next.Write(models.LogStreamStderr, "second attempt")
+	next.Close()
+
+	page, err := jobQueue.Logs().GetJobLog("job-1", -1, 1, "")
+	if err != nil {
+		t.Fatalf("GetJobLog failed: %v", err)
+	}
+	if page.Lines[0].Seq != jobLogBatchSize+10 || page.Lines[0].Attempt != 2 {
+		t.Errorf("Expected the second attempt to continue the sequence, got %+v", page.Lines[0])
+	}
+}
+
+func TestJobLogFallsBackToLegacyOutput(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+
+	job := &models.Job{
+		ID:             "legacy",
+		Type:           "test",
+		Name:           "Legacy",
+		Status:         models.JobStatusCompleted,
+		TerminalOutput: []string{"hello", "[ERROR] oops"},
+		CreatedAt:      time.Now(),
+	}
+	if err := db.GetDB().Create(job).Error; err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	page, err := jobQueue.Logs().GetJobLog("legacy", 0, 0, models.LogStreamStderr)
+	if err != nil {
+		t.Fatalf("GetJobLog failed: %v", err)
+	}
+	if page.Total != 1 || page.Lines[0].Line != "oops" {
+		t.Errorf("Expected the legacy stderr line, got %+v", page.Lines)
+	}
+
+	if err := db.GetDB().Delete(job).Error; err != nil {
+		t.Fatalf("Failed to trash job: %v", err)
+	}
+	page, err = jobQueue.Logs().GetJobLog("legacy", 0, 0, "")
+	if err != nil || page.Total != 2 {
+		t.Errorf("Expected the legacy output of a trashed job, got %+v, %v", page, err)
+	}
+
+	page, err = jobQueue.Logs().GetJobLog("missing", 0, 0, "")
+	if err != nil || page.Total != 0 {
+		t.Errorf("Expected an empty page for an unknown job, got %+v, %v", page, err)
+	}
+}
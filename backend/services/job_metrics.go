@@ -0,0 +1,83 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const (
+	defaultRuntimeHistoryLimit = 50
+	maxRuntimeHistoryLimit     = 500
+)
+
+// PluginRuntimeHistory returns the resource usage of the most recent
+// finished runs of a plugin in every project. pluginID may also be a
+// built-in job type.
+func (j *JobQueueService) PluginRuntimeHistory(pluginID string, limit int) (*models.PluginRuntimeHistory, error) {
+	if pluginID == "" {
+		return nil, fmt.Errorf("plugin ID is required")
+	}
+	if limit <= 0 {
+		limit = defaultRuntimeHistoryLimit
+	} else if limit > maxRuntimeHistoryLimit {
+		limit = maxRuntimeHistoryLimit
+	}
+
+	query := models.JobQuery{
+		PluginID:    pluginID,
+		AllProjects: true,
+		Statuses:    []models.JobStatus{models.JobStatusCompleted, models.JobStatusFailed},
+	}
+	var jobs []models.Job
+	if err := j.filterJobs(j.db.GetDB().Model(&models.Job{}), query).
+		Where("started_at IS NOT NULL").
+		Order("created_at DESC").Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load runtime history: %w", err)
+	}
+
+	history := &models.PluginRuntimeHistory{PluginID: pluginID, Runs: make([]models.JobRuntime, 0, len(jobs))}
+	var durations, cpuSeconds []float64
+	for _, job := range jobs {
+		history.Runs = append(history.Runs, models.JobRuntime{
+			JobID:           job.ID,
+			Name:            job.Name,
+			ProjectID:       job.ProjectID,
+			Status:          job.Status,
+			CreatedAt:       job.CreatedAt,
+			DurationSeconds: job.DurationSeconds,
+			Metrics:         job.Metrics,
+		})
+
+		if job.Status != models.JobStatusCompleted {
+			history.Failed++
+			continue
+		}
+		history.Completed++
+		if job.DurationSeconds != nil {
+			durations = append(durations, *job.DurationSeconds)
+			history.MaxDurationSeconds = max(history.MaxDurationSeconds, *job.DurationSeconds)
+		}
+		if job.Metrics.ExitCode != nil {
+			cpuSeconds = append(cpuSeconds, job.Metrics.CPUSeconds())
+		}
+		history.MaxMemoryBytes = max(history.MaxMemoryBytes, job.Metrics.PeakMemoryBytes())
+	}
+	history.MedianDurationSeconds = median(durations)
+	history.MedianCPUSeconds = median(cpuSeconds)
+	return history, nil
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestJobRecordsProcessMetrics(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+	id, err := jobQueue.CreateJobWithOptions("test", "Exit", "direct", []string{"sh", "-c", "exit 4"}, nil, models.JobOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	job := waitForJobStatus(t, db, id, models.JobStatusFailed)
+	if job.Metrics.ExitCode == nil || *job.Metrics.ExitCode != 4 {
+		t.Errorf("Expected exit code 4 to be stored, got %+v", job.Metrics)
+	}
+}
+
+func TestPluginRuntimeHistory(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+
+	exitCode := 0
+	base := time.Now().Add(-time.Hour)
+	runs := []struct {
+		id       string
+		plugin   string
+		status   models.JobStatus
+		seconds  int
+		cpu      float64
+		memoryMB int64
+	}{
+		{"a", "limma", models.JobStatusCompleted, 10, 8, 100},
+		{"b", "limma", models.JobStatusCompleted, 30, 20, 300},
+		{"c", "limma", models.JobStatusFailed, 2, 1, 900},
+		{"d", "limma", models.JobStatusCompleted, 20, 12, 200},
+		{"e", "pca", models.JobStatusCompleted, 50, 40, 50},
+	}
+	for i, run := range runs {
+		startedAt := base.Add(time.Duration(i) * time.Minute)
+		completedAt := startedAt.Add(time.Duration(run.seconds) * time.Second)
+		job := models.Job{
+			ID:          run.id,
+			Type:        "plugin",
+			Name:        "Run " + run.id,
+			Command:     "python",
+			ProjectID:   models.DefaultProjectID,
+			Parameters:  models.JSONMap{"pluginId": run.plugin},
+			Status:      run.status,
+			CreatedAt:   startedAt,
+			StartedAt:   &startedAt,
+			CompletedAt: &completedAt,
+			Metrics: models.ProcessMetrics{
+				ExitCode:       &exitCode,
+				UserCPUSeconds: run.cpu,
+				MaxRSSBytes:    run.memoryMB << 20,
+			},
+		}
+		if err := db.GetDB().Create(&job).Error; err != nil {
+			t.Fatalf("Failed to create job: %v", err)
+		}
+	}
+
+	history, err := jobQueue.PluginRuntimeHistory("limma", 0)
+	if err != nil {
+		t.Fatalf("PluginRuntimeHistory failed: %v", err)
+	}
+	if len(history.Runs) != 4 || history.Runs[0].JobID != "d" {
+		t.Fatalf("Expected the four limma runs newest first, got %+v", history.Runs)
+	}
+	if history.Completed != 3 || history.Failed != 1 {
+		t.Errorf("Expected 3 completed and 1 failed run, got %d and %d", history.Completed, history.Failed)
+	}
+	if history.MedianDurationSeconds != 20 || history.MaxDurationSeconds != 30 {
+		t.Errorf("Expected a median of 20s and a maximum of 30s, got %v and %v",
+			history.MedianDurationSeconds, history.MaxDurationSeconds)
+	}
+	if history.MedianCPUSeconds != 12 {
+		t.Errorf("Expected a median CPU time of 12s, got %v", history.MedianCPUSeconds)
+	}
+	if history.MaxMemoryBytes != 300<<20 {
+		t.Errorf("Expected failed runs to be left out of the peak memory, got %d", history.MaxMemoryBytes)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+// jobCommand is the process a queued job runs as. Script is the script
+// the interpreter runs, empty for direct executables. ConsoleDir is where
+// its output is spooled, empty for jobs without an output directory.
+type jobCommand struct {
+	Runtime    string
+	Argv       []string
+	Script     string
+	WorkingDir string
+	ConsoleDir string
+	Env        []string
+}
+
+// jobRuntime is the worker runtime a job's command runs under. Anything that
+// is not R or a direct executable, including pythonWithR, runs as Python.
+func jobRuntime(command string) string {
+	switch command {
+	case WorkerRuntimeR, WorkerRuntimeDirect:
+		return command
+	}
+	return WorkerRuntimePython
+}
+
+// resolveExecution works out how a job runs: its runtime, the interpreter
+// from the environment recorded on the job or the configured default, the
+// script path, the working directory and extra environment variables. Every
+// job runs through here, whether it came from a plugin, a batch, a rerun or
+// one of the built-in analyses.
+func (j *JobQueueService) resolveExecution(job *models.Job) (*jobCommand, error) {
+	if len(job.Args) == 0 {
+		return nil, fmt.Errorf("job has no command to run")
+	}
+
+	command := &jobCommand{Runtime: jobRuntime(job.Command)}
+	if outputDir, ok := job.Parameters["outputDir"].(string); ok && outputDir != "" {
+		command.WorkingDir = outputDir
+		command.ConsoleDir = filepath.Join(outputDir, jobConsoleDirName)
+	}
+
+	switch command.Runtime {
+	case WorkerRuntimeR:
+		if j.rRunner == nil {
+			return nil, fmt.Errorf("R runner not initialized")
+		}
+		command.Argv = j.rRunner.Argv(job.Args[0], job.Args[1:])
+		if job.REnvPath != "" {
+			command.Argv[0] = job.REnvPath
+		}
+		command.Script = command.Argv[1]
+		command.Env = j.rRunner.Env()
+	case WorkerRuntimeDirect:
+		if j.directRunner == nil {
+			return nil, fmt.Errorf("Direct runner not initialized")
+		}
+		command.Argv = j.directRunner.Argv(job.Args[0], job.Args[1:])
+	default:
+		if j.pythonRunner == nil {
+			return nil, fmt.Errorf("Python runner not initialized")
+		}
+		command.Argv = j.pythonRunner.Argv(job.Args[0], job.Args[1:])
+		if job.PythonEnvPath != "" {
+			command.Argv[0] = job.PythonEnvPath
+		}
+		command.Script = command.Argv[1]
+		command.Env = j.pythonRunner.Env()
+		if job.Command == string(models.PluginRuntimePythonWithR) && j.rRunner != nil {
+			command.Env = append(command.Env, j.rRunner.Env()...)
+		}
+	}
+
+	if command.Argv[0] == "" {
+		return nil, fmt.Errorf("%s interpreter not configured", command.Runtime)
+	}
+	return command, nil
+}
+
+// run starts the job's process and waits for it to exit.
+func (c *jobCommand) run(ctx context.Context, limits models.ExecutionLimits, outputCallback func(stream string, line string)) error {
+	if c.Script != "" {
+		if _, err := os.Stat(c.Script); os.IsNotExist(err) {
+			return fmt.Errorf("script not found: %s", c.Script)
+		}
+	}
+
+	cmd := exec.Command(c.Argv[0], c.Argv[1:]...)
+	if c.WorkingDir != "" {
+		if err := os.MkdirAll(c.WorkingDir, 0755); err != nil {
+			return fmt.Errorf("failed to create working directory: %w", err)
+		}
+		cmd.Dir = c.WorkingDir
+	}
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	if c.ConsoleDir != "" {
+		ctx = withConsoleSpool(ctx, c.ConsoleDir)
+	}
+	return runProcess(ctx, cmd, limits, outputCallback)
+}
+
+// QueuePluginJob queues a prepared plugin run under the plugin's runtime.
+func (j *JobQueueService) QueuePluginJob(plugin *models.PluginV2, name string, prepared *PluginJob) (string, error) {
+	jobIDs, err := j.QueuePluginJobs(plugin, []string{name}, []*PluginJob{prepared})
+	if err != nil {
+		return "", err
+	}
+	return jobIDs[0], nil
+}
+
+// QueuePluginJobs queues several prepared runs of a plugin, named by names,
+// as a whole: either every run is queued or none is, and no worker can start
+// one before all of them are stored.
+func (j *JobQueueService) QueuePluginJobs(plugin *models.PluginV2, names []string, prepared []*PluginJob) ([]string, error) {
+	jobs := make([]*models.Job, len(prepared))
+	for i, run := range prepared {
+		if outputDir, ok := run.Parameters["outputDir"].(string); ok && outputDir != "" {
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				return nil, fmt.Errorf("failed to create output directory: %w", err)
+			}
+		}
+
+		job, err := j.newJob(plugin.Definition.Plugin.ID, names[i], plugin.Definition.Runtime.Type,
+			run.Args, run.Parameters, run.Options)
+		if err != nil {
+			return nil, err
+		}
+		jobs[i] = job
+	}
+
+	if err := j.storeJobs(jobs); err != nil {
+		return nil, err
+	}
+	jobIDs := make([]string, len(jobs))
+	for i, job := range jobs {
+		jobIDs[i] = job.ID
+		log.Printf("[QueuePluginJob] Queued job %s for plugin %s", job.ID, plugin.Definition.Plugin.ID)
+	}
+	return jobIDs, nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestResolveExecution(t *testing.T) {
+	t.Setenv("PYTHONPATH", "")
+	jobQueue := &JobQueueService{
+		pythonRunner: &PythonRunner{pythonPath: "/usr/bin/python3", scriptDir: "/app/scripts/python"},
+		rRunner:      &RRunner{rscriptPath: "/usr/bin/Rscript", rLibPath: "/app/rlib", scriptDir: "/app/scripts/r"},
+		directRunner: &DirectRunner{binDir: "/app/bin/external"},
+	}
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	tests := []struct {
+		name    string
+		job     models.Job
+		runtime string
+		argv    []string
+		env     []string
+	}{
+		{
+			name:    "bundled python script in the job's environment",
+			job:     models.Job{Command: "python", Args: []string{"pca.py", "--n", "2"}, PythonEnvPath: "/envs/ms/bin/python"},
+			runtime: WorkerRuntimePython,
+			argv:    []string{"/envs/ms/bin/python", filepath.Join("/app/scripts/python", "pca.py"), "--n", "2"},
+			env:     []string{"PYTHONPATH=/app/scripts/python"},
+		},
+		{
+			name:    "plugin script with the default interpreter",
+			job:     models.Job{Command: "python", Args: []string{"/plugins/qc/qc.py"}},
+			runtime: WorkerRuntimePython,
+			argv:    []string{"/usr/bin/python3", "/plugins/qc/qc.py"},
+			env:     []string{"PYTHONPATH=/app/scripts/python"},
+		},
+		{
+			name:    "python with R gets the R libraries",
+			job:     models.Job{Command: "pythonWithR", Args: []string{"/plugins/rpy/run.py"}},
+			runtime: WorkerRuntimePython,
+			argv:    []string{"/usr/bin/python3", "/plugins/rpy/run.py"},
+			env:     []string{"PYTHONPATH=/app/scripts/python", "R_LIBS=/app/rlib"},
+		},
+		{
+			name:    "R script in the job's environment",
+			job:     models.Job{Command: "r", Args: []string{"correlation_matrix.R"}, REnvPath: "/opt/R/bin/Rscript"},
+			runtime: WorkerRuntimeR,
+			argv:    []string{"/opt/R/bin/Rscript", filepath.Join("/app/scripts/r", "correlation_matrix.R")},
+			env:     []string{"R_LIBS=/app/rlib"},
+		},
+		{
+			name:    "direct executable",
+			job:     models.Job{Command: "direct", Args: []string{"/opt/tools/diann", "--threads", "4"}},
+			runtime: WorkerRuntimeDirect,
+			argv:    []string{"/opt/tools/diann", "--threads", "4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.job.Parameters = map[string]interface{}{"outputDir": outputDir}
+			command, err := jobQueue.resolveExecution(&tt.job)
+			if err != nil {
+				t.Fatalf("resolveExecution failed: %v", err)
+			}
+			if command.Runtime != tt.runtime || !slices.Equal(command.Argv, tt.argv) || !slices.Equal(command.Env, tt.env) {
+				t.Errorf("Unexpected command: %+v", command)
+			}
+			if command.WorkingDir != outputDir {
+				t.Errorf("Expected the job to run in %s, got %s", outputDir, command.WorkingDir)
+			}
+		})
+	}
+
+	if _, err := (&JobQueueService{}).resolveExecution(&models.Job{Command: "r", Args: []string{"x.R"}}); err == nil || err.Error() != "R runner not initialized" {
+		t.Errorf("Expected a missing R runner to be reported, got %v", err)
+	}
+}
+
+func TestQueuePluginJobRunsOnceInOutputDir(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+	dir := t.TempDir()
+	script := filepath.Join(dir, "record.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\npwd >> runs.txt\n"), 0755); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+
+	plugin := &models.PluginV2{
+		ScriptPath: script,
+		Definition: models.PluginDefinition{
+			Plugin:  models.PluginMetadata{ID: "record", Name: "Record"},
+			Runtime: models.PluginRuntimeV2{Type: "direct"},
+		},
+	}
+	outputDir := filepath.Join(dir, "record_run")
+	prepared, err := NewPluginExecutor().PrepareJob(plugin, nil, models.JobOptions{}, outputDir)
+	if err != nil {
+		t.Fatalf("PrepareJob failed: %v", err)
+	}
+	id, err := jobQueue.QueuePluginJob(plugin, "Record", prepared)
+	if err != nil {
+		t.Fatalf("QueuePluginJob failed: %v", err)
+	}
+
+	job := waitForJobStatus(t, db, id, models.JobStatusCompleted)
+	if job.OutputPath != outputDir {
+		t.Errorf("Expected the output path to be %s, got %s", outputDir, job.OutputPath)
+	}
+	if job.ConsolePath != filepath.Join(outputDir, jobConsoleDirName) {
+		t.Errorf("Expected the console to be spooled under the output directory, got %q", job.ConsolePath)
+	}
+	artifacts, _ := jobQueue.Artifacts().GetJobArtifacts(id)
+	if len(artifacts) != 1 || artifacts[0].Path != "runs.txt" {
+		t.Errorf("Expected only runs.txt as an artifact, got %+v", artifacts)
+	}
+	runs, err := os.ReadFile(filepath.Join(outputDir, "runs.txt"))
+	if err != nil {
+		t.Fatalf("Expected the plugin to run in its output directory: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(runs)), "\n")
+	resolved, _ := filepath.EvalSymlinks(outputDir)
+	if len(lines) != 1 || (lines[0] != outputDir && lines[0] != resolved) {
+		t.Errorf("Expected the plugin to run exactly once in %s, got %q", outputDir, lines)
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultJobQueryLimit = 50
+	maxJobQueryLimit     = 500
+)
+
+// jobQueryCursor is the position after the last job of a page: its sort
+// value and ID, which breaks ties between jobs with the same value.
+type jobQueryCursor struct {
+	Time     *time.Time `json:"t,omitempty"`
+	Duration *float64   `json:"d,omitempty"`
+	ID       string     `json:"id"`
+}
+
+// QueryJobs returns one page of jobs matching the query, ordered by the
+// requested sort key. Pages are keyed on the last job seen rather than an
+// offset, so jobs created while paging do not shift the results.
+func (j *JobQueueService) QueryJobs(query models.JobQuery) (*models.JobQueryPage, error) {
+	sortExpr, err := jobSortExpression(query.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultJobQueryLimit
+	} else if limit > maxJobQueryLimit {
+		limit = maxJobQueryLimit
+	}
+
+	filtered := j.filterJobs(j.db.GetDB().Model(&models.Job{}), query)
+
+	page := &models.JobQueryPage{Jobs: []models.Job{}}
+	if err := filtered.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+
+	direction, comparison := "DESC", "<"
+	if query.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	rows := filtered.Session(&gorm.Session{})
+	if query.Cursor != "" {
+		cursor, err := decodeJobCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		value := cursorSortValue(query.SortBy, cursor)
+		rows = rows.Where(fmt.Sprintf("(%s %s ?) OR (%s = ? AND id %s ?)", sortExpr, comparison, sortExpr, comparison),
+			value, value, cursor.ID)
+	}
+
+	err = rows.Order(fmt.Sprintf("%s %s, id %s", sortExpr, direction, direction)).
+		Limit(limit + 1).
+		Find(&page.Jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+
+	if len(page.Jobs) > limit {
+		page.Jobs = page.Jobs[:limit]
+		page.NextCursor = encodeJobCursor(query.SortBy, &page.Jobs[limit-1])
+	}
+	return page, nil
+}
+
+func (j *JobQueueService) filterJobs(db *gorm.DB, query models.JobQuery) *gorm.DB {
+	if !query.AllProjects {
+		projectID := query.ProjectID
+		if projectID == "" {
+			projectID = j.projects.ActiveProjectID()
+		}
+		db = db.Where("project_id = ?", projectID)
+	}
+	if len(query.Statuses) > 0 {
+		db = db.Where("status IN ?", query.Statuses)
+	}
+	if query.PluginID != "" {
+		db = db.Where("json_extract(parameters, '$.pluginId') = ? OR type = ?", query.PluginID, query.PluginID)
+	}
+	if query.EnvPath != "" {
+		db = db.Where("python_env_path = ? OR r_env_path = ?", query.EnvPath, query.EnvPath)
+	}
+	if query.Tag != "" {
+		db = db.Where("EXISTS (SELECT 1 FROM json_each(jobs.tags) WHERE json_each.value = ?)", query.Tag)
+	}
+	if search := strings.TrimSpace(query.Search); search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		db = db.Where("LOWER(name) LIKE ? OR LOWER(type) LIKE ?", pattern, pattern)
+	}
+	if query.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *query.CreatedAfter)
+	}
+	if query.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *query.CreatedBefore)
+	}
+	return db
+}
+
+// jobSortExpression maps a sort key to the column it orders by. Jobs that
+// never started or finished sort as the smallest value.
+func jobSortExpression(sortBy string) (string, error) {
+	switch sortBy {
+	case "", models.JobSortCreated:
+		return "created_at", nil
+	case models.JobSortStarted:
+		return "COALESCE(started_at, '')", nil
+	case models.JobSortDuration:
+		return "COALESCE(duration_seconds, -1)", nil
+	}
+	return "", fmt.Errorf("unknown sort key: %s", sortBy)
+}
+
+func cursorSortValue(sortBy string, cursor *jobQueryCursor) interface{} {
+	switch sortBy {
+	case models.JobSortDuration:
+		if cursor.Duration == nil {
+			return -1
+		}
+		return *cursor.Duration
+	case models.JobSortStarted:
+		if cursor.Time == nil {
+			return ""
+		}
+	}
+	if cursor.Time == nil {
+		return time.Time{}
+	}
+	return cursor.Time.Local()
+}
+
+func encodeJobCursor(sortBy string, job *models.Job) string {
+	cursor := jobQueryCursor{ID: job.ID}
+	switch sortBy {
+	case models.JobSortStarted:
+		cursor.Time = job.StartedAt
+	case models.JobSortDuration:
+		cursor.Duration = job.DurationSeconds
+	default:
+		cursor.Time = &job.CreatedAt
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeJobCursor(encoded string) (*jobQueryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var cursor jobQueryCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func seedQueryJobs(t *testing.T, db *DatabaseService) {
+	t.Helper()
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 12; i++ {
+		created := base.Add(time.Duration(i) * time.Minute)
+		started := created.Add(time.Second)
+		completed := started.Add(time.Duration(12-i) * time.Second)
+
+		job := models.Job{
+			ID:          fmt.Sprintf("job-%02d", i),
+			Type:        "plugin",
+			Name:        fmt.Sprintf("Run %d", i),
+			ProjectID:   models.DefaultProjectID,
+			Status:      models.JobStatusCompleted,
+			Command:     "python",
+			Parameters:  models.JSONMap{"pluginId": "limma"},
+			CreatedAt:   created,
+			StartedAt:   &started,
+			CompletedAt: &completed,
+		}
+		if i%3 == 0 {
+			job.Status = models.JobStatusFailed
+			job.Parameters = models.JSONMap{"pluginId": "pca"}
+			job.PythonEnvPath = "/envs/old"
+		}
+		if i%4 == 0 {
+			job.Tags = models.StringArray{"paper", "batch-1"}
+		}
+		if err := db.GetDB().Create(&job).Error; err != nil {
+			t.Fatalf("Failed to create job: %v", err)
+		}
+	}
+
+	pending := models.Job{ID: "job-pending", Type: "plugin", Name: "Waiting", ProjectID: models.DefaultProjectID,
+		Status: models.JobStatusPending, Command: "python", CreatedAt: base.Add(-time.Minute),
+		RunAfter: func() *time.Time { t := time.Now().Add(time.Hour); return &t }()}
+	db.GetDB().Create(&pending)
+	db.GetDB().Create(&models.Job{ID: "job-other", Type: "plugin", Name: "Other project", ProjectID: "other",
+		Status: models.JobStatusCompleted, Command: "python", CreatedAt: base})
+}
+
+func queryJobIDs(page *models.JobQueryPage) []string {
+	ids := make([]string, len(page.Jobs))
+	for i, job := range page.Jobs {
+		ids[i] = job.ID
+	}
+	return ids
+}
+
+func TestQueryJobsFilters(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	seedQueryJobs(t, db)
+
+	tests := []struct {
+		name  string
+		query models.JobQuery
+		want  int64
+	}{
+		{"active project", models.JobQuery{}, 13},
+		{"all projects", models.JobQuery{AllProjects: true}, 14},
+		{"status", models.JobQuery{Statuses: []models.JobStatus{models.JobStatusFailed}}, 4},
+		{"plugin", models.JobQuery{PluginID: "pca"}, 4},
+		{"environment", models.JobQuery{EnvPath: "/envs/old"}, 4},
+		{"tag", models.JobQuery{Tag: "paper"}, 3},
+		{"search", models.JobQuery{Search: "run 1"}, 3},
+		{"plugin and status", models.JobQuery{PluginID: "limma", Statuses: []models.JobStatus{models.JobStatusFailed}}, 0},
+		{"created after", models.JobQuery{CreatedAfter: func() *time.Time { t := time.Now().Add(-50*time.Minute - 30*time.Second); return &t }()}, 2},
+	}
+
+	for _, tt := range tests {
+		page, err := jobQueue.QueryJobs(tt.query)
+		if err != nil {
+			t.Fatalf("%s: QueryJobs failed: %v", tt.name, err)
+		}
+		if page.Total != tt.want || int64(len(page.Jobs)) != tt.want {
+			t.Errorf("%s: expected %d jobs, got total %d and %v", tt.name, tt.want, page.Total, queryJobIDs(page))
+		}
+	}
+
+	if _, err := jobQueue.QueryJobs(models.JobQuery{SortBy: "name"}); err == nil {
+		t.Error("Expected an unknown sort key to be rejected")
+	}
+	if _, err := jobQueue.QueryJobs(models.JobQuery{Cursor: "not a cursor"}); err == nil {
+		t.Error("Expected an invalid cursor to be rejected")
+	}
+}
+
+func TestQueryJobsPagesWithCursor(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	seedQueryJobs(t, db)
+
+	tests := []struct {
+		sortBy    string
+		ascending bool
+		first     string
+		last      string
+	}{
+		{models.JobSortCreated, false, "job-11", "job-pending"},
+		{models.JobSortCreated, true, "job-pending", "job-11"},
+		{models.JobSortStarted, false, "job-11", "job-pending"},
+		{models.JobSortDuration, false, "job-00", "job-pending"},
+		{models.JobSortDuration, true, "job-pending", "job-00"},
+	}
+
+	for _, tt := range tests {
+		var ids []string
+		query := models.JobQuery{SortBy: tt.sortBy, Ascending: tt.ascending, Limit: 5}
+		for pages := 0; ; pages++ {
+			if pages > 5 {
+				t.Fatalf("%s: paging did not terminate", tt.sortBy)
+			}
+			page, err := jobQueue.QueryJobs(query)
+			if err != nil {
+				t.Fatalf("%s: QueryJobs failed: %v", tt.sortBy, err)
+			}
+			ids = append(ids, queryJobIDs(page)...)
+			if page.NextCursor == "" {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+
+		if len(ids) != 13 {
+			t.Fatalf("%s: expected 13 jobs across pages, got %v", tt.sortBy, ids)
+		}
+		seen := make(map[string]bool)
+		for _, id := range ids {
+			if seen[id] {
+				t.Errorf("%s: job %s returned twice", tt.sortBy, id)
+			}
+			seen[id] = true
+		}
+		if ids[0] != tt.first || ids[len(ids)-1] != tt.last {
+			t.Errorf("%s (ascending %v): expected %s first and %s last, got %v", tt.sortBy, tt.ascending, tt.first, tt.last, ids)
+		}
+	}
+}
+
+func TestJobDurationIsRecorded(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	id, _ := jobQueue.CreateJobWithOptions("test", "Tagged", "direct", []string{"true"}, nil,
+		models.JobOptions{Tags: []string{" qc ", "qc", ""}})
+	job := waitForJobStatus(t, db, id, models.JobStatusCompleted)
+
+	if job.DurationSeconds == nil || *job.DurationSeconds < 0 {
+		t.Errorf("Expected a duration for the completed job, got %v", job.DurationSeconds)
+	}
+	if len(job.Tags) != 1 || job.Tags[0] != "qc" {
+		t.Errorf("Expected tags to be normalized to [qc], got %v", job.Tags)
+	}
+
+	if err := jobQueue.SetJobTags(id, []string{"final", "paper"}); err != nil {
+		t.Fatalf("SetJobTags failed: %v", err)
+	}
+	page, _ := jobQueue.QueryJobs(models.JobQuery{Tag: "final"})
+	if len(page.Jobs) != 1 || page.Jobs[0].ID != id {
+		t.Errorf("Expected the retagged job, got %v", queryJobIDs(page))
+	}
+}
@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,45 +14,81 @@ import (
 	"github.com/google/uuid"
 	"github.com/noatgnu/cauldron-go/backend/models"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+)
+
+const (
+	jobLeaseDuration      = 60 * time.Second
+	jobLeaseRenewInterval = 20 * time.Second
+	queuePollInterval     = 2 * time.Second
 )
 
 type JobQueueService struct {
 	ctx           context.Context
 	db            *DatabaseService
+	logs          *JobLogService
+	artifacts     *ArtifactService
+	projects      *ProjectService
+	search        *JobSearchService
 	jobs          map[string]*models.Job
-	queue         chan *models.Job
-	workers       int
+	pools         map[string]*workerPool
 	mu            sync.RWMutex
+	leaseMu       sync.Mutex
 	wg            sync.WaitGroup
+	stop          chan struct{}
+	instanceID    string
+	started       bool
 	pythonRunner  *PythonRunner
 	rRunner       *RRunner
 	directRunner  *DirectRunner
 	settingsServ  *SettingsService
 	paused        bool
 	stopImmediate bool
-	currentJobID  string
-	cancelFunc    context.CancelFunc
+	executions    map[string][]*jobExecution
+	finishHooks   []func(jobID string)
+}
+
+type jobExecution struct {
+	cancel context.CancelFunc
 }
 
 func NewJobQueueService(ctx context.Context, db *DatabaseService) *JobQueueService {
+	logs := NewJobLogService(ctx, db)
 	service := &JobQueueService{
-		ctx:     ctx,
-		db:      db,
-		jobs:    make(map[string]*models.Job),
-		queue:   make(chan *models.Job, 100),
-		workers: 2,
+		ctx:        ctx,
+		db:         db,
+		logs:       logs,
+		artifacts:  NewArtifactService(db),
+		search:     NewJobSearchService(db, logs),
+		projects:   NewProjectService(ctx, db),
+		jobs:       make(map[string]*models.Job),
+		pools:      newWorkerPools(),
+		stop:       make(chan struct{}),
+		instanceID: uuid.New().String(),
+		executions: make(map[string][]*jobExecution),
 	}
 
 	service.loadFromDatabase()
 
-	for i := 0; i < service.workers; i++ {
-		service.wg.Add(1)
-		go service.worker()
-	}
-
 	return service
 }
 
+func (j *JobQueueService) Logs() *JobLogService {
+	return j.logs
+}
+
+func (j *JobQueueService) Artifacts() *ArtifactService {
+	return j.artifacts
+}
+
+func (j *JobQueueService) Projects() *ProjectService {
+	return j.projects
+}
+
+func (j *JobQueueService) Search() *JobSearchService {
+	return j.search
+}
+
 func (j *JobQueueService) SetRunners(pythonRunner *PythonRunner, rRunner *RRunner, directRunner *DirectRunner, settings *SettingsService) {
 	j.pythonRunner = pythonRunner
 	j.rRunner = rRunner
@@ -58,25 +96,97 @@ func (j *JobQueueService) SetRunners(pythonRunner *PythonRunner, rRunner *RRunne
 	j.settingsServ = settings
 }
 
-func (j *JobQueueService) worker() {
-	defer j.wg.Done()
+// Start returns jobs interrupted by a previous session to the queue and starts
+// the worker pools. It should be called once the runners have been set.
+func (j *JobQueueService) Start() {
+	j.mu.Lock()
+	if j.started {
+		j.mu.Unlock()
+		return
+	}
+	j.started = true
+	j.mu.Unlock()
 
-	for job := range j.queue {
-		j.mu.RLock()
-		isPaused := j.paused
-		j.mu.RUnlock()
+	j.recoverLeases()
+	j.ApplyWorkerSettings()
+}
 
-		if isPaused {
-			log.Printf("[worker] Queue is paused, requeueing job: %s", job.ID)
-			j.mu.Lock()
-			j.queue <- job
-			j.mu.Unlock()
-			time.Sleep(1 * time.Second)
+// leaseNextJob claims the highest priority pending job for a runtime, oldest
+// first within a priority, whose lease is free or has expired.
+func (j *JobQueueService) leaseNextJob(owner string, runtimeName string) *models.Job {
+	j.leaseMu.Lock()
+	defer j.leaseMu.Unlock()
+
+	now := time.Now()
+	// Anything that is not R or a direct executable runs in the Python pool.
+	query := j.db.GetDB().Where("status = ?", models.JobStatusPending)
+	if runtimeName == WorkerRuntimePython {
+		query = query.Where("command NOT IN ?", []string{WorkerRuntimeR, WorkerRuntimeDirect})
+	} else {
+		query = query.Where("command = ?", runtimeName)
+	}
+
+	var candidate models.Job
+	err := query.
+		Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+		Where("run_after IS NULL OR run_after <= ?", now).
+		Where("lease_owner IS NULL OR lease_owner = '' OR lease_expires_at < ?", now).
+		Order("priority DESC, queue_position ASC, created_at ASC").
+		First(&candidate).Error
+	if err != nil {
+		return nil
+	}
+
+	result := j.db.GetDB().Exec(
+		"UPDATE jobs SET lease_owner = ?, lease_expires_at = ? WHERE id = ? AND status = ? AND (lease_owner IS NULL OR lease_owner = '' OR lease_expires_at < ?)",
+		owner, now.Add(jobLeaseDuration), candidate.ID, models.JobStatusPending, now)
+	if result.Error != nil {
+		log.Printf("[leaseNextJob] Failed to lease job %s: %v", candidate.ID, result.Error)
+		return nil
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	job, err := j.GetJob(candidate.ID)
+	if err != nil {
+		j.releaseLease(candidate.ID, owner)
+		return nil
+	}
+	return job
+}
+
+func (j *JobQueueService) renewLease(jobID string, owner string) {
+	j.db.GetDB().Exec("UPDATE jobs SET lease_expires_at = ? WHERE id = ? AND lease_owner = ?",
+		time.Now().Add(jobLeaseDuration), jobID, owner)
+}
+
+func (j *JobQueueService) releaseLease(jobID string, owner string) {
+	j.db.GetDB().Exec("UPDATE jobs SET lease_owner = '', lease_expires_at = NULL WHERE id = ? AND lease_owner = ?",
+		jobID, owner)
+}
+
+// recoverLeases re-leases jobs interrupted by a previous session: jobs left in
+// progress by another queue instance are handled by the recovery policy, and
+// pending jobs still holding a lease are released so the workers pick them
+// up again.
+func (j *JobQueueService) recoverLeases() {
+	var interrupted []models.Job
+	j.db.GetDB().Where("status = ?", models.JobStatusInProgress).
+		Where("lease_owner IS NULL OR lease_owner NOT LIKE ?", j.instanceID+"/%").
+		Find(&interrupted)
+
+	policy := j.settingsServ.RecoveryPolicy()
+	for _, candidate := range interrupted {
+		job, err := j.GetJob(candidate.ID)
+		if err != nil {
 			continue
 		}
-
-		j.processJob(job)
+		j.recoverInterruptedJob(job, policy)
 	}
+
+	j.db.GetDB().Exec("UPDATE jobs SET lease_owner = '', lease_expires_at = NULL WHERE status = ? AND lease_owner NOT LIKE ?",
+		models.JobStatusPending, j.instanceID+"/%")
 }
 
 func (j *JobQueueService) CreateJob(jobType string, name string, command string, args []string) (string, error) {
@@ -84,6 +194,22 @@ func (j *JobQueueService) CreateJob(jobType string, name string, command string,
 }
 
 func (j *JobQueueService) CreateJobWithParameters(jobType string, name string, command string, args []string, parameters map[string]interface{}) (string, error) {
+	return j.CreateJobWithOptions(jobType, name, command, args, parameters, models.JobOptions{})
+}
+
+func (j *JobQueueService) CreateJobWithOptions(jobType string, name string, command string, args []string, parameters map[string]interface{}, options models.JobOptions) (string, error) {
+	job, err := j.newJob(jobType, name, command, args, parameters, options)
+	if err != nil {
+		return "", err
+	}
+	if err := j.storeJobs([]*models.Job{job}); err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+// newJob builds a job from its options without storing it.
+func (j *JobQueueService) newJob(jobType string, name string, command string, args []string, parameters map[string]interface{}, options models.JobOptions) (*models.Job, error) {
 	pythonPath := ""
 	pythonEnvType := ""
 	rPath := ""
@@ -115,35 +241,215 @@ func (j *JobQueueService) CreateJobWithParameters(jobType string, name string, c
 		}
 	}
 
+	for _, parentID := range options.DependsOn {
+		if _, err := j.GetJob(parentID); err != nil {
+			return nil, fmt.Errorf("invalid dependency: %v", err)
+		}
+	}
+
+	runAfter := options.RunAfter
+	if options.Schedule != "" {
+		schedule, err := parseCronSchedule(options.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		if runAfter == nil {
+			next, _ := schedule.Next(time.Now())
+			runAfter = &next
+		}
+	}
+
+	projectID := options.ProjectID
+	if projectID == "" {
+		projectID = j.projects.ActiveProjectID()
+	}
+
 	job := &models.Job{
-		ID:             uuid.New().String(),
-		Type:           jobType,
-		Name:           name,
-		Status:         models.JobStatusPending,
-		Progress:       0,
-		Command:        command,
-		Args:           args,
-		Parameters:     parameters,
-		PythonEnvPath:  pythonPath,
-		PythonEnvType:  pythonEnvType,
-		REnvPath:       rPath,
-		REnvType:       rEnvType,
-		TerminalOutput: []string{},
-		CreatedAt:      time.Now(),
+		ID:               uuid.New().String(),
+		Type:             jobType,
+		Name:             name,
+		ProjectID:        projectID,
+		Tags:             normalizeTags(options.Tags),
+		Status:           models.JobStatusPending,
+		Progress:         0,
+		Command:          command,
+		Args:             args,
+		Parameters:       parameters,
+		PythonEnvPath:    pythonPath,
+		PythonEnvType:    pythonEnvType,
+		REnvPath:         rPath,
+		REnvType:         rEnvType,
+		Priority:         options.Priority,
+		QueuePosition:    time.Now().UnixNano(),
+		Limits:           options.Limits,
+		DependsOn:        options.DependsOn,
+		ProgressPatterns: options.ProgressPatterns,
+		DeclaredOutputs:  options.DeclaredOutputs,
+		Attempt:          1,
+		RunAfter:         runAfter,
+		Schedule:         options.Schedule,
+		BatchID:          options.BatchID,
+		TerminalOutput:   []string{},
+		CreatedAt:        time.Now(),
 	}
 
+	if options.Retry != nil {
+		job.Retry = *options.Retry
+	}
+
+	snapshotMode := options.SnapshotInputs
+	if snapshotMode == "" {
+		snapshotMode = j.settingsServ.InputSnapshot()
+	}
+	if err := recordInputs(job, snapshotMode); err != nil {
+		return nil, err
+	}
+
+	ready, blocker := j.checkDependencies(job.DependsOn)
+	if blocker != "" {
+		completedTime := time.Now()
+		job.Status = models.JobStatusSkipped
+		job.Error = "Skipped: " + blocker
+		job.CompletedAt = &completedTime
+	} else if !ready {
+		job.Status = models.JobStatusBlocked
+	}
+
+	return job, nil
+}
+
+// storeJobs stores new jobs in one transaction, so workers see either all of
+// them or, when storing fails, none, and then indexes and announces them and
+// wakes the workers.
+func (j *JobQueueService) storeJobs(jobs []*models.Job) error {
+	// A worker may lease a job as soon as it is stored, so jobs are indexed
+	// and announced from copies taken before then.
+	snapshots := make([]models.Job, len(jobs))
 	j.mu.Lock()
-	j.jobs[job.ID] = job
+	for i, job := range jobs {
+		snapshots[i] = *job
+		j.jobs[job.ID] = job
+	}
 	j.mu.Unlock()
 
-	if err := j.db.GetDB().Create(job).Error; err != nil {
-		return "", err
+	err := j.db.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, job := range jobs {
+			if err := tx.Create(job).Error; err != nil {
+				return fmt.Errorf("failed to store job %s: %w", job.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		j.mu.Lock()
+		for _, job := range jobs {
+			delete(j.jobs, job.ID)
+		}
+		j.mu.Unlock()
+		return err
 	}
 
-	j.queue <- job
-	j.emitJobUpdate(job)
+	pending := false
+	for i := range snapshots {
+		j.search.IndexJob(&snapshots[i])
+		j.emitJobUpdate(&snapshots[i])
+		pending = pending || snapshots[i].Status == models.JobStatusPending
+	}
+	if pending {
+		j.notify()
+	}
+	return nil
+}
 
-	return job.ID, nil
+// checkDependencies reports whether every parent job has completed. If a
+// parent failed, was cancelled, was skipped or no longer exists, blocker
+// describes why the dependent job can never run.
+func (j *JobQueueService) checkDependencies(parentIDs []string) (bool, string) {
+	ready := true
+	for _, parentID := range parentIDs {
+		parent, err := j.GetJob(parentID)
+		if err != nil {
+			return false, fmt.Sprintf("upstream job %s no longer exists", parentID)
+		}
+
+		j.mu.RLock()
+		status := parent.Status
+		j.mu.RUnlock()
+
+		switch status {
+		case models.JobStatusCompleted:
+		case models.JobStatusFailed, models.JobStatusCancelled, models.JobStatusSkipped:
+			return false, fmt.Sprintf("upstream job %s (%s) %s", parent.Name, parent.ID, status)
+		default:
+			ready = false
+		}
+	}
+	return ready, ""
+}
+
+// OnJobFinished registers a hook called whenever a job finishes or is
+// deleted. Hooks run on the goroutine that finished the job.
+func (j *JobQueueService) OnJobFinished(hook func(jobID string)) {
+	j.mu.Lock()
+	j.finishHooks = append(j.finishHooks, hook)
+	j.mu.Unlock()
+}
+
+// ReleaseDependents re-evaluates the blocked jobs that depend on parentID once
+// it has finished, after telling the finish hooks. Jobs whose parents have
+// all completed are queued and jobs with a failed parent are skipped, which
+// in turn releases their own dependents.
+func (j *JobQueueService) ReleaseDependents(parentID string) {
+	j.mu.RLock()
+	hooks := j.finishHooks
+	j.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(parentID)
+	}
+
+	var candidates []models.Job
+	if err := j.db.GetDB().Where("status = ? AND depends_on LIKE ?", models.JobStatusBlocked, "%\""+parentID+"\"%").
+		Find(&candidates).Error; err != nil {
+		log.Printf("[ReleaseDependents] Failed to load dependents of %s: %v", parentID, err)
+		return
+	}
+
+	for _, candidate := range candidates {
+		job, err := j.GetJob(candidate.ID)
+		if err != nil {
+			continue
+		}
+
+		ready, blocker := j.checkDependencies(job.DependsOn)
+
+		j.mu.Lock()
+		if job.Status != models.JobStatusBlocked {
+			j.mu.Unlock()
+			continue
+		}
+		if blocker != "" {
+			completedTime := time.Now()
+			job.Status = models.JobStatusSkipped
+			job.Error = "Skipped: " + blocker
+			job.CompletedAt = &completedTime
+		} else if ready {
+			job.Status = models.JobStatusPending
+		}
+		j.mu.Unlock()
+
+		switch job.Status {
+		case models.JobStatusSkipped:
+			log.Printf("[ReleaseDependents] Skipping job %s: %s", job.ID, job.Error)
+			j.db.GetDB().Save(job)
+			j.emitJobUpdate(job)
+			j.ReleaseDependents(job.ID)
+		case models.JobStatusPending:
+			log.Printf("[ReleaseDependents] Dependencies of job %s satisfied, queueing", job.ID)
+			j.db.GetDB().Save(job)
+			j.emitJobUpdate(job)
+			j.notify()
+		}
+	}
 }
 
 func (j *JobQueueService) GetJob(id string) (*models.Job, error) {
@@ -167,6 +473,7 @@ func (j *JobQueueService) GetJob(id string) (*models.Job, error) {
 	return &dbJob, nil
 }
 
+// GetAllJobs returns the most recent jobs of the active project.
 func (j *JobQueueService) GetAllJobs() []*models.Job {
 	var jobs []*models.Job
 
@@ -176,7 +483,8 @@ func (j *JobQueueService) GetAllJobs() []*models.Job {
 	}
 
 	log.Println("[GetAllJobs] Starting database query...")
-	result := j.db.GetDB().Order("created_at DESC").Limit(100).Find(&jobs)
+	result := j.db.GetDB().Where("project_id = ?", j.projects.ActiveProjectID()).
+		Order("created_at DESC").Limit(100).Find(&jobs)
 
 	if result.Error != nil {
 		log.Printf("[GetAllJobs] ERROR: Database query failed: %v\n", result.Error)
@@ -187,12 +495,97 @@ func (j *JobQueueService) GetAllJobs() []*models.Job {
 	return jobs
 }
 
+// DeleteJob moves a job to the trash. Its log and artifacts are kept until
+// it is purged, so the job can still be restored. A job that is running or
+// that a worker has leased to start is refused.
 func (j *JobQueueService) DeleteJob(id string) error {
+	job, err := j.GetJob(id)
+	if err != nil {
+		return err
+	}
+	j.mu.RLock()
+	running := job.Status == models.JobStatusInProgress
+	j.mu.RUnlock()
+	if running {
+		return fmt.Errorf("job %s is running, cancel it before deleting it", id)
+	}
+
+	result := j.db.GetDB().
+		Where("id = ? AND status <> ?", id, models.JobStatusInProgress).
+		Where("lease_owner IS NULL OR lease_owner = '' OR lease_expires_at < ?", time.Now()).
+		Delete(&models.Job{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("job %s is running, cancel it before deleting it", id)
+	}
+
+	j.mu.Lock()
+	delete(j.jobs, id)
+	j.mu.Unlock()
+	j.search.RemoveJob(id)
+
+	j.ReleaseDependents(id)
+	return nil
+}
+
+// RestoreJob takes a job out of the trash.
+func (j *JobQueueService) RestoreJob(id string) (*models.Job, error) {
+	result := j.db.GetDB().Unscoped().Model(&models.Job{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("job not in trash: %s", id)
+	}
+
+	job, err := j.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	j.search.IndexJob(job)
+	j.emitJobUpdate(job)
+	if job.Status == models.JobStatusPending {
+		j.notify()
+	}
+	return job, nil
+}
+
+// PurgeJob permanently deletes a job, whether or not it is in the trash,
+// together with its log and artifacts.
+func (j *JobQueueService) PurgeJob(id string) error {
 	j.mu.Lock()
 	delete(j.jobs, id)
 	j.mu.Unlock()
 
-	return j.db.GetDB().Delete(&models.Job{}, "id = ?", id).Error
+	if err := j.db.GetDB().Unscoped().Delete(&models.Job{}, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if err := j.logs.DeleteJobLog(id); err != nil {
+		log.Printf("[PurgeJob] Failed to delete log of job %s: %v", id, err)
+	}
+	if err := j.artifacts.DeleteJobArtifacts(id); err != nil {
+		log.Printf("[PurgeJob] Failed to delete artifacts of job %s: %v", id, err)
+	}
+	j.search.RemoveJob(id)
+
+	j.ReleaseDependents(id)
+	return nil
+}
+
+// saveJob writes back a job the queue is running. Unlike Save it never
+// recreates a job that was moved to the trash in the meantime, and it
+// reports whether the job is still there.
+func (j *JobQueueService) saveJob(job *models.Job) bool {
+	result := j.db.GetDB().Model(job).Select("*").Omit("id", "created_at", "deleted_at").Updates(job)
+	if result.Error != nil {
+		log.Printf("[saveJob] Failed to save job %s: %v", job.ID, result.Error)
+		return false
+	}
+	return result.RowsAffected > 0
 }
 
 func (j *JobQueueService) ValidateJobEnvironment(job *models.Job) error {
@@ -252,35 +645,49 @@ func (j *JobQueueService) ValidateJobEnvironment(job *models.Job) error {
 	return nil
 }
 
-func (j *JobQueueService) processJob(job *models.Job) {
-	j.mu.Lock()
-	j.currentJobID = job.ID
-	j.mu.Unlock()
+func (j *JobQueueService) processJob(parent context.Context, job *models.Job) {
+	defer j.ReleaseDependents(job.ID)
+	defer j.scheduleNextOccurrence(job)
+	defer j.search.IndexJob(job)
 
-	defer func() {
-		j.mu.Lock()
-		j.currentJobID = ""
+	ctx, release := j.trackExecution(parent, job.ID)
+	defer release()
+
+	j.mu.Lock()
+	if job.Status == models.JobStatusCancelled {
 		j.mu.Unlock()
-	}()
+		log.Printf("[processJob] Skipping cancelled job: %s", job.ID)
+		return
+	}
+	j.mu.Unlock()
 
 	now := time.Now()
 	job.StartedAt = &now
+	job.NextAttemptAt = nil
+	job.Error = ""
+	job.ErrorDetails = models.JobError{}
+	job.Warnings = unenforcedLimitWarnings(job.Limits)
+	job.ProgressMessage = ""
+	job.Metrics = models.ProcessMetrics{}
 	job.Status = models.JobStatusInProgress
 
-	j.db.GetDB().Save(job)
+	if !j.saveJob(job) {
+		log.Printf("[processJob] Skipping job moved to the trash: %s", job.ID)
+		return
+	}
 	j.emitJobUpdate(job)
 
 	j.mu.RLock()
 	shouldStopImmediate := j.stopImmediate
 	j.mu.RUnlock()
 
-	if shouldStopImmediate {
-		log.Printf("[processJob] Immediate stop requested, canceling job: %s", job.ID)
+	if shouldStopImmediate || ctx.Err() != nil {
+		log.Printf("[processJob] Stop requested, canceling job: %s", job.ID)
 		completedTime := time.Now()
 		job.CompletedAt = &completedTime
-		job.Status = models.JobStatusFailed
+		job.Status = models.JobStatusCancelled
 		job.Error = "Job stopped by user request"
-		j.db.GetDB().Save(job)
+		j.saveJob(job)
 		j.emitJobUpdate(job)
 		return
 	}
@@ -290,7 +697,7 @@ func (j *JobQueueService) processJob(job *models.Job) {
 		job.CompletedAt = &completedTime
 		job.Status = models.JobStatusFailed
 		job.Error = err.Error()
-		j.db.GetDB().Save(job)
+		j.saveJob(job)
 		j.emitJobUpdate(job)
 		return
 	}
@@ -300,79 +707,298 @@ func (j *JobQueueService) processJob(job *models.Job) {
 		job.CompletedAt = &completedTime
 		job.Status = models.JobStatusCompleted
 		job.Progress = 100
-		j.db.GetDB().Save(job)
+		j.saveJob(job)
 		j.emitJobUpdate(job)
 		return
 	}
 
-	var err error
-	outputCallback := func(line string) {
-		job.TerminalOutput = append(job.TerminalOutput, line)
-		j.db.GetDB().Save(job)
-		if j.ctx.Value("wails-test") == nil {
-			runtime.EventsEmit(j.ctx, "job:output", map[string]interface{}{
-				"jobId":  job.ID,
-				"output": line,
-			})
-		}
+	command, err := j.resolveExecution(job)
+	if err != nil {
+		completedTime := time.Now()
+		job.CompletedAt = &completedTime
+		job.Status = models.JobStatusFailed
+		job.Error = err.Error()
+		j.saveJob(job)
+		j.emitJobUpdate(job)
+		return
 	}
 
-	if job.Command == "r" {
-		if j.rRunner == nil {
-			completedTime := time.Now()
-			job.CompletedAt = &completedTime
-			job.Status = models.JobStatusFailed
-			job.Error = "R runner not initialized"
-			j.db.GetDB().Save(job)
-			j.emitJobUpdate(job)
-			return
-		}
-		err = j.rRunner.ExecuteScript(job.Args[0], job.Args[1:], outputCallback)
-	} else if job.Command == "direct" {
-		if j.directRunner == nil {
-			completedTime := time.Now()
-			job.CompletedAt = &completedTime
-			job.Status = models.JobStatusFailed
-			job.Error = "Direct runner not initialized"
-			j.db.GetDB().Save(job)
-			j.emitJobUpdate(job)
-			return
-		}
-		var workingDir string
-		if outputDir, ok := job.Parameters["outputDir"].(string); ok {
-			workingDir = outputDir
-		}
-		err = j.directRunner.ExecuteProgram(job.Args[0], job.Args[1:], workingDir, outputCallback)
-	} else {
-		if j.pythonRunner == nil {
-			completedTime := time.Now()
-			job.CompletedAt = &completedTime
-			job.Status = models.JobStatusFailed
-			job.Error = "Python runner not initialized"
-			j.db.GetDB().Save(job)
-			j.emitJobUpdate(job)
-			return
+	metrics := &models.ProcessMetrics{}
+	ctx = withProcessMetrics(ctx, metrics)
+	logWriter := j.logs.NewWriter(job.ID, job.Attempt)
+	progressParser := newProgressParser(job.ProgressPatterns)
+	reporter := newProgressReporter(j, job)
+	outputCallback := func(stream string, line string) {
+		if update, matched, hide := progressParser.Parse(line); matched {
+			reporter.Report(update)
+			if hide {
+				return
+			}
 		}
-		err = j.pythonRunner.ExecuteScript(job.Args[0], job.Args[1:], outputCallback)
+		logWriter.Write(stream, line)
+	}
+
+	j.mu.Lock()
+	job.ConsolePath = command.ConsoleDir
+	j.mu.Unlock()
+	err = command.run(ctx, job.Limits, outputCallback)
+	logWriter.Close()
+	reporter.Stop()
+	job.Metrics = *metrics
+	job.ProcessID = 0
+	job.ProcessStartTime = 0
+
+	if err != nil && !errors.Is(err, context.Canceled) && j.scheduleRetry(job, err, logWriter.Tail()) {
+		return
 	}
 
 	completedTime := time.Now()
 	job.CompletedAt = &completedTime
 
-	if err != nil {
+	if errors.Is(err, context.Canceled) {
+		job.Status = models.JobStatusCancelled
+		job.Error = "Job cancelled by user"
+	} else if err != nil {
 		job.Status = models.JobStatusFailed
-		job.Error = err.Error()
+		job.ErrorDetails = parseJobError(job, err, logWriter.StderrTail())
+		job.Error = summarizeJobError(job.ErrorDetails, err)
 	} else {
 		job.Status = models.JobStatusCompleted
 		job.Progress = 100
+		job.ProgressMessage = ""
 	}
 
 	if outputDir, ok := job.Parameters["outputDir"].(string); ok && outputDir != "" {
 		job.OutputPath = outputDir
+		j.recordArtifacts(job)
+	}
+
+	j.saveJob(job)
+	j.emitJobUpdate(job)
+}
+
+// recordArtifacts stores the manifest of the job's output directory. A
+// completed job fails when a required declared output is missing and gets a
+// warning for each other declared output that is missing.
+func (j *JobQueueService) recordArtifacts(job *models.Job) {
+	if info, err := os.Stat(job.OutputPath); err != nil || !info.IsDir() {
+		return
+	}
+
+	scan, err := j.artifacts.ScanJobOutputs(job.ID, job.OutputPath, job.DeclaredOutputs)
+	if err != nil {
+		log.Printf("[recordArtifacts] Job %s: %v", job.ID, err)
+		job.Warnings = append(job.Warnings, err.Error())
+		return
+	}
+
+	if job.Status != models.JobStatusCompleted {
+		return
+	}
+	if len(scan.MissingRequired) > 0 {
+		job.Status = models.JobStatusFailed
+		job.Error = fmt.Sprintf("Missing required output(s): %s", strings.Join(scan.MissingRequired, ", "))
+	}
+	for _, missing := range scan.MissingOptional {
+		job.Warnings = append(job.Warnings, fmt.Sprintf("Declared output %s was not produced", missing))
+	}
+}
+
+// scheduleNextOccurrence queues the next run of a recurring job once the
+// current run has finished. Cancelling a run ends the schedule.
+func (j *JobQueueService) scheduleNextOccurrence(job *models.Job) {
+	if job.Schedule == "" {
+		return
+	}
+	if job.Status != models.JobStatusCompleted && job.Status != models.JobStatusFailed {
+		return
+	}
+
+	schedule, err := parseCronSchedule(job.Schedule)
+	if err != nil {
+		log.Printf("[scheduleNextOccurrence] Job %s has an invalid schedule: %v", job.ID, err)
+		return
+	}
+	next, err := schedule.Next(time.Now())
+	if err != nil {
+		log.Printf("[scheduleNextOccurrence] No next run for job %s: %v", job.ID, err)
+		return
+	}
+
+	origin := job.ScheduledFrom
+	if origin == "" {
+		origin = job.ID
+	}
+
+	occurrence := &models.Job{
+		ID:               uuid.New().String(),
+		Type:             job.Type,
+		Name:             job.Name,
+		ProjectID:        job.ProjectID,
+		Tags:             job.Tags,
+		Status:           models.JobStatusPending,
+		Priority:         job.Priority,
+		QueuePosition:    time.Now().UnixNano(),
+		Command:          job.Command,
+		Args:             job.Args,
+		Parameters:       job.Parameters,
+		PythonEnvPath:    job.PythonEnvPath,
+		PythonEnvType:    job.PythonEnvType,
+		REnvPath:         job.REnvPath,
+		REnvType:         job.REnvType,
+		Limits:           job.Limits,
+		Retry:            job.Retry,
+		ProgressPatterns: job.ProgressPatterns,
+		DeclaredOutputs:  job.DeclaredOutputs,
+		Attempt:          1,
+		RunAfter:         &next,
+		Schedule:         job.Schedule,
+		ScheduledFrom:    origin,
+		TerminalOutput:   []string{},
+		CreatedAt:        time.Now(),
+	}
+
+	j.mu.Lock()
+	j.jobs[occurrence.ID] = occurrence
+	j.mu.Unlock()
+
+	if err := j.db.GetDB().Create(occurrence).Error; err != nil {
+		log.Printf("[scheduleNextOccurrence] Failed to create next run of job %s: %v", job.ID, err)
+		return
+	}
+	j.search.IndexJob(occurrence)
+
+	log.Printf("[scheduleNextOccurrence] Next run of %s scheduled for %s", job.Name, next.Format(time.RFC3339))
+	j.emitJobUpdate(occurrence)
+}
+
+// GetScheduledJobs returns pending jobs that are waiting for their start time,
+// soonest first.
+func (j *JobQueueService) GetScheduledJobs() []*models.Job {
+	var jobs []*models.Job
+	j.db.GetDB().Where("status = ? AND run_after > ?", models.JobStatusPending, time.Now()).
+		Order("run_after ASC").
+		Find(&jobs)
+	return jobs
+}
+
+// scheduleRetry records the failed attempt and puts the job back in the queue
+// after the policy's backoff. It returns false when the job should fail.
+func (j *JobQueueService) scheduleRetry(job *models.Job, err error, tail []string) bool {
+	policy := job.Retry
+	if job.Attempt >= policy.MaxAttempts {
+		return false
+	}
+
+	exitCode := processExitCode(err)
+	output := append(append([]string{}, tail...), err.Error())
+	if !policy.Retryable(exitCode, output) {
+		log.Printf("[scheduleRetry] Failure of job %s is not retryable: %v", job.ID, err)
+		return false
+	}
+
+	now := time.Now()
+	attempt := models.JobAttempt{
+		Attempt:        job.Attempt,
+		Status:         models.JobStatusFailed,
+		Error:          err.Error(),
+		TerminalOutput: tail,
+		StartedAt:      job.StartedAt,
+		FinishedAt:     now,
+	}
+	if exitCode >= 0 {
+		attempt.ExitCode = &exitCode
+	}
+
+	nextAttemptAt := now.Add(policy.Delay(job.Attempt + 1))
+
+	job.Attempts = append(job.Attempts, attempt)
+	job.Attempt++
+	job.NextAttemptAt = &nextAttemptAt
+	job.Status = models.JobStatusPending
+	job.Progress = 0
+	job.Error = fmt.Sprintf("Attempt %d of %d failed: %v", attempt.Attempt, policy.MaxAttempts, err)
+	job.StartedAt = nil
+	job.CompletedAt = nil
+
+	log.Printf("[scheduleRetry] Job %s attempt %d failed, retrying at %s", job.ID, attempt.Attempt, nextAttemptAt.Format(time.RFC3339))
+	j.saveJob(job)
+	j.emitJobUpdate(job)
+	return true
+}
+
+func (j *JobQueueService) trackExecution(parent context.Context, jobID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	execution := &jobExecution{cancel: cancel}
+
+	j.mu.Lock()
+	j.executions[jobID] = append(j.executions[jobID], execution)
+	j.mu.Unlock()
+
+	release := func() {
+		cancel()
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+
+		remaining := j.executions[jobID][:0]
+		for _, e := range j.executions[jobID] {
+			if e != execution {
+				remaining = append(remaining, e)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(j.executions, jobID)
+		} else {
+			j.executions[jobID] = remaining
+		}
 	}
 
+	return ctx, release
+}
+
+func (j *JobQueueService) CancelJob(id string) error {
+	job, err := j.GetJob(id)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	executions := j.executions[id]
+	for _, execution := range executions {
+		execution.cancel()
+	}
+
+	if len(executions) > 0 {
+		j.mu.Unlock()
+		log.Printf("[CancelJob] Sent cancellation to %d running process(es) for job %s", len(executions), id)
+		return nil
+	}
+
+	if job.Status != models.JobStatusPending && job.Status != models.JobStatusInProgress && job.Status != models.JobStatusBlocked {
+		j.mu.Unlock()
+		return fmt.Errorf("job %s cannot be cancelled (status: %s)", id, job.Status)
+	}
+
+	completedTime := time.Now()
+	job.CompletedAt = &completedTime
+	job.Status = models.JobStatusCancelled
+	job.Error = "Job cancelled by user"
+	pid, startTime := job.ProcessID, job.ProcessStartTime
+	job.ProcessID = 0
+	job.ProcessStartTime = 0
+	j.mu.Unlock()
+
+	// A job interrupted by a previous session may still have its process.
+	killOrphan(pid, startTime)
+
+	log.Printf("[CancelJob] Cancelled job %s before it started", id)
 	j.db.GetDB().Save(job)
+	j.search.IndexJob(job)
 	j.emitJobUpdate(job)
+	j.ReleaseDependents(id)
+
+	return nil
 }
 
 func (j *JobQueueService) emitJobUpdate(job *models.Job) {
@@ -383,12 +1009,39 @@ func (j *JobQueueService) emitJobUpdate(job *models.Job) {
 	runtime.EventsEmit(j.ctx, "job:update", job)
 }
 
-func (j *JobQueueService) RerunJob(jobID string, useSameEnvironment bool, pythonEnvPath string, rEnvPath string) (string, error) {
+// RerunJob queues a copy of a job. With fromSnapshot the copy reads the
+// snapshot of the original's inputs; otherwise it reads the original paths,
+// and any input that changed since the original was submitted is noted in
+// its log. Reruns share the original's output directory, so they record
+// input hashes but never take a snapshot of their own.
+func (j *JobQueueService) RerunJob(jobID string, useSameEnvironment bool, pythonEnvPath string, rEnvPath string, fromSnapshot bool) (string, error) {
 	originalJob, err := j.GetJob(jobID)
 	if err != nil {
 		return "", fmt.Errorf("failed to get original job: %v", err)
 	}
 
+	checks, err := j.VerifyJobInputs(jobID)
+	if err != nil {
+		return "", err
+	}
+	args := []string(originalJob.Args)
+	parameters := originalJob.Parameters
+	var inputWarnings []string
+	if fromSnapshot {
+		replacements, err := snapshotReplacements(checks)
+		if err != nil {
+			return "", fmt.Errorf("cannot rerun from snapshot: %w", err)
+		}
+		args = replaceInputPaths(args, replacements).([]string)
+		parameters = replaceInputPaths(parameters, replacements).(models.JSONMap)
+	} else {
+		for _, check := range checks {
+			if check.Status != models.InputStatusUnchanged {
+				inputWarnings = append(inputWarnings, fmt.Sprintf("Warning: input %s is %s since job %s was submitted", check.Path, check.Status, jobID))
+			}
+		}
+	}
+
 	var newPythonPath, newPythonType, newRPath, newRType string
 
 	if useSameEnvironment {
@@ -425,20 +1078,33 @@ func (j *JobQueueService) RerunJob(jobID string, useSameEnvironment bool, python
 	}
 
 	newJob := &models.Job{
-		ID:             uuid.New().String(),
-		Type:           originalJob.Type,
-		Name:           originalJob.Name + " (Rerun)",
-		Status:         models.JobStatusPending,
-		Progress:       0,
-		Command:        originalJob.Command,
-		Args:           originalJob.Args,
-		Parameters:     originalJob.Parameters,
-		PythonEnvPath:  newPythonPath,
-		PythonEnvType:  newPythonType,
-		REnvPath:       newRPath,
-		REnvType:       newRType,
-		TerminalOutput: []string{},
-		CreatedAt:      time.Now(),
+		ID:               uuid.New().String(),
+		Type:             originalJob.Type,
+		Name:             originalJob.Name + " (Rerun)",
+		ProjectID:        originalJob.ProjectID,
+		Tags:             originalJob.Tags,
+		Status:           models.JobStatusPending,
+		Progress:         0,
+		Command:          originalJob.Command,
+		Args:             args,
+		Parameters:       parameters,
+		PythonEnvPath:    newPythonPath,
+		PythonEnvType:    newPythonType,
+		REnvPath:         newRPath,
+		REnvType:         newRType,
+		Priority:         originalJob.Priority,
+		QueuePosition:    time.Now().UnixNano(),
+		Limits:           originalJob.Limits,
+		Retry:            originalJob.Retry,
+		ProgressPatterns: originalJob.ProgressPatterns,
+		DeclaredOutputs:  originalJob.DeclaredOutputs,
+		Attempt:          1,
+		TerminalOutput:   []string{},
+		CreatedAt:        time.Now(),
+	}
+
+	if err := recordInputs(newJob, models.InputSnapshotNone); err != nil {
+		return "", err
 	}
 
 	j.mu.Lock()
@@ -448,13 +1114,39 @@ func (j *JobQueueService) RerunJob(jobID string, useSameEnvironment bool, python
 	if err := j.db.GetDB().Create(newJob).Error; err != nil {
 		return "", err
 	}
+	for _, warning := range inputWarnings {
+		log.Printf("[RerunJob] Job %s: %s", newJob.ID, warning)
+		j.logs.AppendLine(newJob.ID, newJob.Attempt, models.LogStreamStderr, warning)
+	}
+	j.search.IndexJob(newJob)
 
-	j.queue <- newJob
 	j.emitJobUpdate(newJob)
+	j.notify()
 
 	return newJob.ID, nil
 }
 
+// ImportJob adds a finished job recorded elsewhere, such as one read from a
+// bundle. It is stored as-is and never scheduled.
+func (j *JobQueueService) ImportJob(job *models.Job) error {
+	switch job.Status {
+	case models.JobStatusCompleted, models.JobStatusFailed, models.JobStatusCancelled:
+	default:
+		return fmt.Errorf("cannot import job with status %s", job.Status)
+	}
+
+	if err := j.db.GetDB().Create(job).Error; err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	j.jobs[job.ID] = job
+	j.mu.Unlock()
+
+	j.emitJobUpdate(job)
+	return nil
+}
+
 func (j *JobQueueService) loadFromDatabase() error {
 	log.Println("[loadFromDatabase] Starting...")
 
@@ -485,7 +1177,7 @@ func (j *JobQueueService) loadFromDatabase() error {
 }
 
 func (j *JobQueueService) Shutdown() {
-	close(j.queue)
+	close(j.stop)
 	j.wg.Wait()
 }
 
@@ -500,7 +1192,9 @@ func (j *JobQueueService) UpdateJobProgress(id string, progress float64, output
 
 	job.Progress = progress
 	if output != "" {
-		job.TerminalOutput = append(job.TerminalOutput, output)
+		if err := j.logs.AppendLine(id, job.Attempt, models.LogStreamStdout, output); err != nil {
+			log.Printf("[UpdateJobProgress] Failed to store output for job %s: %v", id, err)
+		}
 	}
 
 	j.db.GetDB().Save(job)
@@ -510,42 +1204,42 @@ func (j *JobQueueService) UpdateJobProgress(id string, progress float64, output
 }
 
 func (j *JobQueueService) FailJob(id string, errorMsg string) error {
-	j.mu.Lock()
-	defer j.mu.Unlock()
-
-	job, ok := j.jobs[id]
-	if !ok {
-		return fmt.Errorf("job not found: %s", id)
+	job, err := j.GetJob(id)
+	if err != nil {
+		return err
 	}
 
+	j.mu.Lock()
 	completedTime := time.Now()
 	job.CompletedAt = &completedTime
 	job.Status = models.JobStatusFailed
 	job.Error = errorMsg
+	j.mu.Unlock()
 
 	j.db.GetDB().Save(job)
 	j.emitJobUpdate(job)
+	j.ReleaseDependents(id)
 
 	return nil
 }
 
 func (j *JobQueueService) CompleteJob(id string, outputPath string) error {
-	j.mu.Lock()
-	defer j.mu.Unlock()
-
-	job, ok := j.jobs[id]
-	if !ok {
-		return fmt.Errorf("job not found: %s", id)
+	job, err := j.GetJob(id)
+	if err != nil {
+		return err
 	}
 
+	j.mu.Lock()
 	completedTime := time.Now()
 	job.CompletedAt = &completedTime
 	job.Status = models.JobStatusCompleted
 	job.Progress = 100
 	job.OutputPath = outputPath
+	j.mu.Unlock()
 
 	j.db.GetDB().Save(job)
 	j.emitJobUpdate(job)
+	j.ReleaseDependents(id)
 
 	return nil
 }
@@ -569,13 +1263,131 @@ func (j *JobQueueService) SearchJobs(query string) []*models.Job {
 	return jobs
 }
 
+// SetJobTags replaces the tags of a job.
+func (j *JobQueueService) SetJobTags(id string, tags []string) error {
+	job, err := j.GetJob(id)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	job.Tags = normalizeTags(tags)
+	j.mu.Unlock()
+
+	if err := j.db.GetDB().Model(&models.Job{}).Where("id = ?", id).
+		UpdateColumn("tags", job.Tags).Error; err != nil {
+		return err
+	}
+
+	j.emitJobUpdate(job)
+	return nil
+}
+
+// normalizeTags trims tags and drops empty and repeated ones.
+func normalizeTags(tags []string) models.StringArray {
+	normalized := models.StringArray{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
 func (j *JobQueueService) RequeueJob(job *models.Job) {
 	j.mu.Lock()
 	j.jobs[job.ID] = job
+	job.Status = models.JobStatusPending
+	j.mu.Unlock()
+
+	j.db.GetDB().Save(job)
+	j.db.GetDB().Exec("UPDATE jobs SET lease_owner = '', lease_expires_at = NULL WHERE id = ?", job.ID)
+	j.emitJobUpdate(job)
+	j.notify()
+}
+
+// GetQueuedJobs returns the pending jobs in the order the workers will take
+// them.
+func (j *JobQueueService) GetQueuedJobs() []*models.Job {
+	var jobs []*models.Job
+	j.db.GetDB().Where("status = ?", models.JobStatusPending).
+		Order("priority DESC, queue_position ASC, created_at ASC").
+		Find(&jobs)
+	return jobs
+}
+
+func (j *JobQueueService) SetJobPriority(id string, priority int) error {
+	job, err := j.GetJob(id)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	if job.Status != models.JobStatusPending && job.Status != models.JobStatusBlocked {
+		j.mu.Unlock()
+		return fmt.Errorf("job %s is not queued (status: %s)", id, job.Status)
+	}
+	job.Priority = priority
 	j.mu.Unlock()
 
-	j.queue <- job
+	if err := j.db.GetDB().Model(&models.Job{}).Where("id = ?", id).Update("priority", priority).Error; err != nil {
+		return err
+	}
+
 	j.emitJobUpdate(job)
+	return nil
+}
+
+// ReorderPendingJobs rearranges the given pending jobs into the given order.
+// The jobs swap queue positions among themselves, so their place relative to
+// other queued jobs is kept. Priority still takes precedence over position.
+func (j *JobQueueService) ReorderPendingJobs(jobIDs []string) error {
+	j.leaseMu.Lock()
+	defer j.leaseMu.Unlock()
+
+	var queued []models.Job
+	if err := j.db.GetDB().Where("id IN ? AND status = ?", jobIDs, models.JobStatusPending).
+		Find(&queued).Error; err != nil {
+		return err
+	}
+	if len(queued) != len(jobIDs) {
+		return fmt.Errorf("only pending jobs can be reordered")
+	}
+
+	positions := make([]int64, 0, len(queued))
+	for _, job := range queued {
+		positions = append(positions, job.QueuePosition)
+	}
+	sort.Slice(positions, func(a, b int) bool { return positions[a] < positions[b] })
+
+	err := j.db.GetDB().Transaction(func(tx *gorm.DB) error {
+		for i, id := range jobIDs {
+			if err := tx.Model(&models.Job{}).Where("id = ?", id).Update("queue_position", positions[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	for i, id := range jobIDs {
+		if job, ok := j.jobs[id]; ok {
+			job.QueuePosition = positions[i]
+		}
+	}
+	j.mu.Unlock()
+
+	if j.ctx.Value("wails-test") == nil {
+		runtime.EventsEmit(j.ctx, "queue:reordered", jobIDs)
+	}
+	return nil
 }
 
 func (j *JobQueueService) PauseQueue() error {
@@ -607,8 +1419,11 @@ func (j *JobQueueService) StopQueueImmediate() error {
 	j.stopImmediate = true
 	log.Println("[StopQueueImmediate] Queue stopped immediately - canceling current job and pausing queue")
 
-	if j.currentJobID != "" {
-		log.Printf("[StopQueueImmediate] Marking current job %s for cancellation", j.currentJobID)
+	for jobID, executions := range j.executions {
+		log.Printf("[StopQueueImmediate] Cancelling running job %s", jobID)
+		for _, execution := range executions {
+			execution.cancel()
+		}
 	}
 
 	if j.ctx.Value("wails-test") == nil {
@@ -640,16 +1455,7 @@ func (j *JobQueueService) ResumeQueue() error {
 		})
 	}
 
-	var pendingJobs []*models.Job
-	j.db.GetDB().Where("status = ?", models.JobStatusPending).Order("created_at ASC").Find(&pendingJobs)
-
-	for _, job := range pendingJobs {
-		if _, exists := j.jobs[job.ID]; !exists {
-			j.jobs[job.ID] = job
-			j.queue <- job
-			log.Printf("[ResumeQueue] Requeued pending job: %s - %s", job.ID, job.Name)
-		}
-	}
+	j.notify()
 
 	return nil
 }
@@ -660,16 +1466,32 @@ func (j *JobQueueService) GetQueueStatus() map[string]interface{} {
 
 	var pendingCount int64
 	var inProgressCount int64
+	var blockedCount int64
 
 	j.db.GetDB().Model(&models.Job{}).Where("status = ?", models.JobStatusPending).Count(&pendingCount)
 	j.db.GetDB().Model(&models.Job{}).Where("status = ?", models.JobStatusInProgress).Count(&inProgressCount)
+	j.db.GetDB().Model(&models.Job{}).Where("status = ?", models.JobStatusBlocked).Count(&blockedCount)
+
+	upcomingJobs := []map[string]interface{}{}
+	for _, job := range j.GetScheduledJobs() {
+		upcomingJobs = append(upcomingJobs, map[string]interface{}{
+			"id":       job.ID,
+			"name":     job.Name,
+			"type":     job.Type,
+			"runAfter": job.RunAfter,
+			"schedule": job.Schedule,
+		})
+	}
 
 	return map[string]interface{}{
 		"paused":          j.paused,
 		"stopImmediate":   j.stopImmediate,
-		"currentJobID":    j.currentJobID,
+		"runningJobIDs":   j.runningJobIDs(),
+		"workerCounts":    j.workerCounts(),
 		"pendingCount":    pendingCount,
 		"inProgressCount": inProgressCount,
-		"queueLength":     len(j.queue),
+		"blockedCount":    blockedCount,
+		"upcomingJobs":    upcomingJobs,
+		"queueLength":     pendingCount,
 	}
 }
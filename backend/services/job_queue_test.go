@@ -0,0 +1,523 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func newTestJobQueue(t *testing.T) (*JobQueueService, *DatabaseService) {
+	db := createTestDB(t)
+	ctx := context.WithValue(context.Background(), "wails-test", true)
+	jobQueue := NewJobQueueService(ctx, db)
+	jobQueue.Start()
+
+	t.Cleanup(func() {
+		jobQueue.Shutdown()
+		db.Close()
+	})
+
+	return jobQueue, db
+}
+
+func waitForJobStatus(t *testing.T, db *DatabaseService, id string, status models.JobStatus) models.Job {
+	t.Helper()
+
+	var job models.Job
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := db.GetDB().First(&job, "id = ?", id).Error; err == nil && job.Status == status {
+			return job
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("Job %s did not reach status %s (last status: %s)", id, status, job.Status)
+	return job
+}
+
+func TestJobDependenciesRunAfterParentsComplete(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+
+	if err := jobQueue.PauseQueue(); err != nil {
+		t.Fatalf("Failed to pause queue: %v", err)
+	}
+
+	parentID, err := jobQueue.CreateJob("test", "Parent", "python", []string{})
+	if err != nil {
+		t.Fatalf("Failed to create parent job: %v", err)
+	}
+
+	childID, err := jobQueue.CreateJobWithOptions("test", "Child", "python", []string{}, nil,
+		models.JobOptions{DependsOn: []string{parentID}})
+	if err != nil {
+		t.Fatalf("Failed to create child job: %v", err)
+	}
+
+	waitForJobStatus(t, db, childID, models.JobStatusBlocked)
+
+	if err := jobQueue.ResumeQueue(); err != nil {
+		t.Fatalf("Failed to resume queue: %v", err)
+	}
+
+	waitForJobStatus(t, db, parentID, models.JobStatusCompleted)
+	waitForJobStatus(t, db, childID, models.JobStatusCompleted)
+}
+
+func TestJobDependenciesSkipTransitivelyOnFailure(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+
+	if err := jobQueue.PauseQueue(); err != nil {
+		t.Fatalf("Failed to pause queue: %v", err)
+	}
+
+	parentID, err := jobQueue.CreateJob("test", "Parent", "python", []string{"script.py"})
+	if err != nil {
+		t.Fatalf("Failed to create parent job: %v", err)
+	}
+
+	childID, err := jobQueue.CreateJobWithOptions("test", "Child", "python", []string{}, nil,
+		models.JobOptions{DependsOn: []string{parentID}})
+	if err != nil {
+		t.Fatalf("Failed to create child job: %v", err)
+	}
+
+	grandchildID, err := jobQueue.CreateJobWithOptions("test", "Grandchild", "python", []string{}, nil,
+		models.JobOptions{DependsOn: []string{childID}})
+	if err != nil {
+		t.Fatalf("Failed to create grandchild job: %v", err)
+	}
+
+	if err := jobQueue.ResumeQueue(); err != nil {
+		t.Fatalf("Failed to resume queue: %v", err)
+	}
+
+	// No Python runner is configured, so the parent fails.
+	waitForJobStatus(t, db, parentID, models.JobStatusFailed)
+	waitForJobStatus(t, db, childID, models.JobStatusSkipped)
+	grandchild := waitForJobStatus(t, db, grandchildID, models.JobStatusSkipped)
+
+	if grandchild.StartedAt != nil {
+		t.Error("Expected skipped job never to start")
+	}
+
+	lateID, err := jobQueue.CreateJobWithOptions("test", "Late", "python", []string{}, nil,
+		models.JobOptions{DependsOn: []string{parentID}})
+	if err != nil {
+		t.Fatalf("Failed to create late job: %v", err)
+	}
+	waitForJobStatus(t, db, lateID, models.JobStatusSkipped)
+}
+
+func TestJobDependenciesRejectUnknownParent(t *testing.T) {
+	jobQueue, _ := newTestJobQueue(t)
+
+	_, err := jobQueue.CreateJobWithOptions("test", "Orphan", "python", []string{}, nil,
+		models.JobOptions{DependsOn: []string{"does-not-exist"}})
+	if err == nil {
+		t.Fatal("Expected an error for an unknown dependency")
+	}
+}
+
+func queuedJobIDs(jobQueue *JobQueueService) []string {
+	var ids []string
+	for _, job := range jobQueue.GetQueuedJobs() {
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
+
+func TestJobQueuePriorityAndReorder(t *testing.T) {
+	jobQueue, _ := newTestJobQueue(t)
+
+	if err := jobQueue.PauseQueue(); err != nil {
+		t.Fatalf("Failed to pause queue: %v", err)
+	}
+
+	create := func(name string, priority int) string {
+		id, err := jobQueue.CreateJobWithOptions("test", name, "python", []string{}, nil,
+			models.JobOptions{Priority: priority})
+		if err != nil {
+			t.Fatalf("Failed to create job %s: %v", name, err)
+		}
+		return id
+	}
+
+	first := create("First", 0)
+	urgent := create("Urgent", 5)
+	second := create("Second", 0)
+	third := create("Third", 0)
+
+	expected := []string{urgent, first, second, third}
+	if got := queuedJobIDs(jobQueue); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected queue order %v, got %v", expected, got)
+	}
+
+	if err := jobQueue.ReorderPendingJobs([]string{third, first}); err != nil {
+		t.Fatalf("Failed to reorder jobs: %v", err)
+	}
+
+	expected = []string{urgent, third, second, first}
+	if got := queuedJobIDs(jobQueue); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected queue order %v after reorder, got %v", expected, got)
+	}
+
+	if err := jobQueue.SetJobPriority(second, 10); err != nil {
+		t.Fatalf("Failed to set priority: %v", err)
+	}
+
+	expected = []string{second, urgent, third, first}
+	if got := queuedJobIDs(jobQueue); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected queue order %v after priority change, got %v", expected, got)
+	}
+}
+
+func TestJobQueueDoesNotBlockWhenFull(t *testing.T) {
+	jobQueue, _ := newTestJobQueue(t)
+
+	if err := jobQueue.PauseQueue(); err != nil {
+		t.Fatalf("Failed to pause queue: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; i < 150; i++ {
+			if _, err := jobQueue.CreateJob("test", "Bulk", "python", []string{}); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Failed to create jobs: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Creating jobs blocked while the queue was paused")
+	}
+
+	if got := len(jobQueue.GetQueuedJobs()); got != 150 {
+		t.Errorf("Expected 150 queued jobs, got %d", got)
+	}
+}
+
+func TestJobQueueLeaseIsExclusive(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	ctx := context.WithValue(context.Background(), "wails-test", true)
+	jobQueue := NewJobQueueService(ctx, db)
+
+	id, err := jobQueue.CreateJob("test", "Leased", "python", []string{})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	leased := jobQueue.leaseNextJob("worker-a", WorkerRuntimePython)
+	if leased == nil || leased.ID != id {
+		t.Fatalf("Expected worker-a to lease job %s, got %v", id, leased)
+	}
+	if other := jobQueue.leaseNextJob("worker-b", WorkerRuntimePython); other != nil {
+		t.Fatalf("Expected no job for worker-b, got %s", other.ID)
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	db.GetDB().Exec("UPDATE jobs SET lease_expires_at = ? WHERE id = ?", expired, id)
+
+	if other := jobQueue.leaseNextJob("worker-b", WorkerRuntimePython); other == nil || other.ID != id {
+		t.Fatalf("Expected worker-b to take over the expired lease, got %v", other)
+	}
+}
+
+func TestJobQueueRecoversInterruptedJobs(t *testing.T) {
+	db := createTestDB(t)
+	ctx := context.WithValue(context.Background(), "wails-test", true)
+
+	started := time.Now().Add(-time.Hour)
+	job := &models.Job{
+		ID:            "interrupted-job",
+		Type:          "test",
+		Name:          "Interrupted",
+		Status:        models.JobStatusInProgress,
+		Command:       "python",
+		Args:          models.StringArray{},
+		QueuePosition: started.UnixNano(),
+		CreatedAt:     started,
+		StartedAt:     &started,
+	}
+	if err := db.GetDB().Create(job).Error; err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+	db.GetDB().Exec("UPDATE jobs SET lease_owner = ?, lease_expires_at = ? WHERE id = ?",
+		"previous-session/0", time.Now().Add(time.Minute), job.ID)
+
+	jobQueue := NewJobQueueService(ctx, db)
+	jobQueue.Start()
+	t.Cleanup(func() {
+		jobQueue.Shutdown()
+		db.Close()
+	})
+
+	recovered := waitForJobStatus(t, db, job.ID, models.JobStatusCompleted)
+	if recovered.StartedAt == nil || !recovered.StartedAt.After(started) {
+		t.Errorf("Expected the job to be started again, got StartedAt %v", recovered.StartedAt)
+	}
+}
+
+func TestWorkerPoolsRouteJobsByRuntime(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+
+	// No R runner is configured, so an R job fails once an R worker takes it.
+	rJobID, err := jobQueue.CreateJob("test", "R job", "r", []string{"script.R"})
+	if err != nil {
+		t.Fatalf("Failed to create R job: %v", err)
+	}
+	waitForJobStatus(t, db, rJobID, models.JobStatusFailed)
+
+	if leased := jobQueue.leaseNextJob("python-worker", WorkerRuntimePython); leased != nil {
+		t.Fatalf("Expected the Python pool not to see R jobs, got %s", leased.ID)
+	}
+
+	rJob, _ := jobQueue.GetJob(rJobID)
+	if rJob.Error != "R runner not initialized" {
+		t.Errorf("Expected the R worker to run the job, got error %q", rJob.Error)
+	}
+}
+
+func TestWorkerPoolsResizeAtRuntime(t *testing.T) {
+	jobQueue, _ := newTestJobQueue(t)
+
+	countWorkers := func(runtimeName string) int {
+		count := 0
+		for _, worker := range jobQueue.GetWorkers() {
+			if worker.Runtime == runtimeName && !worker.Retiring {
+				count++
+			}
+		}
+		return count
+	}
+
+	if got := countWorkers(WorkerRuntimePython); got != defaultWorkerCounts[WorkerRuntimePython] {
+		t.Fatalf("Expected %d Python workers, got %d", defaultWorkerCounts[WorkerRuntimePython], got)
+	}
+
+	if err := jobQueue.SetWorkerCount(WorkerRuntimePython, 4); err != nil {
+		t.Fatalf("Failed to grow pool: %v", err)
+	}
+	if got := countWorkers(WorkerRuntimePython); got != 4 {
+		t.Fatalf("Expected 4 Python workers, got %d", got)
+	}
+
+	if err := jobQueue.SetWorkerCount(WorkerRuntimePython, 1); err != nil {
+		t.Fatalf("Failed to shrink pool: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(jobQueue.GetWorkers()) != 1+defaultWorkerCounts[WorkerRuntimeR]+defaultWorkerCounts[WorkerRuntimeDirect] {
+		if time.Now().After(deadline) {
+			t.Fatalf("Retired workers did not exit, workers: %+v", jobQueue.GetWorkers())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := jobQueue.SetWorkerCount("julia", 1); err == nil {
+		t.Error("Expected an error for an unknown runtime")
+	}
+	if err := jobQueue.SetWorkerCount(WorkerRuntimeR, 0); err == nil {
+		t.Error("Expected an error for a zero worker count")
+	}
+
+	settings := &SettingsService{config: &models.Config{PythonWorkers: 2}}
+	for _, value := range []interface{}{0, 100.0, "many"} {
+		if err := settings.Set("pythonWorkers", value); err == nil {
+			t.Errorf("Expected %v workers to be rejected", value)
+		}
+	}
+	if settings.config.PythonWorkers != 2 {
+		t.Errorf("Expected a rejected worker count not to be kept, got %d", settings.config.PythonWorkers)
+	}
+}
+
+func TestGetWorkersReportsRunningJob(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("uses the sleep command")
+	}
+
+	db := createTestDB(t)
+	ctx := context.WithValue(context.Background(), "wails-test", true)
+	jobQueue := NewJobQueueService(ctx, db)
+	jobQueue.SetRunners(nil, nil, NewDirectRunner(), nil)
+	jobQueue.Start()
+	t.Cleanup(func() {
+		jobQueue.Shutdown()
+		db.Close()
+	})
+
+	jobID, err := jobQueue.CreateJob("test", "Sleeper", "direct", []string{"sleep", "30"})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	var worker models.WorkerInfo
+	deadline := time.Now().Add(5 * time.Second)
+	for worker.PID == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("No worker reported a PID for job %s: %+v", jobID, jobQueue.GetWorkers())
+		}
+		for _, w := range jobQueue.GetWorkers() {
+			if w.JobID == jobID {
+				worker = w
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if worker.Runtime != WorkerRuntimeDirect || !worker.Busy || worker.StartedAt == nil {
+		t.Errorf("Unexpected worker info: %+v", worker)
+	}
+
+	status := jobQueue.GetQueueStatus()
+	if running, _ := status["runningJobIDs"].([]string); !reflect.DeepEqual(running, []string{jobID}) {
+		t.Errorf("Expected running jobs [%s], got %v", jobID, status["runningJobIDs"])
+	}
+
+	if err := jobQueue.CancelJob(jobID); err != nil {
+		t.Fatalf("Failed to cancel job: %v", err)
+	}
+	waitForJobStatus(t, db, jobID, models.JobStatusCancelled)
+}
+
+func newTestJobQueueWithDirectRunner(t *testing.T) (*JobQueueService, *DatabaseService) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	db := createTestDB(t)
+	ctx := context.WithValue(context.Background(), "wails-test", true)
+	jobQueue := NewJobQueueService(ctx, db)
+	jobQueue.SetRunners(nil, nil, NewDirectRunner(), nil)
+	jobQueue.Start()
+	t.Cleanup(func() {
+		jobQueue.Shutdown()
+		db.Close()
+	})
+
+	return jobQueue, db
+}
+
+func TestJobRetryRecordsAttempts(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	marker := filepath.Join(t.TempDir(), "attempts")
+	script := fmt.Sprintf("echo run >> %s; test $(wc -l < %s) -ge 3 || { echo 'connection reset'; exit 3; }", marker, marker)
+
+	jobID, err := jobQueue.CreateJobWithOptions("test", "Flaky", "direct", []string{"sh", "-c", script}, nil,
+		models.JobOptions{Retry: &models.RetryPolicy{MaxAttempts: 3, RetryOnExitCodes: []int{3}}})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	job := waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+	if job.Attempt != 3 {
+		t.Errorf("Expected the job to succeed on attempt 3, got %d", job.Attempt)
+	}
+	if len(job.Attempts) != 2 {
+		t.Fatalf("Expected 2 failed attempts in the history, got %d", len(job.Attempts))
+	}
+
+	first := job.Attempts[0]
+	if first.Attempt != 1 || first.ExitCode == nil || *first.ExitCode != 3 {
+		t.Errorf("Unexpected first attempt: %+v", first)
+	}
+	if !reflect.DeepEqual(first.TerminalOutput, []string{"connection reset"}) {
+		t.Errorf("Expected the first attempt's output to be kept, got %v", first.TerminalOutput)
+	}
+}
+
+func TestJobRetryStopsOnNonRetryableFailure(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	jobID, err := jobQueue.CreateJobWithOptions("test", "Broken", "direct", []string{"sh", "-c", "echo 'bad input'; exit 1"}, nil,
+		models.JobOptions{Retry: &models.RetryPolicy{MaxAttempts: 3, RetryOnPatterns: []string{"(?i)timeout|connection"}}})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	job := waitForJobStatus(t, db, jobID, models.JobStatusFailed)
+	if job.Attempt != 1 || len(job.Attempts) != 0 {
+		t.Errorf("Expected a single attempt, got attempt %d with history %+v", job.Attempt, job.Attempts)
+	}
+}
+
+func TestScheduledJobsWaitForRunAfter(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+
+	later := time.Now().Add(time.Hour)
+	laterID, err := jobQueue.CreateJobWithOptions("test", "Overnight", "python", []string{}, nil,
+		models.JobOptions{RunAfter: &later})
+	if err != nil {
+		t.Fatalf("Failed to create scheduled job: %v", err)
+	}
+
+	earlier := time.Now().Add(-time.Minute)
+	dueID, err := jobQueue.CreateJobWithOptions("test", "Due", "python", []string{}, nil,
+		models.JobOptions{RunAfter: &earlier})
+	if err != nil {
+		t.Fatalf("Failed to create due job: %v", err)
+	}
+
+	waitForJobStatus(t, db, dueID, models.JobStatusCompleted)
+
+	job, _ := jobQueue.GetJob(laterID)
+	if job.Status != models.JobStatusPending {
+		t.Errorf("Expected the scheduled job to stay pending, got %s", job.Status)
+	}
+
+	upcoming, _ := jobQueue.GetQueueStatus()["upcomingJobs"].([]map[string]interface{})
+	if len(upcoming) != 1 || upcoming[0]["id"] != laterID {
+		t.Errorf("Expected upcoming jobs to list %s, got %v", laterID, upcoming)
+	}
+}
+
+func TestRecurringJobSchedulesNextRun(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+
+	now := time.Now()
+	jobID, err := jobQueue.CreateJobWithOptions("test", "Nightly", "python", []string{}, nil,
+		models.JobOptions{RunAfter: &now, Schedule: "0 2 * * *"})
+	if err != nil {
+		t.Fatalf("Failed to create recurring job: %v", err)
+	}
+
+	waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+
+	var next models.Job
+	deadline := time.Now().Add(5 * time.Second)
+	for db.GetDB().First(&next, "scheduled_from = ?", jobID).Error != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the next run to be scheduled")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if next.Status != models.JobStatusPending || next.Schedule != "0 2 * * *" {
+		t.Errorf("Unexpected next run: status %s, schedule %q", next.Status, next.Schedule)
+	}
+	if next.RunAfter == nil || next.RunAfter.Local().Hour() != 2 || next.RunAfter.Local().Minute() != 0 || !next.RunAfter.After(now) {
+		t.Errorf("Expected the next run at 02:00, got %v", next.RunAfter)
+	}
+
+	if _, err := jobQueue.CreateJobWithOptions("test", "Bad", "python", []string{}, nil,
+		models.JobOptions{Schedule: "every night"}); err == nil {
+		t.Error("Expected an error for an invalid schedule")
+	}
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	jobSearchTable       = "job_search"
+	maxIndexedLogBytes   = 1 << 20
+	defaultSearchLimit   = 50
+	maxSearchLimit       = 200
+	snippetTokens        = 16
+	snippetMatchStart    = "\x02"
+	snippetMatchEnd      = "\x03"
+	searchIndexBatchSize = 100
+)
+
+// jobSearchFields are the indexed columns in table order, after job_id.
+var jobSearchFields = []string{"name", "inputs", "parameters", "error", "log"}
+
+// JobSearchService keeps an SQLite FTS5 index over job names, parameters,
+// input paths, errors and log output. When the SQLite build has no FTS5 the
+// index is disabled and searches return an error.
+type JobSearchService struct {
+	db        *DatabaseService
+	logs      *JobLogService
+	available bool
+}
+
+func NewJobSearchService(db *DatabaseService, logs *JobLogService) *JobSearchService {
+	service := &JobSearchService{
+		db:   db,
+		logs: logs,
+	}
+	if err := service.ensureIndex(); err != nil {
+		log.Printf("[JobSearchService] Full-text search disabled: %v", err)
+	} else {
+		service.available = true
+	}
+	return service
+}
+
+// ensureIndex creates the index and fills it from the existing jobs the
+// first time it runs.
+func (s *JobSearchService) ensureIndex() error {
+	var count int64
+	s.db.GetDB().Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", jobSearchTable).Scan(&count)
+	if count > 0 {
+		return nil
+	}
+
+	err := s.db.GetDB().Exec(fmt.Sprintf(
+		"CREATE VIRTUAL TABLE %s USING fts5(job_id UNINDEXED, %s)",
+		jobSearchTable, strings.Join(jobSearchFields, ", "),
+	)).Error
+	if err != nil {
+		return err
+	}
+
+	s.available = true
+	return s.Rebuild()
+}
+
+// Rebuild re-indexes every job.
+func (s *JobSearchService) Rebuild() error {
+	if !s.available {
+		return nil
+	}
+
+	var jobs []models.Job
+	indexed := 0
+	err := s.db.GetDB().FindInBatches(&jobs, searchIndexBatchSize, func(tx *gorm.DB, batch int) error {
+		for i := range jobs {
+			s.IndexJob(&jobs[i])
+			indexed++
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[Rebuild] Indexed %d jobs for search", indexed)
+	return nil
+}
+
+// IndexJob replaces the indexed text of a job. Only the first megabyte of
+// its log is indexed.
+func (s *JobSearchService) IndexJob(job *models.Job) {
+	if !s.available {
+		return
+	}
+
+	parameters, _ := json.Marshal(job.Parameters)
+	logText := s.logText(job.ID)
+	err := s.db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM "+jobSearchTable+" WHERE job_id = ?", job.ID).Error; err != nil {
+			return err
+		}
+		return tx.Exec(
+			"INSERT INTO "+jobSearchTable+" (job_id, name, inputs, parameters, error, log) VALUES (?, ?, ?, ?, ?, ?)",
+			job.ID, job.Name, strings.Join(inputPaths(job.Parameters), "\n"), string(parameters), job.Error, logText,
+		).Error
+	})
+	if err != nil {
+		log.Printf("[IndexJob] Failed to index job %s: %v", job.ID, err)
+	}
+}
+
+func (s *JobSearchService) RemoveJob(jobID string) {
+	if !s.available {
+		return
+	}
+	if err := s.db.GetDB().Exec("DELETE FROM "+jobSearchTable+" WHERE job_id = ?", jobID).Error; err != nil {
+		log.Printf("[RemoveJob] Failed to remove job %s from the search index: %v", jobID, err)
+	}
+}
+
+func (s *JobSearchService) logText(jobID string) string {
+	var text strings.Builder
+	for offset := 0; text.Len() < maxIndexedLogBytes; offset += bundleLogPageSize {
+		page, err := s.logs.GetJobLog(jobID, offset, bundleLogPageSize, "")
+		if err != nil {
+			break
+		}
+		for _, line := range page.Lines {
+			if text.Len()+len(line.Line) > maxIndexedLogBytes {
+				return text.String()
+			}
+			text.WriteString(line.Line)
+			text.WriteByte('\n')
+		}
+		if len(page.Lines) < bundleLogPageSize {
+			break
+		}
+	}
+	return text.String()
+}
+
+type jobSearchRow struct {
+	JobID      string
+	Rank       float64
+	Name       string
+	Inputs     string
+	Parameters string
+	Error      string
+	Log        string
+}
+
+// Search returns the jobs matching every word of text, best matches first,
+// each with a snippet of the field that matched. projectID limits the
+// results to one project; empty searches all projects.
+func (s *JobSearchService) Search(text string, projectID string, limit int) ([]models.JobSearchResult, error) {
+	if !s.available {
+		return nil, fmt.Errorf("full-text search is not available")
+	}
+
+	match := searchMatchExpression(text)
+	if match == "" {
+		return []models.JobSearchResult{}, nil
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	} else if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	snippets := make([]string, len(jobSearchFields))
+	for i, field := range jobSearchFields {
+		snippets[i] = fmt.Sprintf("snippet(%s, %d, '%s', '%s', '…', %d) AS %s",
+			jobSearchTable, i+1, snippetMatchStart, snippetMatchEnd, snippetTokens, field)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %[1]s.job_id AS job_id, bm25(%[1]s, 0, 10, 5, 5, 3, 1) AS rank, %[2]s "+
+			"FROM %[1]s JOIN jobs ON jobs.id = %[1]s.job_id WHERE %[1]s MATCH ?",
+		jobSearchTable, strings.Join(snippets, ", "))
+	args := []interface{}{match}
+	if projectID != "" {
+		query += " AND jobs.project_id = ?"
+		args = append(args, projectID)
+	}
+	query += " ORDER BY rank LIMIT ?"
+	args = append(args, limit)
+
+	var rows []jobSearchRow
+	if err := s.db.GetDB().Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.JobID
+	}
+	var jobs []models.Job
+	if len(ids) > 0 {
+		if err := s.db.GetDB().Where("id IN ?", ids).Find(&jobs).Error; err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[string]models.Job, len(jobs))
+	for _, job := range jobs {
+		byID[job.ID] = job
+	}
+
+	results := make([]models.JobSearchResult, 0, len(rows))
+	for _, row := range rows {
+		job, ok := byID[row.JobID]
+		if !ok {
+			continue
+		}
+		field, snippet := matchedSnippet(row)
+		results = append(results, models.JobSearchResult{
+			Job:     job,
+			Score:   -row.Rank,
+			Field:   field,
+			Snippet: splitSnippet(snippet),
+		})
+	}
+	return results, nil
+}
+
+// searchMatchExpression quotes each word of text so punctuation such as the
+// dot in "Protein.Ids" is matched literally, and lets the last word match
+// as a prefix.
+func searchMatchExpression(text string) string {
+	words := strings.Fields(text)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	terms[len(terms)-1] += "*"
+	return strings.Join(terms, " ")
+}
+
+// matchedSnippet returns the first field whose snippet contains a match.
+func matchedSnippet(row jobSearchRow) (string, string) {
+	for i, snippet := range []string{row.Name, row.Inputs, row.Parameters, row.Error, row.Log} {
+		if strings.Contains(snippet, snippetMatchStart) {
+			return jobSearchFields[i], snippet
+		}
+	}
+	return jobSearchFields[0], row.Name
+}
+
+func splitSnippet(snippet string) []models.SnippetPart {
+	parts := []models.SnippetPart{}
+	for snippet != "" {
+		start := strings.Index(snippet, snippetMatchStart)
+		if start < 0 {
+			parts = append(parts, models.SnippetPart{Text: snippet})
+			break
+		}
+		if start > 0 {
+			parts = append(parts, models.SnippetPart{Text: snippet[:start]})
+		}
+		snippet = snippet[start+len(snippetMatchStart):]
+
+		end := strings.Index(snippet, snippetMatchEnd)
+		if end < 0 {
+			end = len(snippet)
+		}
+		parts = append(parts, models.SnippetPart{Text: snippet[:end], Match: true})
+		snippet = strings.TrimPrefix(snippet[end:], snippetMatchEnd)
+	}
+	return parts
+}
+
+// inputPaths returns the absolute file paths among a job's parameters.
+func inputPaths(parameters map[string]interface{}) []string {
+	var paths []string
+	for key, value := range parameters {
+		if key == "outputDir" {
+			continue
+		}
+		for _, path := range parameterPaths(value) {
+			if filepath.IsAbs(path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// createFTSTestDB opens the test database with the driver the app uses,
+// which is built with FTS5.
+func createFTSTestDB(t *testing.T) *DatabaseService {
+	sqlDB, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	db, err := gorm.Open(sqlite.Dialector{Conn: sqlDB}, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	service := &DatabaseService{ctx: context.Background(), db: db}
+	if err := service.autoMigrate(); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	return service
+}
+
+func snippetText(parts []models.SnippetPart) (string, []string) {
+	var text strings.Builder
+	var matches []string
+	for _, part := range parts {
+		text.WriteString(part.Text)
+		if part.Match {
+			matches = append(matches, part.Text)
+		}
+	}
+	return text.String(), matches
+}
+
+func TestSearchJobsByParametersAndLog(t *testing.T) {
+	if goruntime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	db := createFTSTestDB(t)
+	ctx := context.WithValue(context.Background(), "wails-test", true)
+	jobQueue := NewJobQueueService(ctx, db)
+	jobQueue.SetRunners(nil, nil, NewDirectRunner(), nil)
+	jobQueue.Start()
+	t.Cleanup(func() {
+		jobQueue.Shutdown()
+		db.Close()
+	})
+
+	diann, _ := jobQueue.CreateJobWithOptions("diann", "DIA-NN summary", "direct", []string{"echo", "loaded 4521 precursors"},
+		map[string]interface{}{"inputFile": "/data/run1/report.tsv", "indexColumn": "Protein.Ids"}, models.JobOptions{})
+	other, _ := jobQueue.CreateJobWithOptions("pca", "PCA", "direct", []string{"echo", "done"},
+		map[string]interface{}{"inputFile": "/data/run2/matrix.tsv", "indexColumn": "Genes"}, models.JobOptions{})
+	waitForJobStatus(t, db, diann, models.JobStatusCompleted)
+	waitForJobStatus(t, db, other, models.JobStatusCompleted)
+
+	search := jobQueue.Search()
+	tests := []struct {
+		text  string
+		want  string
+		field string
+	}{
+		{"Protein.Ids", diann, "parameters"},
+		{"run1/report", diann, "inputs"},
+		{"precursors", diann, "log"},
+		{"summ", diann, "name"},
+		{"genes", other, "parameters"},
+	}
+	for _, tt := range tests {
+		results, err := search.Search(tt.text, models.DefaultProjectID, 10)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", tt.text, err)
+		}
+		if len(results) != 1 || results[0].Job.ID != tt.want {
+			t.Errorf("Search(%q): expected job %s, got %+v", tt.text, tt.want, results)
+			continue
+		}
+		if results[0].Field != tt.field {
+			t.Errorf("Search(%q): expected a match in %s, got %s", tt.text, tt.field, results[0].Field)
+		}
+		if _, matches := snippetText(results[0].Snippet); len(matches) == 0 {
+			t.Errorf("Search(%q): expected a highlighted match in %+v", tt.text, results[0].Snippet)
+		}
+	}
+
+	if results, _ := search.Search("Protein.Ids", "other-project", 10); len(results) != 0 {
+		t.Errorf("Expected no matches in another project, got %+v", results)
+	}
+	if results, _ := search.Search(`"unbalanced`, "", 10); len(results) != 0 {
+		t.Errorf("Expected no matches for a quoted word, got %+v", results)
+	}
+
+	jobQueue.DeleteJob(diann)
+	if results, _ := search.Search("precursors", "", 10); len(results) != 0 {
+		t.Errorf("Expected deleted jobs to be removed from the index, got %+v", results)
+	}
+}
+
+func TestSearchIndexIsBuiltForExistingJobs(t *testing.T) {
+	db := createFTSTestDB(t)
+	defer db.Close()
+
+	db.GetDB().Exec("DROP TABLE " + jobSearchTable)
+	db.GetDB().Create(&models.Job{ID: "legacy", Type: "test", Name: "Legacy", Command: "python",
+		ProjectID: models.DefaultProjectID, Status: models.JobStatusFailed, Error: "KeyError: 'Protein.Group'"})
+
+	search := NewJobSearchService(db, NewJobLogService(context.Background(), db))
+	results, err := search.Search("protein.group", "", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Field != "error" {
+		t.Fatalf("Expected the legacy job to match on its error, got %+v", results)
+	}
+	if text, matches := snippetText(results[0].Snippet); !strings.Contains(text, "KeyError") || len(matches) != 1 || matches[0] != "Protein.Group" {
+		t.Errorf("Expected the phrase highlighted in the error, got %q %v", text, matches)
+	}
+}
+
+func TestSearchMatchExpression(t *testing.T) {
+	tests := map[string]string{
+		"":              "",
+		"Protein.Ids":   `"Protein.Ids"*`,
+		"diann  report": `"diann" "report"*`,
+		`say "hi"`:      `"say" """hi"""*`,
+	}
+	for text, want := range tests {
+		if got := searchMatchExpression(text); got != want {
+			t.Errorf("searchMatchExpression(%q) = %s, want %s", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+//go:build linux
+// +build linux
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const cpuLimitHardGraceSeconds = 5
+
+// resourceLimitsEnforced reports whether memory and CPU limits are enforced
+// on this platform.
+const resourceLimitsEnforced = true
+
+// applyResourceLimits sets the memory and CPU rlimits of a started process.
+// The limits are applied with prlimit once the process has started, so a
+// child it forks before then does not inherit them. Start only returns after
+// the program has been exec'd, which leaves the first moments of its
+// start-up as the window.
+func applyResourceLimits(pid int, limits models.ExecutionLimits) error {
+	if limits.MaxMemoryMB > 0 {
+		bytes := uint64(limits.MaxMemoryMB) * 1024 * 1024
+		if err := prlimit(pid, syscall.RLIMIT_DATA, &syscall.Rlimit{Cur: bytes, Max: bytes}); err != nil {
+			return err
+		}
+	}
+
+	if limits.MaxCPUSeconds > 0 {
+		soft := uint64(limits.MaxCPUSeconds)
+		if err := prlimit(pid, syscall.RLIMIT_CPU, &syscall.Rlimit{Cur: soft, Max: soft + cpuLimitHardGraceSeconds}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func prlimit(pid int, resource int, limit *syscall.Rlimit) error {
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource),
+		uintptr(unsafe.Pointer(limit)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// cpuLimitExceeded reports whether a process was stopped by its CPU rlimit.
+// The kernel sends SIGXCPU at the soft limit and SIGKILL at the hard limit;
+// a SIGKILL only counts when the process had used up its CPU time, so an
+// OOM kill or a manual kill -9 is not mistaken for one.
+func cpuLimitExceeded(state *os.ProcessState, maxCPUSeconds int) bool {
+	if state == nil {
+		return false
+	}
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return false
+	}
+	switch status.Signal() {
+	case syscall.SIGXCPU:
+		return true
+	case syscall.SIGKILL:
+		return state.UserTime()+state.SystemTime() >= time.Duration(maxCPUSeconds)*time.Second
+	}
+	return false
+}
+
+// processGroupRSS returns the summed resident set size in bytes of every
+// process whose process group is pgid.
+func processGroupRSS(pgid int) (int64, error) {
+	stats, err := readProcStats()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, stat := range stats {
+		if stat.pgrp == pgid {
+			total += stat.rssBytes
+		}
+	}
+	return total, nil
+}
+
+// processTreeUsage returns the summed resident set size in bytes and the
+// number of processes of pid, its descendants and the rest of its process
+// group.
+func processTreeUsage(pid int) (int64, int, error) {
+	stats, err := readProcStats()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	children := make(map[int][]int)
+	for _, stat := range stats {
+		children[stat.ppid] = append(children[stat.ppid], stat.pid)
+	}
+	inTree := map[int]bool{pid: true}
+	pending := []int{pid}
+	for len(pending) > 0 {
+		parent := pending[0]
+		pending = pending[1:]
+		for _, child := range children[parent] {
+			if !inTree[child] {
+				inTree[child] = true
+				pending = append(pending, child)
+			}
+		}
+	}
+
+	var total int64
+	count := 0
+	for _, stat := range stats {
+		if inTree[stat.pid] || stat.pgrp == pid {
+			total += stat.rssBytes
+			count++
+		}
+	}
+	return total, count, nil
+}
+
+// processMaxRSS returns the peak resident set size in bytes the kernel
+// reported for an exited process and the descendants it waited for.
+func processMaxRSS(state *os.ProcessState) int64 {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		return usage.Maxrss * 1024
+	}
+	return 0
+}
+
+// processLivenessSupported reports whether processAlive can tell whether a
+// process from a previous session is still running.
+const processLivenessSupported = true
+
+// processStartTime returns when pid started, in clock ticks since boot. A
+// PID and its start time together identify a process even after the PID is
+// reused.
+func processStartTime(pid int) (int64, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, err
+	}
+	fields := procStatFields(string(data))
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	return strconv.ParseInt(fields[19], 10, 64)
+}
+
+// processAlive reports whether the process that started at startTime is
+// still running as pid. Zombies count as exited.
+func processAlive(pid int, startTime int64) bool {
+	if pid <= 0 || startTime == 0 {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return false
+	}
+	fields := procStatFields(string(data))
+	if len(fields) < 20 || fields[0] == "Z" || fields[0] == "X" {
+		return false
+	}
+	started, err := strconv.ParseInt(fields[19], 10, 64)
+	return err == nil && started == startTime
+}
+
+type procStat struct {
+	pid      int
+	ppid     int
+	pgrp     int
+	rssBytes int64
+}
+
+func readProcStats() ([]procStat, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int64(os.Getpagesize())
+	var stats []procStat
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+
+		fields := procStatFields(string(data))
+		if len(fields) < 22 {
+			continue
+		}
+
+		ppid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		pgrp, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		rssPages, err := strconv.ParseInt(fields[21], 10, 64)
+		if err != nil {
+			continue
+		}
+		stats = append(stats, procStat{pid: pid, ppid: ppid, pgrp: pgrp, rssBytes: rssPages * pageSize})
+	}
+
+	return stats, nil
+}
+
+// procStatFields splits /proc/<pid>/stat into its fields starting at the
+// state field, so index 0 is state, 1 is ppid and 2 is pgrp. The command name
+// is skipped because it may contain spaces and parentheses.
+func procStatFields(stat string) []string {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 || end+2 > len(stat) {
+		return nil
+	}
+	return strings.Fields(stat[end+2:])
+}
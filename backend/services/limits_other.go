@@ -0,0 +1,35 @@
+//go:build !linux
+// +build !linux
+
+package services
+
+import (
+	"errors"
+	"os"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+// resourceLimitsEnforced is false where memory and CPU limits are not
+// enforced: there are no rlimits to set and no memory watchdog.
+const resourceLimitsEnforced = false
+
+func applyResourceLimits(pid int, limits models.ExecutionLimits) error {
+	return nil
+}
+
+func cpuLimitExceeded(state *os.ProcessState, maxCPUSeconds int) bool {
+	return false
+}
+
+func processGroupRSS(pgid int) (int64, error) {
+	return 0, errors.New("process memory sampling is only supported on Linux")
+}
+
+func processTreeUsage(pid int) (int64, int, error) {
+	return 0, 0, errors.New("process sampling is only supported on Linux")
+}
+
+func processMaxRSS(state *os.ProcessState) int64 {
+	return 0
+}
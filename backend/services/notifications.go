@@ -0,0 +1,265 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const (
+	webhookSignatureHeader = "X-Cauldron-Signature"
+	webhookEventHeader     = "X-Cauldron-Event"
+	webhookDeliveryHeader  = "X-Cauldron-Delivery"
+)
+
+// desktopNotificationTimeout bounds how long a platform notifier may take
+// before it is killed.
+const desktopNotificationTimeout = 10 * time.Second
+
+// webhookRetryDelays are the waits between delivery attempts, so a webhook
+// is tried once more than there are delays.
+var webhookRetryDelays = []time.Duration{2 * time.Second, 10 * time.Second, time.Minute}
+
+// NotificationService tells the user when jobs and batches finish: with a
+// desktop notification and by POSTing a JSON payload to the configured
+// webhook URLs. Runs of a batch only raise a desktop notification for the
+// batch as a whole, but every run is still sent to the webhooks.
+type NotificationService struct {
+	queue       *JobQueueService
+	settings    *SettingsService
+	client      *http.Client
+	retryDelays []time.Duration
+	notify      func(ctx context.Context, title, message string) error
+}
+
+func NewNotificationService(queue *JobQueueService, batches *BatchService, settings *SettingsService) *NotificationService {
+	service := &NotificationService{
+		queue:       queue,
+		settings:    settings,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		retryDelays: webhookRetryDelays,
+		notify:      sendDesktopNotification,
+	}
+	queue.OnJobFinished(service.jobFinished)
+	if batches != nil {
+		batches.OnBatchFinished(service.batchFinished)
+	}
+	return service
+}
+
+func (n *NotificationService) jobFinished(jobID string) {
+	job, err := n.queue.GetJob(jobID)
+	if err != nil || !isFinishedStatus(job.Status) {
+		return
+	}
+
+	payload := models.WebhookPayload{
+		Event:           models.WebhookEventJobFinished,
+		JobID:           job.ID,
+		BatchID:         job.BatchID,
+		Name:            job.Name,
+		Plugin:          jobPlugin(job),
+		Status:          job.Status,
+		DurationSeconds: job.DurationSeconds,
+		OutputPath:      jobOutputDir(job),
+		Error:           job.Error,
+		FinishedAt:      jobFinishedAt(job),
+	}
+	if job.BatchID == "" {
+		n.desktop(payload)
+	}
+	n.sendWebhooks(payload)
+}
+
+func (n *NotificationService) batchFinished(summary *models.BatchSummary) {
+	payload := models.WebhookPayload{
+		Event:      models.WebhookEventBatchFinished,
+		BatchID:    summary.Batch.ID,
+		Name:       summary.Batch.Name,
+		Plugin:     summary.Batch.PluginID,
+		Status:     summary.Status,
+		OutputPath: summary.Batch.OutputDir,
+		Total:      summary.Total,
+		Counts:     summary.Counts,
+		FinishedAt: time.Now(),
+	}
+	if summary.Batch.FinishedAt != nil {
+		payload.FinishedAt = *summary.Batch.FinishedAt
+		duration := summary.Batch.FinishedAt.Sub(summary.Batch.CreatedAt).Seconds()
+		payload.DurationSeconds = &duration
+	}
+	n.desktop(payload)
+	n.sendWebhooks(payload)
+}
+
+// desktop shows a notification for a payload in the background, so a slow
+// notifier holds up neither the worker nor the batch index.
+func (n *NotificationService) desktop(payload models.WebhookPayload) {
+	if !n.settings.DesktopNotifications() || n.queue.ctx.Value("wails-test") != nil {
+		return
+	}
+
+	title := fmt.Sprintf("%s %s", payload.Name, strings.ReplaceAll(string(payload.Status), "_", " "))
+	message := payload.Plugin
+	if payload.Event == models.WebhookEventBatchFinished {
+		message = fmt.Sprintf("%d of %d runs completed", payload.Counts[models.JobStatusCompleted], payload.Total)
+	} else if payload.Error != "" {
+		message = payload.Error
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), desktopNotificationTimeout)
+		defer cancel()
+		if err := n.notify(ctx, title, message); err != nil {
+			log.Printf("[NotificationService] Failed to show desktop notification: %v", err)
+		}
+	}()
+}
+
+// sendWebhooks delivers a payload to every configured URL in the
+// background.
+func (n *NotificationService) sendWebhooks(payload models.WebhookPayload) {
+	urls, secret := n.settings.Webhooks()
+	if len(urls) == 0 {
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[NotificationService] Failed to encode webhook payload: %v", err)
+		return
+	}
+	for _, url := range urls {
+		go func(url string) {
+			if err := n.deliver(url, secret, payload.Event, body); err != nil {
+				log.Printf("[NotificationService] Giving up on webhook %s: %v", url, err)
+			}
+		}(url)
+	}
+}
+
+// TestWebhook sends a test payload to url once, without retrying, so the
+// user can check a webhook and its signature before relying on it.
+func (n *NotificationService) TestWebhook(url string) error {
+	if _, err := toWebhookURLs(url); err != nil {
+		return err
+	}
+	_, secret := n.settings.Webhooks()
+	body, err := json.Marshal(models.WebhookPayload{
+		Event:      models.WebhookEventTest,
+		Name:       "Webhook test",
+		Status:     models.JobStatusCompleted,
+		FinishedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = n.post(url, secret, models.WebhookEventTest, uuid.New().String(), body)
+	return err
+}
+
+// deliver POSTs body to url, retrying on network errors, rate limiting and
+// server errors. Every attempt carries the same delivery ID so the receiver
+// can drop duplicates.
+func (n *NotificationService) deliver(url, secret, event string, body []byte) error {
+	delivery := uuid.New().String()
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = n.post(url, secret, event, delivery, body)
+		if err == nil || !retry || attempt >= len(n.retryDelays) {
+			return err
+		}
+		log.Printf("[NotificationService] Webhook %s failed, retrying: %v", url, err)
+		select {
+		case <-time.After(n.retryDelays[attempt]):
+		case <-n.queue.stop:
+			return fmt.Errorf("app is shutting down: %w", err)
+		}
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (n *NotificationService) post(url, secret, event, delivery string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Cauldron")
+	req.Header.Set(webhookEventHeader, event)
+	req.Header.Set(webhookDeliveryHeader, delivery)
+	if secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+// signWebhook is the hex HMAC-SHA256 of body keyed with secret, sent as
+// "sha256=<signature>" so receivers can check a payload came from us.
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendDesktopNotification shows a notification with the notifier each
+// platform ships with, killing it if ctx is done first.
+func sendDesktopNotification(ctx context.Context, title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode(%s)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('Cauldron').Show([Windows.UI.Notifications.ToastNotification]::new($template))`,
+			powerShellString(title), powerShellString(message))
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+		hideConsoleWindow(cmd)
+	default:
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=Cauldron", title, message)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func appleScriptString(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+func powerShellString(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", "''") + "'"
+}
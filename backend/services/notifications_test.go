@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+type webhookRequest struct {
+	delivery  string
+	signature string
+	payload   models.WebhookPayload
+	body      []byte
+}
+
+func TestWebhookRetriesAndSigns(t *testing.T) {
+	var mu sync.Mutex
+	var requests []webhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request := webhookRequest{
+			delivery:  r.Header.Get(webhookDeliveryHeader),
+			signature: r.Header.Get(webhookSignatureHeader),
+			body:      body,
+		}
+		json.Unmarshal(body, &request.payload)
+
+		mu.Lock()
+		requests = append(requests, request)
+		first := len(requests) == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	jobQueue, db := newTestJobQueue(t)
+	settings := &SettingsService{config: &models.Config{
+		DesktopNotifications: true,
+		WebhookURLs:          []string{server.URL},
+		WebhookSecret:        "lab-secret",
+	}}
+	notifications := NewNotificationService(jobQueue, nil, settings)
+	notifications.retryDelays = []time.Duration{10 * time.Millisecond}
+	notifications.notify = func(ctx context.Context, title, message string) error {
+		t.Errorf("Expected no desktop notification in tests, got %q", title)
+		return nil
+	}
+
+	id, err := jobQueue.CreateJobWithOptions("test", "Webhook", "python", []string{}, map[string]interface{}{"outputDir": "/data/out"}, models.JobOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+	waitForJobStatus(t, db, id, models.JobStatusCompleted)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		count := len(requests)
+		mu.Unlock()
+		if count >= 2 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 2 {
+		t.Fatalf("Expected the webhook to be retried once, got %d requests", len(requests))
+	}
+	if requests[0].delivery == "" || requests[0].delivery != requests[1].delivery {
+		t.Errorf("Expected both attempts to share a delivery ID, got %q and %q", requests[0].delivery, requests[1].delivery)
+	}
+	delivered := requests[1]
+	if delivered.signature != "sha256="+signWebhook("lab-secret", delivered.body) {
+		t.Errorf("Unexpected signature %q", delivered.signature)
+	}
+	payload := delivered.payload
+	if payload.Event != models.WebhookEventJobFinished || payload.JobID != id || payload.Status != models.JobStatusCompleted ||
+		payload.Plugin != "test" || payload.OutputPath != "/data/out" || payload.DurationSeconds == nil {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	var mu sync.Mutex
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	jobQueue, _ := newTestJobQueue(t)
+	notifications := NewNotificationService(jobQueue, nil, &SettingsService{config: &models.Config{}})
+	notifications.retryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+
+	if err := notifications.deliver(server.URL, "", models.WebhookEventTest, []byte("{}")); err == nil {
+		t.Error("Expected an unauthorized webhook to fail")
+	}
+	if err := notifications.TestWebhook("ftp://example.com"); err == nil {
+		t.Error("Expected a non-http webhook URL to be rejected")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if count != 1 {
+		t.Errorf("Expected a client error not to be retried, got %d requests", count)
+	}
+}
@@ -14,6 +14,52 @@ func NewPluginExecutor() *PluginExecutor {
 	return &PluginExecutor{}
 }
 
+// PluginJob is the job a plugin run is queued as.
+type PluginJob struct {
+	Args       []string
+	Parameters map[string]interface{}
+	Options    models.JobOptions
+}
+
+// PrepareJob validates the parameters of a plugin run and builds the job
+// that runs it, writing to outputDir. The plugin's execution settings fill
+// in the options left unset.
+func (e *PluginExecutor) PrepareJob(plugin *models.PluginV2, parameters map[string]interface{}, options models.JobOptions, outputDir string) (*PluginJob, error) {
+	job := &PluginJob{Parameters: make(map[string]interface{}), Options: options}
+	for k, v := range parameters {
+		job.Parameters[k] = v
+	}
+
+	if err := e.ValidateParameters(plugin, job.Parameters); err != nil {
+		return nil, fmt.Errorf("parameter validation failed: %w", err)
+	}
+
+	args, err := e.BuildArguments(plugin, job.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build arguments: %w", err)
+	}
+	if plugin.Definition.Execution.OutputDir != "" {
+		args = append(args, plugin.Definition.Execution.OutputDir, outputDir)
+	}
+	job.Args = args
+
+	job.Parameters["outputDir"] = outputDir
+	job.Parameters["pluginId"] = plugin.Definition.Plugin.ID
+	job.Parameters["pluginVersion"] = plugin.Definition.Plugin.Version
+
+	if limits := plugin.Definition.Execution.Limits; limits != nil {
+		job.Options.Limits = job.Options.Limits.WithDefaults(*limits)
+	}
+	if job.Options.Retry == nil {
+		job.Options.Retry = plugin.Definition.Execution.Retry
+	}
+	if len(job.Options.ProgressPatterns) == 0 {
+		job.Options.ProgressPatterns = plugin.Definition.Execution.Progress
+	}
+	job.Options.DeclaredOutputs = plugin.Definition.Outputs
+	return job, nil
+}
+
 func (e *PluginExecutor) BuildArguments(plugin *models.PluginV2, parameters map[string]interface{}) ([]string, error) {
 	args := []string{plugin.ScriptPath}
 
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	goruntime "runtime"
 	"strings"
 
@@ -193,6 +194,23 @@ func (l *PluginLoaderV2) validateDefinition(def *models.PluginDefinition) error
 		}
 	}
 
+	if retry := def.Execution.Retry; retry != nil {
+		if retry.MaxAttempts < 1 {
+			return fmt.Errorf("retry maxAttempts must be at least 1")
+		}
+		for _, pattern := range retry.RetryOnPatterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid retry pattern %q: %v", pattern, err)
+			}
+		}
+	}
+
+	for _, progress := range def.Execution.Progress {
+		if _, err := compileProgressPattern(progress.Pattern); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const (
+	processTerminateGrace = 10 * time.Second
+	processPipeGrace      = 5 * time.Second
+	memoryWatchInterval   = 1 * time.Second
+	processSampleInterval = 2 * time.Second
+)
+
+var (
+	ErrProcessTimedOut     = errors.New("timed out")
+	ErrMemoryLimitExceeded = errors.New("memory limit exceeded")
+	ErrCPULimitExceeded    = errors.New("CPU time limit exceeded")
+)
+
+var allocationFailureMarkers = []string{
+	"cannot allocate vector of size",
+	"MemoryError",
+	"std::bad_alloc",
+	"Cannot allocate memory",
+}
+
+type lineWriter struct {
+	mu       sync.Mutex
+	stream   string
+	buf      []byte
+	callback func(stream string, line string)
+}
+
+func newLineWriter(stream string, callback func(stream string, line string)) *lineWriter {
+	return &lineWriter{
+		stream:   stream,
+		callback: callback,
+	}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		w.emit(string(w.buf[:idx]))
+		w.buf = w.buf[idx+1:]
+	}
+
+	return len(p), nil
+}
+
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *lineWriter) emit(line string) {
+	if w.callback != nil {
+		w.callback(w.stream, strings.TrimRight(line, "\r"))
+	}
+}
+
+type processMetricsKey struct{}
+
+// withProcessMetrics returns a context that makes runProcess record the
+// resource usage of the process it starts into metrics.
+func withProcessMetrics(ctx context.Context, metrics *models.ProcessMetrics) context.Context {
+	return context.WithValue(ctx, processMetricsKey{}, metrics)
+}
+
+// processSample holds the peaks seen while sampling a process tree.
+type processSample struct {
+	mu        sync.Mutex
+	rss       int64
+	processes int
+}
+
+// runProcess starts cmd in its own process group, streams stdout and stderr
+// line by line to outputCallback, tagged with the stream each line came from,
+// and waits for it to exit. When ctx is done the whole process tree is sent
+// SIGTERM and, after processTerminateGrace, SIGKILL. Non-zero limits are
+// enforced with rlimits plus a memory watchdog on Linux and a watchdog for
+// the wall-clock timeout everywhere.
+// When ctx carries process metrics the process tree is sampled while it runs
+// and its exit code and CPU and memory usage are recorded. When ctx carries a
+// console spool the output goes to files there and is followed from them, and
+// the exit status is written there too, for a later session to collect.
+func runProcess(ctx context.Context, cmd *exec.Cmd, limits models.ExecutionLimits, outputCallback func(stream string, line string)) error {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	if limits.Timeout > 0 {
+		timeout := time.Duration(limits.Timeout) * time.Second
+		var cancelTimeout context.CancelFunc
+		runCtx, cancelTimeout = context.WithTimeoutCause(runCtx, timeout,
+			fmt.Errorf("%w after %s", ErrProcessTimedOut, timeout))
+		defer cancelTimeout()
+	}
+
+	var allocationFailed atomic.Bool
+	callback := outputCallback
+	if limits.MaxMemoryMB > 0 {
+		callback = func(stream string, line string) {
+			if isAllocationFailure(line) {
+				allocationFailed.Store(true)
+			}
+			if outputCallback != nil {
+				outputCallback(stream, line)
+			}
+		}
+	}
+
+	hideConsoleWindow(cmd)
+	setProcessGroup(cmd)
+
+	stdout := newLineWriter(models.LogStreamStdout, callback)
+	stderr := newLineWriter(models.LogStreamStderr, callback)
+	spoolDir, _ := ctx.Value(consoleSpoolKey{}).(string)
+	var spool []*os.File
+	proceed := func() {}
+	if spoolDir != "" {
+		files, err := openConsoleSpool(spoolDir, models.LogStreamStdout, models.LogStreamStderr)
+		if err != nil {
+			return fmt.Errorf("failed to create console files: %w", err)
+		}
+		spool = files
+		cmd.Stdout = files[0]
+		cmd.Stderr = files[1]
+		if proceed, err = recordExitStatus(cmd, filepath.Join(spoolDir, exitStatusFileName)); err != nil {
+			files[0].Close()
+			files[1].Close()
+			return fmt.Errorf("failed to record the exit status: %w", err)
+		}
+	} else {
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+	}
+	cmd.WaitDelay = processPipeGrace
+
+	startErr := cmd.Start()
+	for _, file := range spool {
+		file.Close()
+	}
+	if startErr != nil {
+		proceed()
+		return startErr
+	}
+
+	pid := cmd.Process.Pid
+	observeProcess(ctx, pid)
+	if err := applyResourceLimits(pid, limits); err != nil {
+		log.Printf("[runProcess] Failed to apply resource limits to pid %d: %v", pid, err)
+	}
+	proceed()
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-runCtx.Done():
+			terminateProcessTree(pid, processTerminateGrace, done)
+		case <-done:
+		}
+	}()
+
+	if limits.MaxMemoryMB > 0 {
+		go watchMemory(pid, int64(limits.MaxMemoryMB)*1024*1024, done, cancel)
+	}
+
+	metrics, _ := ctx.Value(processMetricsKey{}).(*models.ProcessMetrics)
+	sample := &processSample{}
+	if metrics != nil {
+		go sampleProcessTree(pid, done, sample)
+	}
+
+	var followers sync.WaitGroup
+	if spoolDir != "" {
+		for _, writer := range []*lineWriter{stdout, stderr} {
+			followers.Add(1)
+			go func() {
+				defer followers.Done()
+				followConsole(consoleFile(spoolDir, writer.stream), writer, done)
+			}()
+		}
+	}
+
+	err := cmd.Wait()
+	close(done)
+	followers.Wait()
+
+	stdout.Flush()
+	stderr.Flush()
+
+	if metrics != nil && cmd.ProcessState != nil {
+		sample.mu.Lock()
+		*metrics = processMetrics(cmd.ProcessState, sample)
+		sample.mu.Unlock()
+	}
+
+	if runCtx.Err() != nil {
+		return context.Cause(runCtx)
+	}
+	if err != nil && limits.MaxCPUSeconds > 0 && cpuLimitExceeded(cmd.ProcessState, limits.MaxCPUSeconds) {
+		return fmt.Errorf("%w (%ds)", ErrCPULimitExceeded, limits.MaxCPUSeconds)
+	}
+	if err != nil && allocationFailed.Load() {
+		return fmt.Errorf("%w (%d MB)", ErrMemoryLimitExceeded, limits.MaxMemoryMB)
+	}
+	return err
+}
+
+func watchMemory(pgid int, limitBytes int64, done <-chan struct{}, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(memoryWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			rss, err := processGroupRSS(pgid)
+			if err != nil {
+				log.Printf("[watchMemory] Memory sampling unavailable, watchdog disabled: %v", err)
+				return
+			}
+			if rss > limitBytes {
+				cancel(fmt.Errorf("%w (%d MB used, limit %d MB)", ErrMemoryLimitExceeded,
+					rss/(1024*1024), limitBytes/(1024*1024)))
+				return
+			}
+		}
+	}
+}
+
+// sampleProcessTree records the peak memory and process count of the tree
+// rooted at pid until done is closed.
+func sampleProcessTree(pid int, done <-chan struct{}, sample *processSample) {
+	ticker := time.NewTicker(processSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		rss, processes, err := processTreeUsage(pid)
+		if err != nil {
+			return
+		}
+		sample.mu.Lock()
+		sample.rss = max(sample.rss, rss)
+		sample.processes = max(sample.processes, processes)
+		sample.mu.Unlock()
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func processMetrics(state *os.ProcessState, sample *processSample) models.ProcessMetrics {
+	exitCode := state.ExitCode()
+	return models.ProcessMetrics{
+		ExitCode:         &exitCode,
+		UserCPUSeconds:   state.UserTime().Seconds(),
+		SystemCPUSeconds: state.SystemTime().Seconds(),
+		MaxRSSBytes:      processMaxRSS(state),
+		PeakRSSBytes:     sample.rss,
+		PeakProcesses:    sample.processes,
+	}
+}
+
+// processExitCode returns the exit code carried by an error from runProcess,
+// or -1 when the process did not exit normally.
+func processExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// unenforcedLimitWarnings returns a warning for each memory or CPU limit the
+// platform cannot enforce, so a job does not look protected by limits that
+// are ignored.
+func unenforcedLimitWarnings(limits models.ExecutionLimits) []string {
+	if resourceLimitsEnforced {
+		return nil
+	}
+	var warnings []string
+	if limits.MaxMemoryMB > 0 {
+		warnings = append(warnings, fmt.Sprintf("The %d MB memory limit is not enforced on %s", limits.MaxMemoryMB, runtime.GOOS))
+	}
+	if limits.MaxCPUSeconds > 0 {
+		warnings = append(warnings, fmt.Sprintf("The %ds CPU time limit is not enforced on %s", limits.MaxCPUSeconds, runtime.GOOS))
+	}
+	return warnings
+}
+
+func isAllocationFailure(line string) bool {
+	for _, marker := range allocationFailureMarkers {
+		if strings.Contains(line, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+//go:build darwin
+// +build darwin
+
+package services
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// processLivenessSupported reports whether processAlive can tell whether a
+// process from a previous session is still running.
+const processLivenessSupported = true
+
+// darwinProcessZombie is SZOMB, the p_stat of a process that exited and has
+// not been waited for.
+const darwinProcessZombie = 5
+
+// processStartTime returns when pid started, in microseconds since the
+// epoch. A PID and its start time together identify a process even after
+// the PID is reused.
+func processStartTime(pid int) (int64, error) {
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil {
+		return 0, err
+	}
+	return info.Proc.P_starttime.Sec*1e6 + int64(info.Proc.P_starttime.Usec), nil
+}
+
+// processAlive reports whether the process that started at startTime is
+// still running as pid. Zombies count as exited.
+func processAlive(pid int, startTime int64) bool {
+	if pid <= 0 || startTime == 0 {
+		return false
+	}
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil || info.Proc.P_stat == darwinProcessZombie {
+		return false
+	}
+	return info.Proc.P_starttime.Sec*1e6+int64(info.Proc.P_starttime.Usec) == startTime
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package services
+
+import "errors"
+
+// processLivenessSupported is false where a process from a previous session
+// cannot be told from a reused PID.
+const processLivenessSupported = false
+
+func processStartTime(pid int) (int64, error) {
+	return 0, errors.New("process start times are not available on this platform")
+}
+
+// processAlive cannot tell a process from a reused PID without a start time,
+// so processes from a previous session are treated as exited.
+func processAlive(pid int, startTime int64) bool {
+	return false
+}
@@ -0,0 +1,136 @@
+//go:build !windows
+// +build !windows
+
+package services
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestRunProcessStreamsOutput(t *testing.T) {
+	var mu sync.Mutex
+	var lines []string
+	cmd := exec.Command("sh", "-c", "echo hello; echo oops >&2; printf partial")
+
+	if err := runProcess(context.Background(), cmd, models.ExecutionLimits{}, func(stream string, line string) {
+		mu.Lock()
+		lines = append(lines, stream+": "+line)
+		mu.Unlock()
+	}); err != nil {
+		t.Fatalf("runProcess failed: %v", err)
+	}
+
+	expected := map[string]bool{"stdout: hello": false, "stderr: oops": false, "stdout: partial": false}
+	for _, line := range lines {
+		if _, ok := expected[line]; ok {
+			expected[line] = true
+		}
+	}
+	for line, seen := range expected {
+		if !seen {
+			t.Errorf("Expected output line %q, got %v", line, lines)
+		}
+	}
+}
+
+func TestRunProcessCancelKillsProcessTree(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.Command("sh", "-c", "sleep 60 & sleep 60; wait")
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := runProcess(ctx, cmd, models.ExecutionLimits{}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > processTerminateGrace {
+		t.Errorf("Process tree took too long to terminate: %v", elapsed)
+	}
+}
+
+func TestRunProcessTimeout(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+
+	err := runProcess(context.Background(), cmd, models.ExecutionLimits{Timeout: 1}, nil)
+	if !errors.Is(err, ErrProcessTimedOut) {
+		t.Fatalf("Expected ErrProcessTimedOut, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a timed out message, got %q", err.Error())
+	}
+}
+
+func TestRunProcessKillIsNotCPULimit(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "kill -9 $$")
+
+	err := runProcess(context.Background(), cmd, models.ExecutionLimits{MaxCPUSeconds: 60}, nil)
+	if err == nil || errors.Is(err, ErrCPULimitExceeded) {
+		t.Fatalf("Expected a SIGKILL well under the CPU limit to be reported as a plain failure, got %v", err)
+	}
+}
+
+func TestRunProcessRecordsExitStatus(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), jobConsoleDirName)
+	ctx := withConsoleSpool(context.Background(), dir)
+
+	err := runProcess(ctx, exec.Command("sh", "-c", "echo $0; exit 3", "wrapped"), models.ExecutionLimits{}, nil)
+	if processExitCode(err) != 3 {
+		t.Fatalf("Expected exit code 3, got %v", err)
+	}
+	if status, ok := readExitStatus(dir); !ok || status != 3 {
+		t.Errorf("Expected exit status 3 to be written, got %d %v", status, ok)
+	}
+
+	err = runProcess(ctx, exec.Command("sh", "-c", "kill -9 $$"), models.ExecutionLimits{}, nil)
+	if err == nil || processExitCode(err) != -1 {
+		t.Errorf("Expected the signal to reach the waiter, got %v", err)
+	}
+
+	if goruntime.GOOS == "linux" {
+		cmd := exec.Command("sh", "-c", "while :; do :; done")
+		err = runProcess(ctx, cmd, models.ExecutionLimits{MaxCPUSeconds: 1, Timeout: 30}, nil)
+		if !errors.Is(err, ErrCPULimitExceeded) {
+			t.Errorf("Expected the CPU limit to apply to the wrapped command, got %v", err)
+		}
+	}
+}
+
+func TestRunProcessRecordsMetrics(t *testing.T) {
+	metrics := &models.ProcessMetrics{}
+	ctx := withProcessMetrics(context.Background(), metrics)
+	cmd := exec.Command("sh", "-c", "i=0; while [ $i -lt 20000 ]; do i=$((i+1)); done; sleep 0.3 & wait; exit 3")
+
+	err := runProcess(ctx, cmd, models.ExecutionLimits{}, nil)
+	if processExitCode(err) != 3 {
+		t.Fatalf("Expected exit code 3, got %v", err)
+	}
+	if metrics.ExitCode == nil || *metrics.ExitCode != 3 {
+		t.Errorf("Expected exit code 3 to be recorded, got %+v", metrics)
+	}
+	if metrics.CPUSeconds() <= 0 {
+		t.Errorf("Expected CPU time to be recorded, got %+v", metrics)
+	}
+	if goruntime.GOOS == "linux" {
+		if metrics.MaxRSSBytes <= 0 || metrics.PeakRSSBytes <= 0 {
+			t.Errorf("Expected memory usage to be recorded, got %+v", metrics)
+		}
+		if metrics.PeakProcesses < 1 {
+			t.Errorf("Expected the process tree to be sampled, got %+v", metrics)
+		}
+	}
+}
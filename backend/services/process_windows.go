@@ -0,0 +1,82 @@
+//go:build windows
+// +build windows
+
+package services
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// processLivenessSupported reports whether processAlive can tell whether a
+// process from a previous session is still running.
+const processLivenessSupported = true
+
+// stillActive is the exit code GetExitCodeProcess reports for a process
+// that is still running.
+const stillActive = 259
+
+// processStartTime returns when pid was created, in 100-nanosecond intervals
+// since 1601. A PID and its creation time together identify a process even
+// after the PID is reused.
+func processStartTime(pid int) (int64, error) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(handle)
+	return processCreationTime(handle)
+}
+
+// processAlive reports whether the process created at startTime is still
+// running as pid.
+func processAlive(pid int, startTime int64) bool {
+	if pid <= 0 || startTime == 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle)
+
+	var exitCode uint32
+	if err := windows.GetExitCodeProcess(handle, &exitCode); err != nil || exitCode != stillActive {
+		return false
+	}
+	created, err := processCreationTime(handle)
+	return err == nil && created == startTime
+}
+
+// watchExitCode opens pid, if it is still the process created at startTime,
+// so its exit code can be read once it exits. The returned function reports
+// that exit code and releases the process; it must be called once.
+func watchExitCode(pid int, startTime int64) func() (int, bool) {
+	unknown := func() (int, bool) {
+		return 0, false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return unknown
+	}
+	if created, err := processCreationTime(handle); err != nil || created != startTime {
+		windows.CloseHandle(handle)
+		return unknown
+	}
+
+	return func() (int, bool) {
+		defer windows.CloseHandle(handle)
+		var exitCode uint32
+		if err := windows.GetExitCodeProcess(handle, &exitCode); err != nil || exitCode == stillActive {
+			return 0, false
+		}
+		return int(exitCode), true
+	}
+}
+
+func processCreationTime(handle windows.Handle) (int64, error) {
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return 0, err
+	}
+	return int64(creation.HighDateTime)<<32 | int64(creation.LowDateTime), nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+// progressLinePrefix marks a progress report printed by a script, e.g.
+//
+//	##cauldron progress=42 message="fitting model"
+//
+// Either key may be omitted. Protocol lines are kept out of the job log.
+const progressLinePrefix = "##cauldron"
+
+// progressFlushInterval bounds how often progress reports are written to the
+// database and sent to the UI.
+const progressFlushInterval = 500 * time.Millisecond
+
+type progressUpdate struct {
+	progress   *float64
+	message    string
+	hasMessage bool
+}
+
+// parseProgressLine parses a ##cauldron protocol line. The second result is
+// false when the line is not a protocol line.
+func parseProgressLine(line string) (progressUpdate, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), progressLinePrefix)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return progressUpdate{}, false
+	}
+
+	var update progressUpdate
+	for key, value := range parseProtocolFields(rest) {
+		switch key {
+		case "progress":
+			if p, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64); err == nil {
+				p = clampProgress(p)
+				update.progress = &p
+			}
+		case "message":
+			update.message = value
+			update.hasMessage = true
+		}
+	}
+	return update, true
+}
+
+// parseProtocolFields splits space separated key=value pairs. Values may be
+// double quoted, with \" and \\ escapes.
+func parseProtocolFields(s string) map[string]string {
+	fields := make(map[string]string)
+	i := 0
+	for i < len(s) {
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+			i++
+		}
+		start := i
+		for i < len(s) && s[i] != '=' && s[i] != ' ' && s[i] != '\t' {
+			i++
+		}
+		key := s[start:i]
+		if i >= len(s) || s[i] != '=' {
+			continue
+		}
+		i++
+
+		var value strings.Builder
+		if i < len(s) && s[i] == '"' {
+			i++
+			for i < len(s) && s[i] != '"' {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteByte(s[i])
+				i++
+			}
+			i++
+		} else {
+			for i < len(s) && s[i] != ' ' && s[i] != '\t' {
+				value.WriteByte(s[i])
+				i++
+			}
+		}
+
+		if key != "" {
+			fields[key] = value.String()
+		}
+	}
+	return fields
+}
+
+func clampProgress(p float64) float64 {
+	if p < 0 {
+		return 0
+	}
+	if p > 100 {
+		return 100
+	}
+	return p
+}
+
+type compiledProgressPattern struct {
+	re   *regexp.Regexp
+	hide bool
+}
+
+// progressParser recognises the ##cauldron protocol and any patterns declared
+// by the plugin that created the job.
+type progressParser struct {
+	patterns []compiledProgressPattern
+}
+
+func newProgressParser(patterns []models.ProgressPattern) *progressParser {
+	parser := &progressParser{}
+	for _, pattern := range patterns {
+		re, err := compileProgressPattern(pattern.Pattern)
+		if err != nil {
+			log.Printf("[newProgressParser] Ignoring progress pattern: %v", err)
+			continue
+		}
+		parser.patterns = append(parser.patterns, compiledProgressPattern{re: re, hide: pattern.Hide})
+	}
+	return parser
+}
+
+// compileProgressPattern compiles a plugin progress pattern and checks that
+// it captures something usable.
+func compileProgressPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid progress pattern %q: %v", pattern, err)
+	}
+
+	groups := make(map[string]bool)
+	for _, name := range re.SubexpNames() {
+		groups[name] = true
+	}
+	if !groups["progress"] && !(groups["current"] && groups["total"]) && !groups["message"] {
+		return nil, fmt.Errorf("progress pattern %q needs a named group: progress, current and total, or message", pattern)
+	}
+	return re, nil
+}
+
+// Parse reports whether line carries progress and whether it should be left
+// out of the job log.
+func (p *progressParser) Parse(line string) (update progressUpdate, matched bool, hide bool) {
+	if update, ok := parseProgressLine(line); ok {
+		return update, true, true
+	}
+
+	for _, pattern := range p.patterns {
+		match := pattern.re.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		groups := make(map[string]string)
+		for i, name := range pattern.re.SubexpNames() {
+			if name != "" {
+				groups[name] = match[i]
+			}
+		}
+
+		if value, err := strconv.ParseFloat(groups["progress"], 64); err == nil {
+			value = clampProgress(value)
+			update.progress = &value
+		} else {
+			current, currentErr := strconv.ParseFloat(groups["current"], 64)
+			total, totalErr := strconv.ParseFloat(groups["total"], 64)
+			if currentErr == nil && totalErr == nil && total > 0 {
+				value := clampProgress(current / total * 100)
+				update.progress = &value
+			}
+		}
+		if message, ok := groups["message"]; ok && message != "" {
+			update.message = message
+			update.hasMessage = true
+		}
+
+		return update, true, pattern.hide
+	}
+
+	return progressUpdate{}, false, false
+}
+
+// progressReporter applies progress updates to a running job and persists
+// them at most once per progressFlushInterval, always writing the latest
+// state once the interval has passed.
+type progressReporter struct {
+	queue     *JobQueueService
+	job       *models.Job
+	mu        sync.Mutex
+	lastFlush time.Time
+	timer     *time.Timer
+	stopped   bool
+}
+
+func newProgressReporter(queue *JobQueueService, job *models.Job) *progressReporter {
+	return &progressReporter{
+		queue: queue,
+		job:   job,
+	}
+}
+
+func (r *progressReporter) Report(update progressUpdate) {
+	r.queue.mu.Lock()
+	if update.progress != nil {
+		r.job.Progress = *update.progress
+	}
+	if update.hasMessage {
+		r.job.ProgressMessage = update.message
+	}
+	r.queue.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped || r.timer != nil {
+		return
+	}
+
+	wait := progressFlushInterval - time.Since(r.lastFlush)
+	if wait <= 0 {
+		r.flushLocked()
+		return
+	}
+
+	r.timer = time.AfterFunc(wait, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.timer = nil
+		if !r.stopped {
+			r.flushLocked()
+		}
+	})
+}
+
+// Stop discards any pending flush; the caller saves the final job state.
+func (r *progressReporter) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stopped = true
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+func (r *progressReporter) flushLocked() {
+	r.lastFlush = time.Now()
+
+	r.queue.mu.RLock()
+	defer r.queue.mu.RUnlock()
+
+	err := r.queue.db.GetDB().Model(&models.Job{}).Where("id = ?", r.job.ID).Updates(map[string]interface{}{
+		"progress":         r.job.Progress,
+		"progress_message": r.job.ProgressMessage,
+	}).Error
+	if err != nil {
+		log.Printf("[progressReporter] Failed to save progress of job %s: %v", r.job.ID, err)
+	}
+	r.queue.emitJobUpdate(r.job)
+}
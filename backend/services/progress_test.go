@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestParseProgressLine(t *testing.T) {
+	cases := []struct {
+		line     string
+		ok       bool
+		progress float64
+		message  string
+	}{
+		{`##cauldron progress=42 message="fitting model"`, true, 42, "fitting model"},
+		{`##cauldron progress=150`, true, 100, ""},
+		{`  ##cauldron message="say \"hi\"" progress=7.5%`, true, 7.5, `say "hi"`},
+		{`##cauldronish progress=1`, false, 0, ""},
+		{`progress=42`, false, 0, ""},
+	}
+
+	for _, c := range cases {
+		update, ok := parseProgressLine(c.line)
+		if ok != c.ok {
+			t.Errorf("%q: expected ok=%v, got %v", c.line, c.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if update.progress == nil || *update.progress != c.progress {
+			t.Errorf("%q: expected progress %v, got %v", c.line, c.progress, update.progress)
+		}
+		if update.message != c.message {
+			t.Errorf("%q: expected message %q, got %q", c.line, c.message, update.message)
+		}
+	}
+}
+
+func TestProgressParserPluginPatterns(t *testing.T) {
+	parser := newProgressParser([]models.ProgressPattern{
+		{Pattern: `Processing query (?P<current>\d+) of (?P<total>\d+)`},
+		{Pattern: `^\[(?P<progress>\d+)%\] (?P<message>.*)$`, Hide: true},
+	})
+
+	update, matched, hide := parser.Parse("Processing query 5 of 20")
+	if !matched || hide || update.progress == nil || *update.progress != 25 {
+		t.Errorf("Unexpected result for fraction pattern: %+v matched=%v hide=%v", update, matched, hide)
+	}
+
+	update, matched, hide = parser.Parse("[60%] aligning")
+	if !matched || !hide || *update.progress != 60 || update.message != "aligning" {
+		t.Errorf("Unexpected result for percentage pattern: %+v matched=%v hide=%v", update, matched, hide)
+	}
+
+	if _, matched, _ := parser.Parse("ordinary output"); matched {
+		t.Error("Expected ordinary output not to match")
+	}
+
+	if _, err := compileProgressPattern(`(\d+)%`); err == nil {
+		t.Error("Expected a pattern without named groups to be rejected")
+	}
+}
+
+func TestJobProgressFromScriptOutput(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+
+	script := `echo start; echo '##cauldron progress=40 message="fitting model"'; sleep 1; echo done`
+	jobID, err := jobQueue.CreateJob("test", "Reporter", "direct", []string{"sh", "-c", script})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var job models.Job
+		db.GetDB().First(&job, "id = ?", jobID)
+		if job.Progress == 40 && job.ProgressMessage == "fitting model" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Progress was not reported while running: progress=%v message=%q status=%s", job.Progress, job.ProgressMessage, job.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	job := waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+	if job.Progress != 100 || job.ProgressMessage != "" {
+		t.Errorf("Expected completed progress, got %v %q", job.Progress, job.ProgressMessage)
+	}
+
+	page, err := jobQueue.Logs().GetJobLog(jobID, 0, 0, "")
+	if err != nil {
+		t.Fatalf("GetJobLog failed: %v", err)
+	}
+	if page.Total != 2 || page.Lines[0].Line != "start" || page.Lines[1].Line != "done" {
+		t.Errorf("Expected protocol lines to be hidden from the log, got %+v", page.Lines)
+	}
+}
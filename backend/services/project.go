@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const activeProjectSetting = "activeProject"
+
+type ProjectService struct {
+	ctx    context.Context
+	db     *DatabaseService
+	mu     sync.RWMutex
+	active string
+}
+
+func NewProjectService(ctx context.Context, db *DatabaseService) *ProjectService {
+	return &ProjectService{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+func (p *ProjectService) GetProject(id string) (*models.Project, error) {
+	var project models.Project
+	if err := p.db.GetDB().First(&project, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("project not found: %s", id)
+	}
+	return &project, nil
+}
+
+// ListProjects returns every project with the number of jobs, files and
+// sample annotations it owns, the default project first.
+func (p *ProjectService) ListProjects() ([]models.ProjectSummary, error) {
+	var projects []models.Project
+	if err := p.db.GetDB().Order("id = 'default' DESC, name ASC").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	active := p.ActiveProjectID()
+	summaries := make([]models.ProjectSummary, len(projects))
+	for i, project := range projects {
+		summaries[i] = models.ProjectSummary{Project: project, Active: project.ID == active}
+		p.db.GetDB().Model(&models.Job{}).Where("project_id = ?", project.ID).Count(&summaries[i].JobCount)
+		p.db.GetDB().Model(&ImportedFile{}).Where("project_id = ?", project.ID).Count(&summaries[i].FileCount)
+		p.db.GetDB().Model(&models.SampleAnnotation{}).Where("project_id = ?", project.ID).Count(&summaries[i].AnnotationCount)
+	}
+	return summaries, nil
+}
+
+func (p *ProjectService) CreateProject(name string, description string, outputDirectory string) (*models.Project, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("project name is required")
+	}
+
+	var count int64
+	p.db.GetDB().Model(&models.Project{}).Where("name = ?", name).Count(&count)
+	if count > 0 {
+		return nil, fmt.Errorf("a project named %q already exists", name)
+	}
+
+	project := &models.Project{
+		ID:              uuid.New().String(),
+		Name:            name,
+		Description:     strings.TrimSpace(description),
+		OutputDirectory: outputDirectory,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	if err := p.db.GetDB().Create(project).Error; err != nil {
+		return nil, err
+	}
+
+	log.Printf("[CreateProject] Created project %s (%s)", project.Name, project.ID)
+	return project, nil
+}
+
+// DeleteProject removes an empty project. Jobs, including those in the
+// trash, and files have to be deleted first so nothing is dropped by
+// accident. Its sample annotations go with it.
+func (p *ProjectService) DeleteProject(id string) error {
+	if id == models.DefaultProjectID {
+		return fmt.Errorf("the default project cannot be deleted")
+	}
+
+	var jobs, files int64
+	p.db.GetDB().Unscoped().Model(&models.Job{}).Where("project_id = ?", id).Count(&jobs)
+	p.db.GetDB().Model(&ImportedFile{}).Where("project_id = ?", id).Count(&files)
+	if jobs > 0 || files > 0 {
+		return fmt.Errorf("project still has %d job(s), counting the trash, and %d file(s)", jobs, files)
+	}
+
+	err := p.db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", id).Delete(&models.SampleAnnotation{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Project{}, "id = ?", id).Error
+	})
+	if err != nil {
+		return err
+	}
+	if p.ActiveProjectID() == id {
+		return p.SetActiveProject(models.DefaultProjectID)
+	}
+	return nil
+}
+
+// GetSampleAnnotations returns the sample annotations of a project, ordered
+// by sample.
+func (p *ProjectService) GetSampleAnnotations(projectID string) ([]models.SampleAnnotation, error) {
+	annotations := []models.SampleAnnotation{}
+	err := p.db.GetDB().Where("project_id = ?", projectID).Order("sample ASC").Find(&annotations).Error
+	return annotations, err
+}
+
+// SaveSampleAnnotations adds annotations to a project, replacing those of
+// samples it already annotates. Rows without a sample name are skipped.
+func (p *ProjectService) SaveSampleAnnotations(projectID string, annotations []models.SampleAnnotation) error {
+	if _, err := p.GetProject(projectID); err != nil {
+		return err
+	}
+	return p.db.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, annotation := range annotations {
+			annotation.ID = 0
+			annotation.ProjectID = projectID
+			annotation.Sample = strings.TrimSpace(annotation.Sample)
+			if annotation.Sample == "" {
+				continue
+			}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "project_id"}, {Name: "sample"}},
+				DoUpdates: clause.AssignmentColumns([]string{"condition", "batch", "color"}),
+			}).Create(&annotation).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteSampleAnnotations removes the annotations of the given samples from
+// a project.
+func (p *ProjectService) DeleteSampleAnnotations(projectID string, samples []string) error {
+	return p.db.GetDB().Where("project_id = ? AND sample IN ?", projectID, samples).
+		Delete(&models.SampleAnnotation{}).Error
+}
+
+// ActiveProjectID returns the project new jobs and files are added to.
+func (p *ProjectService) ActiveProjectID() string {
+	p.mu.RLock()
+	active := p.active
+	p.mu.RUnlock()
+	if active != "" {
+		return active
+	}
+
+	active, _ = p.db.GetSetting(activeProjectSetting)
+	if active == "" {
+		active = models.DefaultProjectID
+	} else if _, err := p.GetProject(active); err != nil {
+		log.Printf("[ActiveProjectID] Active project %s no longer exists, using default", active)
+		active = models.DefaultProjectID
+	}
+
+	p.mu.Lock()
+	p.active = active
+	p.mu.Unlock()
+	return active
+}
+
+func (p *ProjectService) GetActiveProject() (*models.Project, error) {
+	return p.GetProject(p.ActiveProjectID())
+}
+
+func (p *ProjectService) SetActiveProject(id string) error {
+	project, err := p.GetProject(id)
+	if err != nil {
+		return err
+	}
+	if err := p.db.SaveSetting(activeProjectSetting, id); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.active = id
+	p.mu.Unlock()
+
+	if p.ctx.Value("wails-test") == nil {
+		runtime.EventsEmit(p.ctx, "project:changed", project)
+	}
+	return nil
+}
+
+// OutputDirectory returns the project's own output directory, or fallback
+// when it uses the configured one.
+func (p *ProjectService) OutputDirectory(projectID string, fallback string) string {
+	if project, err := p.GetProject(projectID); err == nil && project.OutputDirectory != "" {
+		return project.OutputDirectory
+	}
+	return fallback
+}
+
+// ProjectOutputDirectory returns the directory a new project's outputs go
+// to, below the configured output directory.
+func ProjectOutputDirectory(baseOutputDir string, name string) string {
+	return filepath.Join(baseOutputDir, "projects", safeFileName(name))
+}
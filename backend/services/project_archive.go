@@ -0,0 +1,219 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const (
+	ProjectArchiveExtension     = ".cauldron-project.tar.xz"
+	ProjectArchiveFormatVersion = 1
+	projectManifestFile         = "project.json"
+	projectJobsDir              = "jobs"
+)
+
+// projectArchiveManifest is stored as project.json in a project archive.
+// Imported files are recorded by path only; sample annotations are stored
+// in full and each finished job is stored as a job bundle under jobs/.
+type projectArchiveManifest struct {
+	FormatVersion int                       `json:"formatVersion"`
+	CreatedAt     time.Time                 `json:"createdAt"`
+	Project       models.Project            `json:"project"`
+	Files         []ImportedFile            `json:"files"`
+	Annotations   []models.SampleAnnotation `json:"annotations,omitempty"`
+	Jobs          []string                  `json:"jobs"`
+}
+
+// ExportProject writes a project with its imported file list, its sample
+// annotations and the bundles of all its finished jobs to destPath, or into destPath when it is a
+// directory, and returns the path written.
+func (b *JobBundleService) ExportProject(projectID string, destPath string) (string, error) {
+	project, err := b.queue.projects.GetProject(projectID)
+	if err != nil {
+		return "", err
+	}
+
+	if info, err := os.Stat(destPath); err == nil && info.IsDir() {
+		destPath = filepath.Join(destPath, safeFileName(project.Name)+ProjectArchiveExtension)
+	}
+
+	manifest := projectArchiveManifest{
+		FormatVersion: ProjectArchiveFormatVersion,
+		CreatedAt:     time.Now(),
+		Project:       *project,
+		Jobs:          []string{},
+	}
+	if manifest.Files, err = b.queue.db.GetImportedFiles(projectID); err != nil {
+		return "", err
+	}
+	if manifest.Annotations, err = b.queue.projects.GetSampleAnnotations(projectID); err != nil {
+		return "", err
+	}
+
+	var jobs []models.Job
+	if err := b.queue.db.GetDB().Where("project_id = ? AND status IN ?", projectID,
+		[]models.JobStatus{models.JobStatusCompleted, models.JobStatusFailed, models.JobStatusCancelled}).
+		Order("created_at ASC").Find(&jobs).Error; err != nil {
+		return "", err
+	}
+
+	tempDir, err := os.MkdirTemp("", "cauldron-project-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tempDir)
+
+	archive, err := b.files.CreateTarXz(destPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create project archive: %w", err)
+	}
+
+	err = func() error {
+		for _, job := range jobs {
+			bundlePath, err := b.ExportJobBundle(job.ID, filepath.Join(tempDir, job.ID+JobBundleExtension))
+			if err != nil {
+				return fmt.Errorf("failed to export job %s: %w", job.ID, err)
+			}
+			name := projectJobsDir + "/" + filepath.Base(bundlePath)
+			if _, err := archive.AddFile(name, bundlePath); err != nil {
+				return err
+			}
+			os.Remove(bundlePath)
+			manifest.Jobs = append(manifest.Jobs, name)
+		}
+
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		return archive.AddBytes(projectManifestFile, data)
+	}()
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(destPath)
+		return "", err
+	}
+
+	log.Printf("[ExportProject] Wrote project %s with %d job(s) and %d file(s) to %s",
+		project.Name, len(manifest.Jobs), len(manifest.Files), destPath)
+	return destPath, nil
+}
+
+// ImportProject recreates a project from an archive written by
+// ExportProject. The project is renamed if its name is already taken. If any
+// part of the archive fails to import, the project and everything imported
+// into it so far are removed again.
+func (b *JobBundleService) ImportProject(archivePath string) (*models.ProjectImport, error) {
+	tempDir, err := os.MkdirTemp("", "cauldron-project-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := b.files.ExtractTarXz(archivePath, tempDir); err != nil {
+		return nil, fmt.Errorf("failed to extract project archive: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, projectManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("archive has no project manifest: %w", err)
+	}
+	var manifest projectArchiveManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid project manifest: %w", err)
+	}
+	if manifest.FormatVersion > ProjectArchiveFormatVersion {
+		return nil, fmt.Errorf("project format version %d is newer than supported version %d",
+			manifest.FormatVersion, ProjectArchiveFormatVersion)
+	}
+
+	name := manifest.Project.Name
+	for i := 2; ; i++ {
+		var count int64
+		b.queue.db.GetDB().Model(&models.Project{}).Where("name = ?", name).Count(&count)
+		if count == 0 {
+			break
+		}
+		name = fmt.Sprintf("%s (%d)", manifest.Project.Name, i)
+	}
+
+	project, err := b.queue.projects.CreateProject(name, manifest.Project.Description,
+		ProjectOutputDirectory(b.configuredOutputDirectory(), name))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ProjectImport{
+		Project:      *project,
+		Jobs:         []models.JobBundleImport{},
+		MissingFiles: []string{},
+	}
+	if err := b.importProjectContents(tempDir, &manifest, project, result); err != nil {
+		b.discardProject(project, result)
+		return nil, err
+	}
+
+	log.Printf("[ImportProject] Imported project %s with %d job(s) and %d file(s)",
+		project.Name, len(result.Jobs), result.FileCount)
+	return result, nil
+}
+
+func (b *JobBundleService) importProjectContents(tempDir string, manifest *projectArchiveManifest, project *models.Project, result *models.ProjectImport) error {
+	for _, file := range manifest.Files {
+		file.ID = 0
+		file.ProjectID = project.ID
+		if err := b.queue.db.SaveImportedFile(&file); err != nil {
+			return err
+		}
+		result.FileCount++
+		if _, err := os.Stat(file.Path); err != nil {
+			result.MissingFiles = append(result.MissingFiles, file.Path)
+		}
+	}
+
+	if err := b.queue.projects.SaveSampleAnnotations(project.ID, manifest.Annotations); err != nil {
+		return fmt.Errorf("failed to import sample annotations: %w", err)
+	}
+	result.AnnotationCount = len(manifest.Annotations)
+
+	for _, name := range manifest.Jobs {
+		bundlePath, err := archiveEntryPath(tempDir, name)
+		if err != nil {
+			return err
+		}
+		imported, err := b.importJobBundle(bundlePath, project.ID)
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", name, err)
+		}
+		result.Jobs = append(result.Jobs, *imported)
+	}
+	return nil
+}
+
+// discardProject removes a partly imported project: the jobs imported into
+// it with their outputs, its files and annotations, and the project itself.
+func (b *JobBundleService) discardProject(project *models.Project, result *models.ProjectImport) {
+	for _, job := range result.Jobs {
+		if err := b.queue.PurgeJob(job.JobID); err != nil {
+			log.Printf("[ImportProject] Failed to remove imported job %s: %v", job.JobID, err)
+		}
+		os.RemoveAll(job.OutputPath)
+	}
+	if err := b.queue.db.GetDB().Where("project_id = ?", project.ID).Delete(&ImportedFile{}).Error; err != nil {
+		log.Printf("[ImportProject] Failed to remove files of project %s: %v", project.ID, err)
+	}
+	if err := b.queue.projects.DeleteProject(project.ID); err != nil {
+		log.Printf("[ImportProject] Failed to remove project %s: %v", project.ID, err)
+	}
+	if project.OutputDirectory != "" {
+		os.Remove(project.OutputDirectory)
+	}
+}
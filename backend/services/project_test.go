@@ -0,0 +1,229 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestMigrateProjectsAssignsDefaultProject(t *testing.T) {
+	db := createTestDB(t)
+	defer db.Close()
+
+	db.GetDB().Create(&models.Job{ID: "legacy", Type: "test", Name: "Legacy", Command: "python", Status: models.JobStatusCompleted})
+	db.GetDB().Create(&ImportedFile{Name: "data.tsv", Path: "/data.tsv", Size: 1, ImportedAt: 1})
+	db.GetDB().Model(&models.Job{}).Where("id = ?", "legacy").Update("project_id", "")
+
+	if err := db.migrateProjects(); err != nil {
+		t.Fatalf("migrateProjects failed: %v", err)
+	}
+
+	var job models.Job
+	db.GetDB().First(&job, "id = ?", "legacy")
+	if job.ProjectID != models.DefaultProjectID {
+		t.Errorf("Expected the legacy job in the default project, got %q", job.ProjectID)
+	}
+	if files, _ := db.GetImportedFiles(models.DefaultProjectID); len(files) != 1 {
+		t.Errorf("Expected the legacy file in the default project, got %+v", files)
+	}
+}
+
+func TestJobsAreScopedToActiveProject(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+	projects := jobQueue.Projects()
+
+	if projects.ActiveProjectID() != models.DefaultProjectID {
+		t.Fatalf("Expected the default project to be active, got %s", projects.ActiveProjectID())
+	}
+
+	defaultJob, _ := jobQueue.CreateJobWithOptions("test", "Default job", "direct", []string{"true"}, nil, models.JobOptions{})
+	waitForJobStatus(t, db, defaultJob, models.JobStatusCompleted)
+
+	study, err := projects.CreateProject("Study A", "", t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	if _, err := projects.CreateProject("Study A", "", ""); err == nil {
+		t.Error("Expected a duplicate project name to be rejected")
+	}
+	if err := projects.SetActiveProject(study.ID); err != nil {
+		t.Fatalf("SetActiveProject failed: %v", err)
+	}
+
+	studyJob, _ := jobQueue.CreateJobWithOptions("test", "Study job", "direct", []string{"true"}, nil, models.JobOptions{})
+	waitForJobStatus(t, db, studyJob, models.JobStatusCompleted)
+
+	jobs := jobQueue.GetAllJobs()
+	if len(jobs) != 1 || jobs[0].ID != studyJob || jobs[0].ProjectID != study.ID {
+		t.Errorf("Expected only the study job, got %+v", jobs)
+	}
+
+	if err := projects.DeleteProject(study.ID); err == nil {
+		t.Error("Expected deleting a project with jobs to fail")
+	}
+	if err := projects.DeleteProject(models.DefaultProjectID); err == nil {
+		t.Error("Expected deleting the default project to fail")
+	}
+
+	jobQueue.DeleteJob(studyJob)
+	if err := projects.DeleteProject(study.ID); err == nil {
+		t.Error("Expected deleting a project with trashed jobs to fail")
+	}
+	jobQueue.PurgeJob(studyJob)
+	if err := projects.DeleteProject(study.ID); err != nil {
+		t.Fatalf("DeleteProject failed: %v", err)
+	}
+	if projects.ActiveProjectID() != models.DefaultProjectID {
+		t.Errorf("Expected the default project to become active, got %s", projects.ActiveProjectID())
+	}
+}
+
+func TestExportAndImportProject(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+	projects := jobQueue.Projects()
+
+	settings := NewSettingsService(jobQueue.ctx, db)
+	settings.Set("outputDirectory", t.TempDir())
+
+	study, err := projects.CreateProject("Study B", "Time course", t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	db.SaveImportedFile(&ImportedFile{ProjectID: study.ID, Name: "gone.tsv", Path: "/nonexistent/gone.tsv", Size: 1, ImportedAt: 1})
+	if err := projects.SaveSampleAnnotations(study.ID, []models.SampleAnnotation{
+		{Sample: "T0_1", Condition: "T0", Color: "#1f77b4"},
+		{Sample: "T4_1", Condition: "T4", Batch: "b1"},
+	}); err != nil {
+		t.Fatalf("SaveSampleAnnotations failed: %v", err)
+	}
+
+	jobID, _ := jobQueue.CreateJobWithOptions("test", "Study job", "direct",
+		[]string{"sh", "-c", "echo done > result.txt"},
+		map[string]interface{}{"outputDir": t.TempDir()},
+		models.JobOptions{ProjectID: study.ID})
+	waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+
+	bundles := NewJobBundleService(jobQueue, nil, nil, NewFileService(jobQueue.ctx), settings)
+	archivePath, err := bundles.ExportProject(study.ID, t.TempDir())
+	if err != nil {
+		t.Fatalf("ExportProject failed: %v", err)
+	}
+	if filepath.Base(archivePath) != "Study_B"+ProjectArchiveExtension {
+		t.Errorf("Unexpected archive name %s", archivePath)
+	}
+
+	result, err := bundles.ImportProject(archivePath)
+	if err != nil {
+		t.Fatalf("ImportProject failed: %v", err)
+	}
+	if result.Project.Name != "Study B (2)" || result.Project.Description != "Time course" {
+		t.Errorf("Unexpected imported project: %+v", result.Project)
+	}
+	if result.FileCount != 1 || len(result.MissingFiles) != 1 {
+		t.Errorf("Expected the missing file to be reported, got %+v", result)
+	}
+	annotations, _ := projects.GetSampleAnnotations(result.Project.ID)
+	if result.AnnotationCount != 2 || len(annotations) != 2 || annotations[1].Condition != "T4" || annotations[1].Batch != "b1" {
+		t.Errorf("Expected the sample annotations to be imported, got %+v", annotations)
+	}
+	if len(result.Jobs) != 1 {
+		t.Fatalf("Expected one imported job, got %+v", result.Jobs)
+	}
+
+	imported, err := jobQueue.GetJob(result.Jobs[0].JobID)
+	if err != nil {
+		t.Fatalf("Imported job not found: %v", err)
+	}
+	if imported.ProjectID != result.Project.ID {
+		t.Errorf("Expected the job in the imported project, got %s", imported.ProjectID)
+	}
+	if _, err := os.Stat(filepath.Join(result.Jobs[0].OutputPath, "result.txt")); err != nil {
+		t.Errorf("Expected the job output to be unpacked: %v", err)
+	}
+}
+
+func TestSampleAnnotationsAreScopedToProject(t *testing.T) {
+	jobQueue, _ := newTestJobQueue(t)
+	projects := jobQueue.Projects()
+	study, _ := projects.CreateProject("Study C", "", "")
+
+	if err := projects.SaveSampleAnnotations(study.ID, []models.SampleAnnotation{{Sample: "A1", Condition: "ctrl"}, {Sample: " "}}); err != nil {
+		t.Fatalf("SaveSampleAnnotations failed: %v", err)
+	}
+	if err := projects.SaveSampleAnnotations(study.ID, []models.SampleAnnotation{{Sample: "A1", Condition: "treated"}}); err != nil {
+		t.Fatalf("SaveSampleAnnotations failed: %v", err)
+	}
+	annotations, _ := projects.GetSampleAnnotations(study.ID)
+	if len(annotations) != 1 || annotations[0].Condition != "treated" {
+		t.Errorf("Expected the sample to be re-annotated, got %+v", annotations)
+	}
+	if others, _ := projects.GetSampleAnnotations(models.DefaultProjectID); len(others) != 0 {
+		t.Errorf("Expected no annotations in the default project, got %+v", others)
+	}
+
+	if err := projects.DeleteProject(study.ID); err != nil {
+		t.Fatalf("DeleteProject failed: %v", err)
+	}
+	if left, _ := projects.GetSampleAnnotations(study.ID); len(left) != 0 {
+		t.Errorf("Expected the annotations to go with the project, got %+v", left)
+	}
+}
+
+func TestImportProjectRollsBackOnFailure(t *testing.T) {
+	jobQueue, db := newTestJobQueueWithDirectRunner(t)
+	settings := NewSettingsService(jobQueue.ctx, db)
+	outputRoot := t.TempDir()
+	settings.Set("outputDirectory", outputRoot)
+	bundles := NewJobBundleService(jobQueue, nil, nil, NewFileService(jobQueue.ctx), settings)
+
+	jobID, _ := jobQueue.CreateJobWithOptions("test", "Good job", "direct",
+		[]string{"sh", "-c", "echo done > result.txt"},
+		map[string]interface{}{"outputDir": t.TempDir()}, models.JobOptions{})
+	waitForJobStatus(t, db, jobID, models.JobStatusCompleted)
+	bundlePath, err := bundles.ExportJobBundle(jobID, filepath.Join(t.TempDir(), "good"+JobBundleExtension))
+	if err != nil {
+		t.Fatalf("ExportJobBundle failed: %v", err)
+	}
+	broken := filepath.Join(t.TempDir(), "broken"+JobBundleExtension)
+	os.WriteFile(broken, []byte("not a bundle"), 0644)
+
+	manifest, _ := json.Marshal(projectArchiveManifest{
+		FormatVersion: ProjectArchiveFormatVersion,
+		Project:       models.Project{Name: "Half"},
+		Files:         []ImportedFile{{Name: "a.tsv", Path: "/data/a.tsv"}},
+		Annotations:   []models.SampleAnnotation{{Sample: "A1", Condition: "ctrl"}},
+		Jobs:          []string{"jobs/good" + JobBundleExtension, "jobs/broken" + JobBundleExtension},
+	})
+	archivePath := filepath.Join(t.TempDir(), "half"+ProjectArchiveExtension)
+	archive, err := bundles.files.CreateTarXz(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	archive.AddFile("jobs/good"+JobBundleExtension, bundlePath)
+	archive.AddFile("jobs/broken"+JobBundleExtension, broken)
+	archive.AddBytes(projectManifestFile, manifest)
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+
+	if _, err := bundles.ImportProject(archivePath); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("Expected the broken bundle to fail the import, got %v", err)
+	}
+
+	var projectCount, jobCount, fileCount, annotationCount int64
+	db.GetDB().Model(&models.Project{}).Where("name = ?", "Half").Count(&projectCount)
+	db.GetDB().Unscoped().Model(&models.Job{}).Count(&jobCount)
+	db.GetDB().Model(&ImportedFile{}).Count(&fileCount)
+	db.GetDB().Model(&models.SampleAnnotation{}).Count(&annotationCount)
+	if projectCount != 0 || jobCount != 1 || fileCount != 0 || annotationCount != 0 {
+		t.Errorf("Expected the partial import to be removed, got %d projects, %d jobs, %d files and %d annotations",
+			projectCount, jobCount, fileCount, annotationCount)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(outputRoot, "projects")); len(entries) != 0 {
+		t.Errorf("Expected no imported outputs to be left, found %d entries", len(entries))
+	}
+}
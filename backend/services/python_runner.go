@@ -1,11 +1,13 @@
 package services
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
 )
 
 type PythonRunner struct {
@@ -27,55 +29,43 @@ func NewPythonRunner(settings *SettingsService) *PythonRunner {
 	}
 }
 
-func (p *PythonRunner) ExecuteScript(scriptName string, args []string, outputCallback func(line string)) error {
-	var scriptPath string
-	if filepath.IsAbs(scriptName) {
-		scriptPath = scriptName
-	} else {
-		scriptPath = filepath.Join(p.scriptDir, scriptName)
-	}
+// Argv returns the command line ExecuteScript runs for a script.
+func (p *PythonRunner) Argv(scriptName string, args []string) []string {
+	return append([]string{p.pythonPath, p.ScriptPath(scriptName)}, args...)
+}
 
-	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-		return fmt.Errorf("script not found: %s", scriptPath)
+// ScriptPath resolves a bundled script name against the scripts directory.
+// Absolute paths, such as plugin scripts, are returned as they are.
+func (p *PythonRunner) ScriptPath(scriptName string) string {
+	if filepath.IsAbs(scriptName) {
+		return scriptName
 	}
+	return filepath.Join(p.scriptDir, scriptName)
+}
 
-	cmdArgs := append([]string{scriptPath}, args...)
-	cmd := exec.Command(p.pythonPath, cmdArgs...)
-	hideConsoleWindow(cmd)
-
-	stdout, err := cmd.StdoutPipe()
+// Env returns the environment Python jobs run with. The scripts directory is
+// put on PYTHONPATH so plugins can import the helpers bundled there, such as
+// cauldron_progress.
+func (p *PythonRunner) Env() []string {
+	dir, err := filepath.Abs(p.scriptDir)
 	if err != nil {
-		return err
+		return nil
 	}
-
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return err
+	if existing := os.Getenv("PYTHONPATH"); existing != "" {
+		dir += string(os.PathListSeparator) + existing
 	}
+	return []string{"PYTHONPATH=" + dir}
+}
 
-	if err := cmd.Start(); err != nil {
-		return err
+func (p *PythonRunner) ExecuteScript(ctx context.Context, scriptName string, args []string, limits models.ExecutionLimits, outputCallback func(stream string, line string)) error {
+	argv := p.Argv(scriptName, args)
+	if _, err := os.Stat(argv[1]); os.IsNotExist(err) {
+		return fmt.Errorf("script not found: %s", argv[1])
 	}
 
-	go func() {
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			if outputCallback != nil {
-				outputCallback(scanner.Text())
-			}
-		}
-	}()
-
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			if outputCallback != nil {
-				outputCallback("[ERROR] " + scanner.Text())
-			}
-		}
-	}()
-
-	return cmd.Wait()
+	cmd := exec.Command(argv[0], argv[1:]...)
+
+	return runProcess(ctx, cmd, limits, outputCallback)
 }
 
 func (p *PythonRunner) ValidatePythonInstallation() error {
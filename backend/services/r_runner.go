@@ -1,11 +1,13 @@
 package services
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
 )
 
 type RRunner struct {
@@ -29,59 +31,41 @@ func NewRRunner(settings *SettingsService) *RRunner {
 	}
 }
 
-func (r *RRunner) ExecuteScript(scriptName string, args []string, outputCallback func(line string)) error {
-	var scriptPath string
-	if filepath.IsAbs(scriptName) {
-		scriptPath = scriptName
-	} else {
-		scriptPath = filepath.Join(r.scriptDir, scriptName)
-	}
+// Argv returns the command line ExecuteScript runs for a script.
+func (r *RRunner) Argv(scriptName string, args []string) []string {
+	return append([]string{r.rscriptPath, r.ScriptPath(scriptName)}, args...)
+}
 
-	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-		return fmt.Errorf("script not found: %s", scriptPath)
+// ScriptPath resolves a bundled script name against the scripts directory.
+// Absolute paths, such as plugin scripts, are returned as they are.
+func (r *RRunner) ScriptPath(scriptName string) string {
+	if filepath.IsAbs(scriptName) {
+		return scriptName
 	}
+	return filepath.Join(r.scriptDir, scriptName)
+}
 
-	cmdArgs := append([]string{scriptPath}, args...)
-	cmd := exec.Command(r.rscriptPath, cmdArgs...)
-	hideConsoleWindow(cmd)
-
-	if r.rLibPath != "" {
-		cmd.Env = append(os.Environ(), fmt.Sprintf("R_LIBS=%s", r.rLibPath))
+// Env returns the variables R scripts run with on top of the app's own.
+func (r *RRunner) Env() []string {
+	if r.rLibPath == "" {
+		return nil
 	}
+	return []string{fmt.Sprintf("R_LIBS=%s", r.rLibPath)}
+}
 
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return err
+func (r *RRunner) ExecuteScript(ctx context.Context, scriptName string, args []string, limits models.ExecutionLimits, outputCallback func(stream string, line string)) error {
+	argv := r.Argv(scriptName, args)
+	if _, err := os.Stat(argv[1]); os.IsNotExist(err) {
+		return fmt.Errorf("script not found: %s", argv[1])
 	}
 
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return err
-	}
+	cmd := exec.Command(argv[0], argv[1:]...)
 
-	if err := cmd.Start(); err != nil {
-		return err
+	if env := r.Env(); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
 	}
 
-	go func() {
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			if outputCallback != nil {
-				outputCallback(scanner.Text())
-			}
-		}
-	}()
-
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			if outputCallback != nil {
-				outputCallback("[ERROR] " + scanner.Text())
-			}
-		}
-	}()
-
-	return cmd.Wait()
+	return runProcess(ctx, cmd, limits, outputCallback)
 }
 
 func (r *RRunner) ValidateRInstallation() error {
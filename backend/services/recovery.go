@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const orphanPollInterval = 2 * time.Second
+
+// recordProcess stores the PID, start time and console folder of a job's
+// process, so a later session can find the process and follow its output if
+// the app exits while it runs.
+func (j *JobQueueService) recordProcess(job *models.Job, pid int) {
+	startTime, _ := processStartTime(pid)
+
+	j.mu.Lock()
+	job.ProcessID = pid
+	job.ProcessStartTime = startTime
+	consolePath := job.ConsolePath
+	j.mu.Unlock()
+
+	err := j.db.GetDB().Model(&models.Job{}).Where("id = ?", job.ID).
+		Updates(map[string]interface{}{"process_id": pid, "process_start_time": startTime, "console_path": consolePath}).Error
+	if err != nil {
+		log.Printf("[recordProcess] Failed to record process %d of job %s: %v", pid, job.ID, err)
+	}
+}
+
+// GetInterruptedJobs returns the jobs a previous session left running that
+// are waiting for a recovery decision.
+func (j *JobQueueService) GetInterruptedJobs() ([]models.InterruptedJob, error) {
+	var jobs []models.Job
+	if err := j.db.GetDB().Where("status = ?", models.JobStatusInProgress).
+		Where("lease_owner IS NULL OR lease_owner = ''").
+		Order("started_at ASC").Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load interrupted jobs: %w", err)
+	}
+
+	interrupted := []models.InterruptedJob{}
+	for _, job := range jobs {
+		j.mu.RLock()
+		running := len(j.executions[job.ID]) > 0
+		j.mu.RUnlock()
+		if running {
+			continue
+		}
+		alive := processAlive(job.ProcessID, job.ProcessStartTime)
+		interrupted = append(interrupted, models.InterruptedJob{
+			Job:          job,
+			ProcessAlive: alive,
+			CanReattach:  alive && job.ConsolePath != "",
+		})
+	}
+	return interrupted, nil
+}
+
+// RecoverJob applies a recovery action to a job interrupted by a previous
+// session: re-attach to its process if that is still running, restart it, or
+// fail it. Restarting and failing stop the old process first.
+func (j *JobQueueService) RecoverJob(id string, action string) error {
+	job, err := j.GetJob(id)
+	if err != nil {
+		return err
+	}
+
+	j.mu.RLock()
+	waiting := job.Status == models.JobStatusInProgress && len(j.executions[id]) == 0
+	j.mu.RUnlock()
+	if !waiting {
+		return fmt.Errorf("job %s is not waiting for recovery", id)
+	}
+	return j.recoverJob(job, action)
+}
+
+// recoverInterruptedJob handles a job left in progress by a previous session
+// according to the recovery policy. A job whose process cannot be checked on
+// this platform is not restarted automatically, since the old process may
+// still be writing to the same output folder.
+func (j *JobQueueService) recoverInterruptedJob(job *models.Job, policy string) {
+	if policy == models.RecoveryPolicyRestart && !processLivenessSupported && job.ProcessID != 0 {
+		log.Printf("[recoverInterruptedJob] Cannot tell whether process %d of job %s is still running, asking instead of restarting", job.ProcessID, job.ID)
+		policy = models.RecoveryPolicyAsk
+	}
+	if policy == models.RecoveryPolicyAsk {
+		j.mu.Lock()
+		job.ProgressMessage = "Interrupted when the app exited, waiting for a recovery decision"
+		j.mu.Unlock()
+
+		j.db.GetDB().Save(job)
+		j.db.GetDB().Exec("UPDATE jobs SET lease_owner = '', lease_expires_at = NULL WHERE id = ?", job.ID)
+		j.emitJobUpdate(job)
+		log.Printf("[recoverInterruptedJob] Job %s - %s is waiting for a recovery decision", job.ID, job.Name)
+		return
+	}
+
+	action := models.RecoveryActionRestart
+	if policy == models.RecoveryPolicyFail {
+		action = models.RecoveryActionFail
+	}
+	if err := j.recoverJob(job, action); err != nil {
+		log.Printf("[recoverInterruptedJob] Failed to recover job %s: %v", job.ID, err)
+	}
+}
+
+func (j *JobQueueService) recoverJob(job *models.Job, action string) error {
+	alive := processAlive(job.ProcessID, job.ProcessStartTime)
+	switch action {
+	case models.RecoveryActionReattach:
+		if !alive {
+			return fmt.Errorf("the process of job %s is no longer running", job.ID)
+		}
+		if job.ConsolePath == "" {
+			return fmt.Errorf("the output of job %s cannot be followed, restart it instead", job.ID)
+		}
+		j.reattach(job)
+		return nil
+	case models.RecoveryActionRestart, models.RecoveryActionFail:
+		if alive {
+			log.Printf("[recoverJob] Stopping process %d left running by job %s", job.ProcessID, job.ID)
+			killOrphan(job.ProcessID, job.ProcessStartTime)
+		}
+	default:
+		return fmt.Errorf("unknown recovery action: %s", action)
+	}
+
+	j.mu.Lock()
+	job.ProcessID = 0
+	job.ProcessStartTime = 0
+	job.ProgressMessage = ""
+	if action == models.RecoveryActionRestart {
+		job.Status = models.JobStatusPending
+		job.Progress = 0
+		job.Error = ""
+		job.StartedAt = nil
+		job.CompletedAt = nil
+		job.TerminalOutput = []string{}
+	} else {
+		completedTime := time.Now()
+		job.Status = models.JobStatusFailed
+		job.Error = "Interrupted: the app exited while the job was running"
+		job.CompletedAt = &completedTime
+	}
+	j.mu.Unlock()
+
+	if err := j.db.GetDB().Save(job).Error; err != nil {
+		return fmt.Errorf("failed to reset job %s: %w", job.ID, err)
+	}
+	j.db.GetDB().Exec("UPDATE jobs SET lease_owner = '', lease_expires_at = NULL WHERE id = ?", job.ID)
+	j.emitJobUpdate(job)
+
+	if action == models.RecoveryActionRestart {
+		log.Printf("[recoverJob] Re-queued interrupted job: %s - %s", job.ID, job.Name)
+		j.notify()
+	} else {
+		log.Printf("[recoverJob] Failed interrupted job: %s - %s", job.ID, job.Name)
+		j.search.IndexJob(job)
+		j.ReleaseDependents(job.ID)
+	}
+	return nil
+}
+
+// reattach watches a process left running by a previous session until it
+// exits, following its console files into the job log from where the
+// previous session left off. Cancelling the job stops the process.
+func (j *JobQueueService) reattach(job *models.Job) {
+	ctx, release := j.trackExecution(j.ctx, job.ID)
+	pid, startTime := job.ProcessID, job.ProcessStartTime
+	exitCode := watchExitCode(pid, startTime)
+
+	j.mu.Lock()
+	job.ProgressMessage = fmt.Sprintf("Re-attached to process %d", pid)
+	j.mu.Unlock()
+	j.db.GetDB().Save(job)
+	j.emitJobUpdate(job)
+	log.Printf("[reattach] Watching process %d of job %s", pid, job.ID)
+
+	// Lines the previous session already logged are skipped. Lines it had
+	// not flushed yet, and progress lines it hid, are logged again.
+	logged := j.logs.lineCounts(job.ID, job.Attempt)
+	logWriter := j.logs.NewWriter(job.ID, job.Attempt)
+	done := make(chan struct{})
+	var followers sync.WaitGroup
+	for _, stream := range []string{models.LogStreamStdout, models.LogStreamStderr} {
+		skip := logged[stream]
+		writer := newLineWriter(stream, func(stream string, line string) {
+			if skip > 0 {
+				skip--
+				return
+			}
+			logWriter.Write(stream, line)
+		})
+		followers.Add(1)
+		go func() {
+			defer followers.Done()
+			followConsole(consoleFile(job.ConsolePath, stream), writer, done)
+			writer.Flush()
+		}()
+	}
+	stopFollowing := func() {
+		close(done)
+		followers.Wait()
+		logWriter.Close()
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		defer release()
+
+		ticker := time.NewTicker(orphanPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-j.stop:
+				stopFollowing()
+				exitCode()
+				return
+			case <-ctx.Done():
+				killOrphan(pid, startTime)
+				stopFollowing()
+				exitCode()
+				j.finishOrphan(job, models.JobStatusCancelled, errors.New("Job cancelled by user"), nil)
+				return
+			case <-ticker.C:
+				if !processAlive(pid, startTime) {
+					stopFollowing()
+					code, known := exitCode()
+					if recorded, ok := readExitStatus(job.ConsolePath); ok {
+						code, known = recorded, true
+					}
+					stderr := logWriter.StderrTail()
+					status, runErr := orphanOutcome(job, code, known, stderr)
+					j.finishOrphan(job, status, runErr, stderr)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// orphanOutcome decides how a re-attached process that exited on its own
+// ends its job. Without an exit status, a traceback in its stderr still fails
+// the job; otherwise the job completes, so dependent jobs are not skipped on
+// an unknown status alone. The error is errExitStatusUnknown whenever the
+// status is unknown.
+func orphanOutcome(job *models.Job, exitCode int, known bool, stderr []string) (models.JobStatus, error) {
+	switch {
+	case known && exitCode == 0:
+		return models.JobStatusCompleted, nil
+	case known:
+		return models.JobStatusFailed, fmt.Errorf("exit status %d", exitCode)
+	case parseJobError(job, nil, stderr).Language != "":
+		return models.JobStatusFailed, errExitStatusUnknown
+	}
+	return models.JobStatusCompleted, errExitStatusUnknown
+}
+
+// finishOrphan records the end of a re-attached process. A failed job has
+// any traceback in its stderr parsed; a job whose exit status is unknown gets
+// a warning to check its outputs.
+func (j *JobQueueService) finishOrphan(job *models.Job, status models.JobStatus, runErr error, stderr []string) {
+	completedTime := time.Now()
+	j.mu.Lock()
+	job.Status = status
+	job.Error = ""
+	job.CompletedAt = &completedTime
+	job.ProcessID = 0
+	job.ProcessStartTime = 0
+	job.ProgressMessage = ""
+	switch status {
+	case models.JobStatusCancelled:
+		job.Error = runErr.Error()
+	case models.JobStatusFailed:
+		job.ErrorDetails = parseJobError(job, runErr, stderr)
+		job.Error = summarizeJobError(job.ErrorDetails, runErr)
+	case models.JobStatusCompleted:
+		job.Progress = 100
+	}
+	if errors.Is(runErr, errExitStatusUnknown) {
+		job.Warnings = append(job.Warnings,
+			"Finished after the app restarted without recording its exit status: check its outputs and rerun it if they are incomplete")
+	}
+	j.mu.Unlock()
+
+	if outputDir, ok := job.Parameters["outputDir"].(string); ok && outputDir != "" {
+		job.OutputPath = outputDir
+		j.recordArtifacts(job)
+	}
+
+	j.db.GetDB().Save(job)
+	j.emitJobUpdate(job)
+	j.search.IndexJob(job)
+	j.ReleaseDependents(job.ID)
+}
+
+// killOrphan stops a process tree started by a previous session, after
+// checking that the PID still belongs to the same process.
+func killOrphan(pid int, startTime int64) {
+	if !processAlive(pid, startTime) {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		deadline := time.Now().Add(processTerminateGrace)
+		for processAlive(pid, startTime) && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+	terminateProcessTree(pid, processTerminateGrace, done)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+// startOrphan starts a process standing in for one left running by a
+// previous session and stores it on an in-progress job.
+func startOrphan(t *testing.T, db *DatabaseService, id string) *exec.Cmd {
+	t.Helper()
+	return startOrphanCommand(t, db, id, exec.Command("sleep", "30"), "")
+}
+
+// startOrphanCommand starts cmd as an orphan. With a consoleDir its output
+// and exit status go to console files there, as a queued job's do.
+func startOrphanCommand(t *testing.T, db *DatabaseService, id string, cmd *exec.Cmd, consoleDir string) *exec.Cmd {
+	t.Helper()
+	if goruntime.GOOS != "linux" {
+		t.Skip("process start times are read from /proc")
+	}
+
+	proceed := func() {}
+	if consoleDir != "" {
+		files, err := openConsoleSpool(consoleDir, models.LogStreamStdout, models.LogStreamStderr)
+		if err != nil {
+			t.Fatalf("Failed to create console files: %v", err)
+		}
+		cmd.Stdout, cmd.Stderr = files[0], files[1]
+		defer files[0].Close()
+		defer files[1].Close()
+		if proceed, err = recordExitStatus(cmd, filepath.Join(consoleDir, exitStatusFileName)); err != nil {
+			t.Fatalf("Failed to record the exit status: %v", err)
+		}
+	}
+	setProcessGroup(cmd)
+	err := cmd.Start()
+	proceed()
+	if err != nil {
+		t.Fatalf("Failed to start process: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		<-exited
+	})
+
+	startTime, err := processStartTime(cmd.Process.Pid)
+	if err != nil {
+		t.Fatalf("Failed to read process start time: %v", err)
+	}
+
+	started := time.Now().Add(-time.Minute)
+	job := &models.Job{
+		ID:               id,
+		Type:             "test",
+		Name:             "Interrupted " + id,
+		Status:           models.JobStatusInProgress,
+		Command:          "direct",
+		ProjectID:        models.DefaultProjectID,
+		Attempt:          1,
+		CreatedAt:        started,
+		StartedAt:        &started,
+		ProcessID:        cmd.Process.Pid,
+		ProcessStartTime: startTime,
+		ConsolePath:      consoleDir,
+	}
+	if err := db.GetDB().Create(job).Error; err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+	db.GetDB().Exec("UPDATE jobs SET lease_owner = ? WHERE id = ?", "previous-session/0", id)
+	return cmd
+}
+
+func startQueueWithPolicy(t *testing.T, db *DatabaseService, policy string) *JobQueueService {
+	t.Helper()
+	ctx := context.WithValue(context.Background(), "wails-test", true)
+	jobQueue := NewJobQueueService(ctx, db)
+	jobQueue.SetRunners(nil, nil, nil, &SettingsService{config: &models.Config{RecoveryPolicy: policy}})
+	jobQueue.Start()
+	t.Cleanup(func() {
+		jobQueue.Shutdown()
+		db.Close()
+	})
+	return jobQueue
+}
+
+func TestRecoveryPolicyFailStopsOrphan(t *testing.T) {
+	db := createTestDB(t)
+	cmd := startOrphan(t, db, "orphan")
+	startTime, _ := processStartTime(cmd.Process.Pid)
+
+	startQueueWithPolicy(t, db, models.RecoveryPolicyFail)
+
+	job := waitForJobStatus(t, db, "orphan", models.JobStatusFailed)
+	if job.ProcessID != 0 || job.Error == "" {
+		t.Errorf("Expected the job to be failed with its process cleared, got %+v", job)
+	}
+	if processAlive(cmd.Process.Pid, startTime) {
+		t.Error("Expected the orphaned process to be stopped")
+	}
+}
+
+func TestRecoveryPolicyAskWaitsForDecision(t *testing.T) {
+	db := createTestDB(t)
+	dir := t.TempDir()
+	proceed := filepath.Join(dir, "proceed")
+	script := `echo before
+while [ ! -e "$0" ]; do sleep 0.05; done
+echo "Traceback (most recent call last):" >&2
+echo '  File "/plugins/qc/qc.py", line 3, in <module>' >&2
+echo "ModuleNotFoundError: No module named 'pyarrow'" >&2
+exit 1`
+	startOrphanCommand(t, db, "orphan", exec.Command("sh", "-c", script, proceed), filepath.Join(dir, jobConsoleDirName))
+	jobQueue := startQueueWithPolicy(t, db, models.RecoveryPolicyAsk)
+	// The previous session logged the first line before it exited.
+	jobQueue.Logs().AppendLine("orphan", 1, models.LogStreamStdout, "before")
+
+	interrupted, err := jobQueue.GetInterruptedJobs()
+	if err != nil {
+		t.Fatalf("GetInterruptedJobs failed: %v", err)
+	}
+	if len(interrupted) != 1 || interrupted[0].Job.ID != "orphan" || !interrupted[0].ProcessAlive || !interrupted[0].CanReattach {
+		t.Fatalf("Expected the orphan to wait for a decision with its process alive, got %+v", interrupted)
+	}
+
+	if err := jobQueue.RecoverJob("orphan", models.RecoveryActionReattach); err != nil {
+		t.Fatalf("RecoverJob failed: %v", err)
+	}
+	if err := jobQueue.RecoverJob("orphan", models.RecoveryActionRestart); err == nil {
+		t.Error("Expected a re-attached job not to wait for recovery")
+	}
+
+	if err := os.WriteFile(proceed, nil, 0644); err != nil {
+		t.Fatalf("Failed to release the orphan: %v", err)
+	}
+	job := waitForJobStatus(t, db, "orphan", models.JobStatusFailed)
+	if !strings.Contains(job.Error, "ModuleNotFoundError") || !strings.Contains(job.Error, "exit status 1") || len(job.Warnings) != 0 {
+		t.Errorf("Expected the recorded exit status with the parsed traceback, got %q %v", job.Error, job.Warnings)
+	}
+
+	page, err := jobQueue.Logs().GetJobLog("orphan", 0, 100, "")
+	if err != nil {
+		t.Fatalf("GetJobLog failed: %v", err)
+	}
+	var lines []string
+	for _, line := range page.Lines {
+		lines = append(lines, line.Stream+": "+line.Line)
+	}
+	if len(lines) != 4 || lines[0] != "stdout: before" || lines[3] != "stderr: ModuleNotFoundError: No module named 'pyarrow'" {
+		t.Errorf("Expected the output after the restart to be followed once, got %q", lines)
+	}
+}
+
+func TestOrphanOutcome(t *testing.T) {
+	job := &models.Job{Command: "python"}
+	traceback := []string{
+		"Traceback (most recent call last):",
+		`  File "/plugins/qc/qc.py", line 3, in <module>`,
+		"ValueError: bad input",
+	}
+
+	tests := []struct {
+		name     string
+		exitCode int
+		known    bool
+		stderr   []string
+		status   models.JobStatus
+		err      string
+	}{
+		{"succeeded", 0, true, nil, models.JobStatusCompleted, ""},
+		{"failed", 2, true, nil, models.JobStatusFailed, "exit status 2"},
+		{"unknown with traceback", 0, false, traceback, models.JobStatusFailed, errExitStatusUnknown.Error()},
+		{"unknown", 0, false, []string{"a warning"}, models.JobStatusCompleted, errExitStatusUnknown.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := orphanOutcome(job, tt.exitCode, tt.known, tt.stderr)
+			message := ""
+			if err != nil {
+				message = err.Error()
+			}
+			if status != tt.status || message != tt.err {
+				t.Errorf("Expected %s %q, got %s %q", tt.status, tt.err, status, message)
+			}
+		})
+	}
+}
+
+func TestReattachRequiresConsole(t *testing.T) {
+	db := createTestDB(t)
+	startOrphan(t, db, "orphan")
+	jobQueue := startQueueWithPolicy(t, db, models.RecoveryPolicyAsk)
+
+	interrupted, err := jobQueue.GetInterruptedJobs()
+	if err != nil {
+		t.Fatalf("GetInterruptedJobs failed: %v", err)
+	}
+	if len(interrupted) != 1 || !interrupted[0].ProcessAlive || interrupted[0].CanReattach {
+		t.Fatalf("Expected a running orphan without console files not to be re-attachable, got %+v", interrupted)
+	}
+	if err := jobQueue.RecoverJob("orphan", models.RecoveryActionReattach); err == nil {
+		t.Error("Expected re-attaching to a job without console files to fail")
+	}
+}
+
+func TestRecoverJobRejectsDeadProcess(t *testing.T) {
+	db := createTestDB(t)
+	cmd := startOrphan(t, db, "orphan")
+	jobQueue := startQueueWithPolicy(t, db, models.RecoveryPolicyAsk)
+	cmd.Process.Kill()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		interrupted, _ := jobQueue.GetInterruptedJobs()
+		if len(interrupted) == 1 && !interrupted[0].ProcessAlive {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := jobQueue.RecoverJob("orphan", models.RecoveryActionReattach); err == nil {
+		t.Error("Expected re-attaching to an exited process to fail")
+	}
+	if err := jobQueue.RecoverJob("orphan", "resume"); err == nil {
+		t.Error("Expected an unknown action to fail")
+	}
+	if err := jobQueue.RecoverJob("orphan", models.RecoveryActionRestart); err != nil {
+		t.Fatalf("RecoverJob failed: %v", err)
+	}
+
+	// The job has no arguments, so it completes as soon as a worker runs it.
+	job := waitForJobStatus(t, db, "orphan", models.JobStatusCompleted)
+	if job.ProcessID != 0 || len(job.Warnings) != 0 {
+		t.Errorf("Expected the job to be run again from scratch, got %+v", job)
+	}
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+var finishedJobStatuses = []models.JobStatus{
+	models.JobStatusCompleted,
+	models.JobStatusFailed,
+	models.JobStatusCancelled,
+	models.JobStatusSkipped,
+}
+
+// RetentionService applies a retention policy to finished jobs, purging
+// their records and the output directories they leave behind. Cleanup frees
+// space, so it bypasses the trash.
+type RetentionService struct {
+	queue    *JobQueueService
+	settings *SettingsService
+}
+
+func NewRetentionService(queue *JobQueueService, settings *SettingsService) *RetentionService {
+	return &RetentionService{
+		queue:    queue,
+		settings: settings,
+	}
+}
+
+// retentionState is what a plan is computed from: the finished jobs newest
+// first, the size of each output directory and which jobs use it.
+type retentionState struct {
+	jobs      []models.Job
+	protected map[string]bool
+	dirSize   map[string]int64
+	dirJobs   map[string][]string
+	roots     []string
+}
+
+// PreviewCleanup lists the jobs the policy would delete, without deleting
+// anything. Unfinished jobs and jobs that unfinished jobs depend on are
+// never selected.
+func (r *RetentionService) PreviewCleanup(policy models.RetentionPolicy) (*models.CleanupPlan, error) {
+	state, err := r.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := make(map[string][]string)
+	addReason := func(jobID string, reason string) {
+		if !state.protected[jobID] {
+			reasons[jobID] = append(reasons[jobID], reason)
+		}
+	}
+
+	if policy.KeepPerPlugin > 0 {
+		seen := make(map[string]int)
+		for _, job := range state.jobs {
+			plugin := jobPlugin(&job)
+			seen[plugin]++
+			if seen[plugin] > policy.KeepPerPlugin {
+				addReason(job.ID, fmt.Sprintf("Older than the %d most recent %s jobs", policy.KeepPerPlugin, plugin))
+			}
+		}
+	}
+
+	if policy.FailedAfterDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -policy.FailedAfterDays)
+		for _, job := range state.jobs {
+			if job.Status == models.JobStatusFailed && jobFinishedAt(&job).Before(cutoff) {
+				addReason(job.ID, fmt.Sprintf("Failed more than %d days ago", policy.FailedAfterDays))
+			}
+		}
+	}
+
+	plan := &models.CleanupPlan{Policy: policy, Candidates: []models.CleanupCandidate{}}
+	for _, size := range state.dirSize {
+		plan.OutputBytes += size
+	}
+
+	if policy.MaxOutputMB > 0 {
+		limit := int64(policy.MaxOutputMB) << 20
+		remaining := plan.OutputBytes - state.freedBytes(reasons)
+		for i := len(state.jobs) - 1; i >= 0 && remaining > limit; i-- {
+			job := &state.jobs[i]
+			if len(reasons[job.ID]) > 0 || state.protected[job.ID] {
+				continue
+			}
+			addReason(job.ID, fmt.Sprintf("Outputs exceed the %d MB limit", policy.MaxOutputMB))
+			remaining = plan.OutputBytes - state.freedBytes(reasons)
+		}
+	}
+
+	for _, job := range state.jobs {
+		if len(reasons[job.ID]) == 0 {
+			continue
+		}
+		candidate := models.CleanupCandidate{
+			JobID:      job.ID,
+			Name:       job.Name,
+			Plugin:     jobPlugin(&job),
+			Status:     job.Status,
+			CreatedAt:  job.CreatedAt,
+			OutputPath: jobOutputDir(&job),
+			Reasons:    reasons[job.ID],
+		}
+		if candidate.OutputPath != "" && state.removable(candidate.OutputPath, reasons) {
+			candidate.RemovesOutput = true
+			candidate.OutputBytes = state.dirSize[candidate.OutputPath]
+		}
+		plan.FreedBytes += candidate.OutputBytes
+		plan.Candidates = append(plan.Candidates, candidate)
+	}
+	return plan, nil
+}
+
+// RunCleanup deletes the jobs of the current plan whose IDs are listed, or
+// the whole plan when jobIDs is empty, and removes output directories no
+// remaining job uses.
+func (r *RetentionService) RunCleanup(policy models.RetentionPolicy, jobIDs []string) (*models.CleanupSummary, error) {
+	plan, err := r.PreviewCleanup(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(jobIDs))
+	for _, id := range jobIDs {
+		selected[id] = true
+	}
+
+	summary := &models.CleanupSummary{}
+	var outputDirs []string
+	for _, candidate := range plan.Candidates {
+		if len(selected) > 0 && !selected[candidate.JobID] {
+			continue
+		}
+		if err := r.queue.PurgeJob(candidate.JobID); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", candidate.Name, err))
+			continue
+		}
+		summary.DeletedJobs++
+		if candidate.RemovesOutput {
+			outputDirs = append(outputDirs, candidate.OutputPath)
+		}
+	}
+
+	sort.Strings(outputDirs)
+	for i, dir := range outputDirs {
+		if i > 0 && dir == outputDirs[i-1] {
+			continue
+		}
+		if outputDirInUse(r.queue.db, dir, "") {
+			continue
+		}
+		size := directorySize(dir)
+		if err := os.RemoveAll(dir); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", dir, err))
+			continue
+		}
+		summary.DeletedDirectories++
+		summary.FreedBytes += size
+	}
+
+	log.Printf("[RunCleanup] Deleted %d jobs and %d output directories, freed %d bytes",
+		summary.DeletedJobs, summary.DeletedDirectories, summary.FreedBytes)
+	return summary, nil
+}
+
+func (r *RetentionService) loadState() (*retentionState, error) {
+	state := &retentionState{
+		protected: make(map[string]bool),
+		dirSize:   make(map[string]int64),
+		dirJobs:   make(map[string][]string),
+		roots:     outputRoots(r.queue.db, r.settings),
+	}
+
+	if err := r.queue.db.GetDB().Where("status IN ?", finishedJobStatuses).
+		Order("created_at DESC").Find(&state.jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	var unfinished []models.Job
+	if err := r.queue.db.GetDB().Where("status NOT IN ?", finishedJobStatuses).Find(&unfinished).Error; err != nil {
+		return nil, fmt.Errorf("failed to load jobs: %w", err)
+	}
+	for _, job := range unfinished {
+		for _, parentID := range job.DependsOn {
+			state.protected[parentID] = true
+		}
+		if dir := jobOutputDir(&job); dir != "" {
+			state.dirJobs[dir] = append(state.dirJobs[dir], job.ID)
+		}
+		state.protected[job.ID] = true
+	}
+
+	// Trashed jobs whose output was left in place can still be restored, so
+	// their directories are never removed.
+	var trashed []models.Job
+	if err := r.queue.db.GetDB().Unscoped().Where("deleted_at IS NOT NULL AND trash_path = ''").Find(&trashed).Error; err != nil {
+		return nil, fmt.Errorf("failed to load trash: %w", err)
+	}
+	for _, job := range trashed {
+		if dir := jobOutputDir(&job); dir != "" {
+			state.dirJobs[dir] = append(state.dirJobs[dir], job.ID)
+		}
+	}
+
+	for _, job := range state.jobs {
+		dir := jobOutputDir(&job)
+		if dir == "" {
+			continue
+		}
+		if _, ok := state.dirSize[dir]; !ok {
+			state.dirSize[dir] = directorySize(dir)
+		}
+		state.dirJobs[dir] = append(state.dirJobs[dir], job.ID)
+	}
+	return state, nil
+}
+
+// removable reports whether dir can be removed once every job in selected
+// is deleted.
+func (s *retentionState) removable(dir string, selected map[string][]string) bool {
+	if !isManagedOutputDir(dir, s.roots) {
+		return false
+	}
+	for _, jobID := range s.dirJobs[dir] {
+		if len(selected[jobID]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *retentionState) freedBytes(selected map[string][]string) int64 {
+	var freed int64
+	for dir, size := range s.dirSize {
+		if s.removable(dir, selected) {
+			freed += size
+		}
+	}
+	return freed
+}
+
+// outputDirInUse reports whether a job other than exceptJobID still uses
+// dir. Trashed jobs count unless their output was moved to the trash.
+func outputDirInUse(db *DatabaseService, dir string, exceptJobID string) bool {
+	var count int64
+	db.GetDB().Unscoped().Model(&models.Job{}).
+		Where("deleted_at IS NULL OR trash_path = ''").
+		Where("output_path = ? OR json_extract(parameters, '$.outputDir') = ?", dir, dir).
+		Where("id <> ?", exceptJobID).
+		Count(&count)
+	return count > 0
+}
+
+// outputRoots returns the configured output directory and the output
+// directories of all projects. Only directories below them are removed.
+func outputRoots(db *DatabaseService, settings *SettingsService) []string {
+	var roots []string
+	if settings != nil && settings.GetConfig().OutputDirectory != "" {
+		roots = append(roots, settings.GetConfig().OutputDirectory)
+	}
+	var projects []models.Project
+	db.GetDB().Find(&projects)
+	for _, project := range projects {
+		if project.OutputDirectory != "" {
+			roots = append(roots, project.OutputDirectory)
+		}
+	}
+	return roots
+}
+
+// isManagedOutputDir reports whether dir lies strictly inside one of the
+// roots and is not itself a root.
+func isManagedOutputDir(dir string, roots []string) bool {
+	dir = filepath.Clean(dir)
+	managed := false
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if dir == root {
+			return false
+		}
+		rel, err := filepath.Rel(root, dir)
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			managed = true
+		}
+	}
+	return managed
+}
+
+func jobOutputDir(job *models.Job) string {
+	if job.OutputPath != "" {
+		return job.OutputPath
+	}
+	dir, _ := job.Parameters["outputDir"].(string)
+	return dir
+}
+
+func jobPlugin(job *models.Job) string {
+	if pluginID, ok := job.Parameters["pluginId"].(string); ok && pluginID != "" {
+		return pluginID
+	}
+	return job.Type
+}
+
+func jobFinishedAt(job *models.Job) time.Time {
+	if job.CompletedAt != nil {
+		return *job.CompletedAt
+	}
+	return job.CreatedAt
+}
+
+func directorySize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
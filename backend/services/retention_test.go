@@ -0,0 +1,150 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func seedRetentionJobs(t *testing.T, db *DatabaseService, root string, external string) {
+	t.Helper()
+
+	writeOutput := func(dir string) string {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "result.bin"), make([]byte, 1<<20), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	base := time.Now().Add(-30 * 24 * time.Hour)
+	failedAt := time.Now().Add(-10 * 24 * time.Hour)
+	jobs := []models.Job{
+		{ID: "a", Type: "plugin", Parameters: models.JSONMap{"pluginId": "limma"}, OutputPath: writeOutput(filepath.Join(root, "a")), Status: models.JobStatusCompleted},
+		{ID: "b", Type: "plugin", Parameters: models.JSONMap{"pluginId": "limma"}, OutputPath: writeOutput(filepath.Join(root, "b")), Status: models.JobStatusCompleted},
+		{ID: "c", Type: "plugin", Parameters: models.JSONMap{"pluginId": "limma"}, OutputPath: writeOutput(filepath.Join(root, "c")), Status: models.JobStatusCompleted},
+		{ID: "d", Type: "pca", OutputPath: writeOutput(filepath.Join(root, "d")), Status: models.JobStatusFailed, CompletedAt: &failedAt},
+		{ID: "e", Type: "pca", OutputPath: writeOutput(external), Status: models.JobStatusCompleted},
+		{ID: "f", Type: "plugin", Parameters: models.JSONMap{"pluginId": "limma", "outputDir": filepath.Join(root, "c")}, Status: models.JobStatusCompleted},
+		{ID: "g", Type: "plugin", Status: models.JobStatusBlocked, DependsOn: models.StringArray{"b"}},
+	}
+	for i := range jobs {
+		jobs[i].Name = "Job " + jobs[i].ID
+		jobs[i].Command = "python"
+		jobs[i].ProjectID = models.DefaultProjectID
+		jobs[i].CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		if err := db.GetDB().Create(&jobs[i]).Error; err != nil {
+			t.Fatalf("Failed to create job: %v", err)
+		}
+	}
+}
+
+func candidateIDs(plan *models.CleanupPlan) map[string]bool {
+	ids := make(map[string]bool)
+	for _, candidate := range plan.Candidates {
+		ids[candidate.JobID] = candidate.RemovesOutput
+	}
+	return ids
+}
+
+func TestPreviewCleanup(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	root := t.TempDir()
+	seedRetentionJobs(t, db, root, filepath.Join(t.TempDir(), "elsewhere"))
+	retention := NewRetentionService(jobQueue, &SettingsService{config: &models.Config{OutputDirectory: root}})
+
+	plan, err := retention.PreviewCleanup(models.RetentionPolicy{KeepPerPlugin: 2, FailedAfterDays: 7})
+	if err != nil {
+		t.Fatalf("PreviewCleanup failed: %v", err)
+	}
+	got := candidateIDs(plan)
+	if len(got) != 2 || !got["a"] || !got["d"] {
+		t.Errorf("Expected jobs a and d with their outputs, got %+v", plan.Candidates)
+	}
+	if plan.FreedBytes != 2<<20 || plan.OutputBytes != 5<<20 {
+		t.Errorf("Expected 2 MB of 5 MB freed, got %d of %d", plan.FreedBytes, plan.OutputBytes)
+	}
+
+	plan, err = retention.PreviewCleanup(models.RetentionPolicy{KeepPerPlugin: 2, FailedAfterDays: 7, MaxOutputMB: 2})
+	if err != nil {
+		t.Fatalf("PreviewCleanup failed: %v", err)
+	}
+	got = candidateIDs(plan)
+	want := map[string]bool{"a": true, "c": true, "d": true, "e": false, "f": true}
+	if len(got) != len(want) {
+		t.Fatalf("Expected candidates %v, got %+v", want, plan.Candidates)
+	}
+	for id, removesOutput := range want {
+		if value, ok := got[id]; !ok || value != removesOutput {
+			t.Errorf("Expected job %s to be a candidate (removes output: %v), got %+v", id, removesOutput, plan.Candidates)
+		}
+	}
+	if _, ok := got["b"]; ok {
+		t.Error("Expected job b to be kept because a blocked job depends on it")
+	}
+}
+
+func TestRunCleanupRemovesRecordsAndOutputs(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	root := t.TempDir()
+	external := filepath.Join(t.TempDir(), "elsewhere")
+	seedRetentionJobs(t, db, root, external)
+	retention := NewRetentionService(jobQueue, &SettingsService{config: &models.Config{OutputDirectory: root}})
+
+	summary, err := retention.RunCleanup(models.RetentionPolicy{KeepPerPlugin: 2, FailedAfterDays: 7}, nil)
+	if err != nil {
+		t.Fatalf("RunCleanup failed: %v", err)
+	}
+	if summary.DeletedJobs != 2 || summary.DeletedDirectories != 2 || summary.FreedBytes != 2<<20 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	for _, id := range []string{"a", "d"} {
+		if _, err := os.Stat(filepath.Join(root, id)); !os.IsNotExist(err) {
+			t.Errorf("Expected output of job %s to be removed", id)
+		}
+		if _, err := jobQueue.GetJob(id); err == nil {
+			t.Errorf("Expected job %s to be deleted", id)
+		}
+	}
+
+	policy := models.RetentionPolicy{MaxOutputMB: 1}
+	summary, err = retention.RunCleanup(policy, []string{"e", "c"})
+	if err != nil {
+		t.Fatalf("RunCleanup failed: %v", err)
+	}
+	if summary.DeletedJobs != 2 || summary.DeletedDirectories != 0 {
+		t.Errorf("Expected two jobs deleted and no directories, got %+v", summary)
+	}
+	if _, err := os.Stat(external); err != nil {
+		t.Error("Expected an output directory outside the output root to be kept")
+	}
+	if _, err := os.Stat(filepath.Join(root, "c")); err != nil {
+		t.Error("Expected an output directory still used by job f to be kept")
+	}
+}
+
+func TestIsManagedOutputDir(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "outputs")
+	project := filepath.Join(root, "projects", "study")
+	roots := []string{root, project}
+
+	tests := map[string]bool{
+		root:                                false,
+		project:                             false,
+		filepath.Join(root, "job_1"):        true,
+		filepath.Join(project, "job_2"):     true,
+		filepath.Join(root, "..", "other"):  false,
+		filepath.Join(root+"-old", "job_3"): false,
+		filepath.Join(root, "..job", "x"):   true,
+	}
+	for dir, want := range tests {
+		if got := isManagedOutputDir(dir, roots); got != want {
+			t.Errorf("isManagedOutputDir(%s) = %v, want %v", dir, got, want)
+		}
+	}
+}
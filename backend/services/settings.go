@@ -2,10 +2,14 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/noatgnu/cauldron-go/backend/models"
 )
@@ -21,7 +25,12 @@ func NewSettingsService(ctx context.Context, db *DatabaseService) *SettingsServi
 		ctx: ctx,
 		db:  db,
 		config: &models.Config{
-			CurtainBackendURL: "https://celsus.muttsu.xyz",
+			CurtainBackendURL:  "https://celsus.muttsu.xyz",
+			TrashRetentionDays: 30,
+			RecoveryPolicy:     models.RecoveryPolicyRestart,
+			InputSnapshot:      models.InputSnapshotNone,
+
+			DesktopNotifications: true,
 		},
 	}
 
@@ -56,6 +65,42 @@ func (s *SettingsService) Load() error {
 	if val, ok := settings["curtainBackendUrl"]; ok {
 		s.config.CurtainBackendURL = val
 	}
+	if val, ok := settings["pythonWorkers"]; ok {
+		s.config.PythonWorkers, _ = strconv.Atoi(val)
+	}
+	if val, ok := settings["rWorkers"]; ok {
+		s.config.RWorkers, _ = strconv.Atoi(val)
+	}
+	if val, ok := settings["directWorkers"]; ok {
+		s.config.DirectWorkers, _ = strconv.Atoi(val)
+	}
+	if val, ok := settings["retentionKeepPerPlugin"]; ok {
+		s.config.RetentionKeepPerPlugin, _ = strconv.Atoi(val)
+	}
+	if val, ok := settings["retentionFailedDays"]; ok {
+		s.config.RetentionFailedDays, _ = strconv.Atoi(val)
+	}
+	if val, ok := settings["retentionMaxOutputMb"]; ok {
+		s.config.RetentionMaxOutputMB, _ = strconv.Atoi(val)
+	}
+	if val, ok := settings["trashRetentionDays"]; ok {
+		s.config.TrashRetentionDays, _ = strconv.Atoi(val)
+	}
+	if val, ok := settings["recoveryPolicy"]; ok && isRecoveryPolicy(val) {
+		s.config.RecoveryPolicy = val
+	}
+	if val, ok := settings["inputSnapshot"]; ok && isInputSnapshotMode(val) {
+		s.config.InputSnapshot = val
+	}
+	if val, ok := settings["desktopNotifications"]; ok {
+		s.config.DesktopNotifications = val != "false"
+	}
+	if val, ok := settings["webhookUrls"]; ok {
+		s.config.WebhookURLs = splitLines(val)
+	}
+	if val, ok := settings["webhookSecret"]; ok {
+		s.config.WebhookSecret = val
+	}
 
 	return nil
 }
@@ -67,6 +112,18 @@ func (s *SettingsService) Save() error {
 	s.db.SaveSetting("rPath", s.config.RPath)
 	s.db.SaveSetting("rLibPath", s.config.RLibPath)
 	s.db.SaveSetting("curtainBackendUrl", s.config.CurtainBackendURL)
+	s.db.SaveSetting("pythonWorkers", strconv.Itoa(s.config.PythonWorkers))
+	s.db.SaveSetting("rWorkers", strconv.Itoa(s.config.RWorkers))
+	s.db.SaveSetting("directWorkers", strconv.Itoa(s.config.DirectWorkers))
+	s.db.SaveSetting("retentionKeepPerPlugin", strconv.Itoa(s.config.RetentionKeepPerPlugin))
+	s.db.SaveSetting("retentionFailedDays", strconv.Itoa(s.config.RetentionFailedDays))
+	s.db.SaveSetting("retentionMaxOutputMb", strconv.Itoa(s.config.RetentionMaxOutputMB))
+	s.db.SaveSetting("trashRetentionDays", strconv.Itoa(s.config.TrashRetentionDays))
+	s.db.SaveSetting("recoveryPolicy", s.config.RecoveryPolicy)
+	s.db.SaveSetting("inputSnapshot", s.config.InputSnapshot)
+	s.db.SaveSetting("desktopNotifications", strconv.FormatBool(s.config.DesktopNotifications))
+	s.db.SaveSetting("webhookUrls", strings.Join(s.config.WebhookURLs, "\n"))
+	s.db.SaveSetting("webhookSecret", s.config.WebhookSecret)
 	return nil
 }
 
@@ -84,6 +141,30 @@ func (s *SettingsService) Get(key string) interface{} {
 		return s.config.RLibPath
 	case "curtainBackendUrl":
 		return s.config.CurtainBackendURL
+	case "pythonWorkers":
+		return s.config.PythonWorkers
+	case "rWorkers":
+		return s.config.RWorkers
+	case "directWorkers":
+		return s.config.DirectWorkers
+	case "retentionKeepPerPlugin":
+		return s.config.RetentionKeepPerPlugin
+	case "retentionFailedDays":
+		return s.config.RetentionFailedDays
+	case "retentionMaxOutputMb":
+		return s.config.RetentionMaxOutputMB
+	case "trashRetentionDays":
+		return s.config.TrashRetentionDays
+	case "recoveryPolicy":
+		return s.config.RecoveryPolicy
+	case "inputSnapshot":
+		return s.config.InputSnapshot
+	case "desktopNotifications":
+		return s.config.DesktopNotifications
+	case "webhookUrls":
+		return s.config.WebhookURLs
+	case "webhookSecret":
+		return s.config.WebhookSecret
 	}
 	return nil
 }
@@ -103,6 +184,53 @@ func (s *SettingsService) Set(key string, value interface{}) error {
 		s.config.RLibPath = value.(string)
 	case "curtainBackendUrl":
 		s.config.CurtainBackendURL = value.(string)
+	case "pythonWorkers", "rWorkers", "directWorkers":
+		count := toInt(value)
+		if count < 1 || count > maxWorkersPerRuntime {
+			return fmt.Errorf("%s must be between 1 and %d", key, maxWorkersPerRuntime)
+		}
+		switch key {
+		case "pythonWorkers":
+			s.config.PythonWorkers = count
+		case "rWorkers":
+			s.config.RWorkers = count
+		default:
+			s.config.DirectWorkers = count
+		}
+	case "retentionKeepPerPlugin":
+		s.config.RetentionKeepPerPlugin = max(0, toInt(value))
+	case "retentionFailedDays":
+		s.config.RetentionFailedDays = max(0, toInt(value))
+	case "retentionMaxOutputMb":
+		s.config.RetentionMaxOutputMB = max(0, toInt(value))
+	case "trashRetentionDays":
+		s.config.TrashRetentionDays = max(0, toInt(value))
+	case "recoveryPolicy":
+		policy, _ := value.(string)
+		if !isRecoveryPolicy(policy) {
+			return fmt.Errorf("unknown recovery policy: %v", value)
+		}
+		s.config.RecoveryPolicy = policy
+	case "inputSnapshot":
+		mode, _ := value.(string)
+		if !isInputSnapshotMode(mode) {
+			return fmt.Errorf("unknown input snapshot mode: %v", value)
+		}
+		s.config.InputSnapshot = mode
+	case "desktopNotifications":
+		enabled, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("desktopNotifications must be true or false")
+		}
+		s.config.DesktopNotifications = enabled
+	case "webhookUrls":
+		urls, err := toWebhookURLs(value)
+		if err != nil {
+			return err
+		}
+		s.config.WebhookURLs = urls
+	case "webhookSecret":
+		s.config.WebhookSecret, _ = value.(string)
 	}
 	return s.Save()
 }
@@ -111,6 +239,14 @@ func (s *SettingsService) GetConfig() *models.Config {
 	return s.config
 }
 
+func (s *SettingsService) RetentionPolicy() models.RetentionPolicy {
+	return models.RetentionPolicy{
+		KeepPerPlugin:   s.config.RetentionKeepPerPlugin,
+		FailedAfterDays: s.config.RetentionFailedDays,
+		MaxOutputMB:     s.config.RetentionMaxOutputMB,
+	}
+}
+
 func (s *SettingsService) initializeDefaults() {
 	if s.config.ResultStoragePath == "" {
 		userConfigDir, _ := os.UserConfigDir()
@@ -135,6 +271,123 @@ func (s *SettingsService) initializeDefaults() {
 	if s.config.CurtainBackendURL == "" {
 		s.config.CurtainBackendURL = "https://celsus.muttsu.xyz"
 	}
+
+	if s.config.PythonWorkers <= 0 {
+		s.config.PythonWorkers = defaultWorkerCounts[WorkerRuntimePython]
+	}
+	if s.config.RWorkers <= 0 {
+		s.config.RWorkers = defaultWorkerCounts[WorkerRuntimeR]
+	}
+	if s.config.DirectWorkers <= 0 {
+		s.config.DirectWorkers = defaultWorkerCounts[WorkerRuntimeDirect]
+	}
+}
+
+// toInt converts a setting value from the frontend, where numbers arrive as
+// float64, to an int.
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+func isRecoveryPolicy(policy string) bool {
+	switch policy {
+	case models.RecoveryPolicyRestart, models.RecoveryPolicyFail, models.RecoveryPolicyAsk:
+		return true
+	}
+	return false
+}
+
+// RecoveryPolicy returns what to do with jobs interrupted by a previous
+// session, defaulting to restarting them.
+func (s *SettingsService) RecoveryPolicy() string {
+	if s == nil || !isRecoveryPolicy(s.config.RecoveryPolicy) {
+		return models.RecoveryPolicyRestart
+	}
+	return s.config.RecoveryPolicy
+}
+
+func isInputSnapshotMode(mode string) bool {
+	switch mode {
+	case models.InputSnapshotNone, models.InputSnapshotCopy, models.InputSnapshotHardlink:
+		return true
+	}
+	return false
+}
+
+// InputSnapshot returns how job inputs are snapshotted when a job does not
+// choose, defaulting to no snapshot.
+func (s *SettingsService) InputSnapshot() string {
+	if s == nil || !isInputSnapshotMode(s.config.InputSnapshot) {
+		return models.InputSnapshotNone
+	}
+	return s.config.InputSnapshot
+}
+
+// toWebhookURLs accepts webhook URLs as a list or as one URL per line and
+// checks that each is an http or https URL.
+func toWebhookURLs(value interface{}) ([]string, error) {
+	var raw []string
+	switch v := value.(type) {
+	case string:
+		raw = splitLines(v)
+	case []string:
+		raw = v
+	case []interface{}:
+		for _, item := range v {
+			text, _ := item.(string)
+			raw = append(raw, text)
+		}
+	default:
+		return nil, fmt.Errorf("webhookUrls must be a list of URLs")
+	}
+
+	urls := []string{}
+	for _, text := range raw {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		parsed, err := url.Parse(text)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL: %s", text)
+		}
+		urls = append(urls, text)
+	}
+	return urls, nil
+}
+
+func splitLines(text string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// DesktopNotifications reports whether finished jobs raise a desktop
+// notification.
+func (s *SettingsService) DesktopNotifications() bool {
+	return s != nil && s.config.DesktopNotifications
+}
+
+// Webhooks returns the URLs finished jobs are POSTed to and the secret the
+// payloads are signed with.
+func (s *SettingsService) Webhooks() ([]string, string) {
+	if s == nil {
+		return nil, ""
+	}
+	return append([]string(nil), s.config.WebhookURLs...), s.config.WebhookSecret
 }
 
 func (s *SettingsService) DetectPythonPath() (string, error) {
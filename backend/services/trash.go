@@ -0,0 +1,192 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+const trashDirName = ".trash"
+
+// TrashService keeps deleted jobs restorable. The output directory of a
+// trashed job is moved into a .trash folder of its output root instead of
+// being removed, and everything in the trash is purged once it is older
+// than the configured number of days.
+type TrashService struct {
+	queue    *JobQueueService
+	settings *SettingsService
+}
+
+func NewTrashService(queue *JobQueueService, settings *SettingsService) *TrashService {
+	return &TrashService{
+		queue:    queue,
+		settings: settings,
+	}
+}
+
+// TrashJob moves a job to the trash. Its output directory goes with it when
+// the directory lies below an output root and no other job uses it.
+func (t *TrashService) TrashJob(id string) error {
+	job, err := t.queue.GetJob(id)
+	if err != nil {
+		return err
+	}
+	t.queue.mu.RLock()
+	dir := jobOutputDir(job)
+	t.queue.mu.RUnlock()
+
+	if err := t.queue.DeleteJob(id); err != nil {
+		return err
+	}
+
+	roots := outputRoots(t.queue.db, t.settings)
+	if dir == "" || !isManagedOutputDir(dir, roots) || outputDirInUse(t.queue.db, dir, id) {
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	trashPath := filepath.Join(trashRoot(dir, roots), trashDirName, id)
+	if err := os.MkdirAll(filepath.Dir(trashPath), 0755); err != nil {
+		log.Printf("[TrashJob] Failed to create trash folder for job %s: %v", id, err)
+		return nil
+	}
+	os.RemoveAll(trashPath)
+	if err := os.Rename(dir, trashPath); err != nil {
+		log.Printf("[TrashJob] Failed to move output of job %s to the trash, leaving it in place: %v", id, err)
+		return nil
+	}
+	return t.setTrashPath(id, trashPath)
+}
+
+// ListTrash purges expired jobs and returns the rest of the trash, most
+// recently deleted first.
+func (t *TrashService) ListTrash() ([]models.Job, error) {
+	if _, err := t.PurgeExpired(); err != nil {
+		log.Printf("[ListTrash] Failed to purge expired jobs: %v", err)
+	}
+
+	jobs := []models.Job{}
+	if err := t.queue.db.GetDB().Unscoped().Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load trash: %w", err)
+	}
+	return jobs, nil
+}
+
+// RestoreJob takes a job out of the trash. With restoreOutput its output
+// directory is moved back to where it was; otherwise the trashed copy is
+// removed, since nothing would refer to it any more.
+func (t *TrashService) RestoreJob(id string, restoreOutput bool) (*models.Job, error) {
+	job, err := t.trashedJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.TrashPath != "" {
+		if restoreOutput {
+			dir := jobOutputDir(job)
+			if _, err := os.Stat(dir); err == nil {
+				return nil, fmt.Errorf("cannot restore output, %s already exists", dir)
+			}
+			if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+				return nil, fmt.Errorf("failed to restore output: %w", err)
+			}
+			if err := os.Rename(job.TrashPath, dir); err != nil {
+				return nil, fmt.Errorf("failed to restore output: %w", err)
+			}
+		} else if err := os.RemoveAll(job.TrashPath); err != nil {
+			return nil, fmt.Errorf("failed to remove trashed output: %w", err)
+		}
+		if err := t.setTrashPath(id, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.queue.RestoreJob(id)
+}
+
+// PurgeJob permanently deletes a trashed job and its trashed output.
+func (t *TrashService) PurgeJob(id string) error {
+	job, err := t.trashedJob(id)
+	if err != nil {
+		return err
+	}
+	return t.purge(job)
+}
+
+// EmptyTrash permanently deletes every job in the trash.
+func (t *TrashService) EmptyTrash() (int, error) {
+	return t.purgeWhere(func(job *models.Job) bool { return true })
+}
+
+// PurgeExpired permanently deletes the jobs that have been in the trash for
+// longer than the configured number of days. Zero days keeps them forever.
+func (t *TrashService) PurgeExpired() (int, error) {
+	if t.settings == nil || t.settings.GetConfig().TrashRetentionDays <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -t.settings.GetConfig().TrashRetentionDays)
+	return t.purgeWhere(func(job *models.Job) bool { return job.DeletedAt.Time.Before(cutoff) })
+}
+
+func (t *TrashService) purgeWhere(match func(job *models.Job) bool) (int, error) {
+	var jobs []models.Job
+	if err := t.queue.db.GetDB().Unscoped().Where("deleted_at IS NOT NULL").Find(&jobs).Error; err != nil {
+		return 0, fmt.Errorf("failed to load trash: %w", err)
+	}
+
+	purged := 0
+	for i := range jobs {
+		if !match(&jobs[i]) {
+			continue
+		}
+		if err := t.purge(&jobs[i]); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	if purged > 0 {
+		log.Printf("[purgeWhere] Purged %d jobs from the trash", purged)
+	}
+	return purged, nil
+}
+
+func (t *TrashService) purge(job *models.Job) error {
+	if job.TrashPath != "" {
+		if err := os.RemoveAll(job.TrashPath); err != nil {
+			return fmt.Errorf("failed to remove trashed output of job %s: %w", job.ID, err)
+		}
+	}
+	return t.queue.PurgeJob(job.ID)
+}
+
+func (t *TrashService) trashedJob(id string) (*models.Job, error) {
+	var job models.Job
+	if err := t.queue.db.GetDB().Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&job).Error; err != nil {
+		return nil, fmt.Errorf("job not in trash: %s", id)
+	}
+	return &job, nil
+}
+
+func (t *TrashService) setTrashPath(id string, trashPath string) error {
+	return t.queue.db.GetDB().Unscoped().Model(&models.Job{}).Where("id = ?", id).
+		Update("trash_path", trashPath).Error
+}
+
+// trashRoot returns the innermost output root containing dir, so trashed
+// output stays on the same volume and can be moved back cheaply.
+func trashRoot(dir string, roots []string) string {
+	best := ""
+	for _, root := range roots {
+		if isManagedOutputDir(dir, []string{root}) && len(filepath.Clean(root)) > len(best) {
+			best = filepath.Clean(root)
+		}
+	}
+	return best
+}
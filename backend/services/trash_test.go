@@ -0,0 +1,157 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+)
+
+func TestTrashAndRestoreJob(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	root := t.TempDir()
+	seedRetentionJobs(t, db, root, filepath.Join(t.TempDir(), "elsewhere"))
+	trash := NewTrashService(jobQueue, &SettingsService{config: &models.Config{OutputDirectory: root}})
+
+	if err := trash.TrashJob("a"); err != nil {
+		t.Fatalf("TrashJob failed: %v", err)
+	}
+	if _, err := jobQueue.GetJob("a"); err == nil {
+		t.Error("Expected a trashed job to be hidden")
+	}
+	if _, err := os.Stat(filepath.Join(root, "a")); !os.IsNotExist(err) {
+		t.Error("Expected the output to be moved out of place")
+	}
+	if _, err := os.Stat(filepath.Join(root, trashDirName, "a", "result.bin")); err != nil {
+		t.Errorf("Expected the output in the trash folder: %v", err)
+	}
+
+	trashed, err := trash.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash failed: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != "a" || !trashed[0].DeletedAt.Valid {
+		t.Fatalf("Expected job a in the trash, got %+v", trashed)
+	}
+
+	job, err := trash.RestoreJob("a", true)
+	if err != nil {
+		t.Fatalf("RestoreJob failed: %v", err)
+	}
+	if job.TrashPath != "" || job.DeletedAt.Valid {
+		t.Errorf("Expected the restored job to be out of the trash, got %+v", job)
+	}
+	if _, err := os.Stat(filepath.Join(root, "a", "result.bin")); err != nil {
+		t.Errorf("Expected the output to be moved back: %v", err)
+	}
+
+	trash.TrashJob("b")
+	if _, err := trash.RestoreJob("b", false); err != nil {
+		t.Fatalf("RestoreJob failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, trashDirName, "b")); !os.IsNotExist(err) {
+		t.Error("Expected the trashed output to be discarded")
+	}
+	if _, err := jobQueue.GetJob("b"); err != nil {
+		t.Errorf("Expected job b to be restored: %v", err)
+	}
+
+	if err := trash.TrashJob("c"); err != nil {
+		t.Fatalf("TrashJob failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "c")); err != nil {
+		t.Error("Expected an output directory still used by job f to stay in place")
+	}
+	if _, err := trash.RestoreJob("missing", true); err == nil {
+		t.Error("Expected restoring a job that is not in the trash to fail")
+	}
+}
+
+func TestDeleteRunningJobFails(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	db.GetDB().Create(&models.Job{ID: "running", Type: "test", Name: "Running", Command: "python",
+		ProjectID: models.DefaultProjectID, Status: models.JobStatusInProgress})
+
+	if err := jobQueue.DeleteJob("running"); err == nil {
+		t.Error("Expected deleting a running job to fail")
+	}
+}
+
+func TestDeleteLeasedJobFails(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	expires := time.Now().Add(time.Minute)
+	db.GetDB().Create(&models.Job{ID: "leased", Type: "test", Name: "Leased", Command: "python",
+		ProjectID: models.DefaultProjectID, Status: models.JobStatusPending, RunAfter: &expires})
+	db.GetDB().Exec("UPDATE jobs SET lease_owner = ?, lease_expires_at = ? WHERE id = ?", "python-1", expires, "leased")
+
+	if err := jobQueue.DeleteJob("leased"); err == nil {
+		t.Error("Expected deleting a job a worker has leased to fail")
+	}
+
+	job, err := jobQueue.GetJob("leased")
+	if err != nil {
+		t.Fatalf("Expected the leased job to stay: %v", err)
+	}
+	db.GetDB().Delete(&models.Job{}, "id = ?", "leased")
+	job.Status = models.JobStatusInProgress
+	if jobQueue.saveJob(job) {
+		t.Error("Expected saving a trashed job to report it is gone")
+	}
+	var count int64
+	db.GetDB().Model(&models.Job{}).Where("id = ?", "leased").Count(&count)
+	if count != 0 {
+		t.Error("Expected saving a running job not to bring it back from the trash")
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	jobQueue, db := newTestJobQueue(t)
+	root := t.TempDir()
+	seedRetentionJobs(t, db, root, filepath.Join(t.TempDir(), "elsewhere"))
+	settings := &SettingsService{config: &models.Config{OutputDirectory: root, TrashRetentionDays: 7}}
+	trash := NewTrashService(jobQueue, settings)
+
+	for _, id := range []string{"a", "b", "d"} {
+		if err := trash.TrashJob(id); err != nil {
+			t.Fatalf("TrashJob(%s) failed: %v", id, err)
+		}
+	}
+	jobQueue.Logs().AppendLine("d", 1, "stdout", "kept until purged")
+	db.GetDB().Unscoped().Model(&models.Job{}).Where("id = ?", "d").
+		Update("deleted_at", time.Now().AddDate(0, 0, -10))
+
+	purged, err := trash.PurgeExpired()
+	if err != nil {
+		t.Fatalf("PurgeExpired failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected one expired job to be purged, got %d", purged)
+	}
+	var count int64
+	db.GetDB().Unscoped().Model(&models.Job{}).Where("id = ?", "d").Count(&count)
+	if count != 0 {
+		t.Error("Expected the expired job to be deleted permanently")
+	}
+	if _, err := os.Stat(filepath.Join(root, trashDirName, "d")); !os.IsNotExist(err) {
+		t.Error("Expected the trashed output of the expired job to be removed")
+	}
+	if page, err := jobQueue.Logs().GetJobLog("d", 0, 10, ""); err == nil && len(page.Lines) != 0 {
+		t.Errorf("Expected the log of the purged job to be deleted, got %+v", page.Lines)
+	}
+
+	if err := trash.PurgeJob("a"); err != nil {
+		t.Fatalf("PurgeJob failed: %v", err)
+	}
+	if err := trash.PurgeJob("c"); err == nil {
+		t.Error("Expected purging a job that is not in the trash to fail")
+	}
+	purged, err = trash.EmptyTrash()
+	if err != nil || purged != 1 {
+		t.Errorf("Expected EmptyTrash to purge job b, got %d (%v)", purged, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, trashDirName, "b")); !os.IsNotExist(err) {
+		t.Error("Expected the trash folder to be emptied")
+	}
+}
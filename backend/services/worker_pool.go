@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/noatgnu/cauldron-go/backend/models"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	WorkerRuntimePython = "python"
+	WorkerRuntimeR      = "r"
+	WorkerRuntimeDirect = "direct"
+)
+
+var workerRuntimes = []string{WorkerRuntimePython, WorkerRuntimeR, WorkerRuntimeDirect}
+
+var defaultWorkerCounts = map[string]int{
+	WorkerRuntimePython: 2,
+	WorkerRuntimeR:      1,
+	WorkerRuntimeDirect: 1,
+}
+
+const maxWorkersPerRuntime = 32
+
+type workerPool struct {
+	runtime string
+	size    int
+	next    int
+	wake    chan struct{}
+	slots   map[string]*workerSlot
+}
+
+type workerSlot struct {
+	id        string
+	runtime   string
+	stop      chan struct{}
+	retiring  bool
+	jobID     string
+	jobName   string
+	pid       int
+	startedAt *time.Time
+}
+
+func newWorkerPools() map[string]*workerPool {
+	pools := make(map[string]*workerPool)
+	for _, name := range workerRuntimes {
+		pools[name] = &workerPool{
+			runtime: name,
+			wake:    make(chan struct{}, 1),
+			slots:   make(map[string]*workerSlot),
+		}
+	}
+	return pools
+}
+
+func (j *JobQueueService) configuredWorkerCount(runtimeName string) int {
+	if j.settingsServ != nil {
+		if count, ok := j.settingsServ.Get(runtimeName + "Workers").(int); ok && count > 0 {
+			return count
+		}
+	}
+	return defaultWorkerCounts[runtimeName]
+}
+
+// ApplyWorkerSettings resizes every pool to the worker counts in the settings.
+func (j *JobQueueService) ApplyWorkerSettings() {
+	for _, name := range workerRuntimes {
+		if err := j.SetWorkerCount(name, j.configuredWorkerCount(name)); err != nil {
+			log.Printf("[ApplyWorkerSettings] %v", err)
+		}
+	}
+}
+
+// SetWorkerCount grows or shrinks the pool for a runtime. Surplus workers
+// finish their current job before exiting.
+func (j *JobQueueService) SetWorkerCount(runtimeName string, count int) error {
+	if count < 1 || count > maxWorkersPerRuntime {
+		return fmt.Errorf("worker count for %s must be between 1 and %d", runtimeName, maxWorkersPerRuntime)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	pool, ok := j.pools[runtimeName]
+	if !ok {
+		return fmt.Errorf("unknown worker runtime: %s", runtimeName)
+	}
+
+	pool.size = count
+	if !j.started {
+		return nil
+	}
+
+	active := make([]*workerSlot, 0, len(pool.slots))
+	for _, slot := range pool.slots {
+		if !slot.retiring {
+			active = append(active, slot)
+		}
+	}
+
+	for len(active) < count {
+		slot := &workerSlot{
+			id:      fmt.Sprintf("%s/%s/%d", j.instanceID, runtimeName, pool.next),
+			runtime: runtimeName,
+			stop:    make(chan struct{}),
+		}
+		pool.next++
+		pool.slots[slot.id] = slot
+		active = append(active, slot)
+
+		j.wg.Add(1)
+		go j.worker(pool, slot)
+	}
+
+	if len(active) > count {
+		// Retire idle workers first.
+		sort.Slice(active, func(a, b int) bool { return active[a].jobID == "" && active[b].jobID != "" })
+		for _, slot := range active[:len(active)-count] {
+			slot.retiring = true
+			close(slot.stop)
+		}
+	}
+
+	log.Printf("[SetWorkerCount] %s pool now has %d worker(s)", runtimeName, count)
+	return nil
+}
+
+func (j *JobQueueService) worker(pool *workerPool, slot *workerSlot) {
+	defer j.wg.Done()
+	defer func() {
+		j.mu.Lock()
+		delete(pool.slots, slot.id)
+		j.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.stop:
+			return
+		case <-slot.stop:
+			return
+		default:
+		}
+
+		j.mu.RLock()
+		isPaused := j.paused
+		j.mu.RUnlock()
+
+		var job *models.Job
+		if !isPaused {
+			job = j.leaseNextJob(slot.id, pool.runtime)
+		}
+
+		if job == nil {
+			select {
+			case <-j.stop:
+				return
+			case <-slot.stop:
+				return
+			case <-pool.wake:
+			case <-ticker.C:
+			}
+			continue
+		}
+
+		// Another job may be waiting for an idle worker.
+		j.notify()
+		j.runLeased(slot, job)
+	}
+}
+
+// notify wakes an idle worker in every pool to check the queue.
+func (j *JobQueueService) notify() {
+	for _, pool := range j.pools {
+		select {
+		case pool.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// runLeased processes a leased job on a worker, renewing the lease and
+// recording the job and process on the worker until it finishes.
+func (j *JobQueueService) runLeased(slot *workerSlot, job *models.Job) {
+	now := time.Now()
+	j.mu.Lock()
+	slot.jobID = job.ID
+	slot.jobName = job.Name
+	slot.startedAt = &now
+	j.mu.Unlock()
+	j.emitWorkersUpdate()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(jobLeaseRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				j.renewLease(job.ID, slot.id)
+			}
+		}
+	}()
+
+	ctx := withProcessObserver(j.ctx, func(pid int) {
+		j.mu.Lock()
+		slot.pid = pid
+		j.mu.Unlock()
+		j.recordProcess(job, pid)
+		j.emitWorkersUpdate()
+	})
+	j.processJob(ctx, job)
+
+	close(done)
+	j.releaseLease(job.ID, slot.id)
+
+	j.mu.Lock()
+	slot.jobID = ""
+	slot.jobName = ""
+	slot.pid = 0
+	slot.startedAt = nil
+	j.mu.Unlock()
+	j.emitWorkersUpdate()
+}
+
+// GetWorkers describes every worker and the job it is running, if any.
+func (j *JobQueueService) GetWorkers() []models.WorkerInfo {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	workers := []models.WorkerInfo{}
+	for _, name := range workerRuntimes {
+		for _, slot := range j.pools[name].slots {
+			info := models.WorkerInfo{
+				ID:        slot.id,
+				Runtime:   slot.runtime,
+				Busy:      slot.jobID != "",
+				Retiring:  slot.retiring,
+				JobID:     slot.jobID,
+				JobName:   slot.jobName,
+				PID:       slot.pid,
+				StartedAt: slot.startedAt,
+			}
+			if slot.startedAt != nil {
+				info.RunningSeconds = time.Since(*slot.startedAt).Seconds()
+			}
+			workers = append(workers, info)
+		}
+	}
+
+	sort.Slice(workers, func(a, b int) bool { return workers[a].ID < workers[b].ID })
+	return workers
+}
+
+func (j *JobQueueService) runningJobIDs() []string {
+	ids := []string{}
+	for _, pool := range j.pools {
+		for _, slot := range pool.slots {
+			if slot.jobID != "" {
+				ids = append(ids, slot.jobID)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (j *JobQueueService) workerCounts() map[string]int {
+	counts := make(map[string]int)
+	for name, pool := range j.pools {
+		counts[name] = pool.size
+	}
+	return counts
+}
+
+func (j *JobQueueService) emitWorkersUpdate() {
+	if j.ctx.Value("wails-test") == nil {
+		runtime.EventsEmit(j.ctx, "queue:workers", j.GetWorkers())
+	}
+}
+
+type processObserverKey struct{}
+
+// withProcessObserver returns a context that makes runProcess report the PID
+// of the process it starts.
+func withProcessObserver(ctx context.Context, observe func(pid int)) context.Context {
+	return context.WithValue(ctx, processObserverKey{}, observe)
+}
+
+func observeProcess(ctx context.Context, pid int) {
+	if observe, ok := ctx.Value(processObserverKey{}).(func(pid int)); ok {
+		observe(pid)
+	}
+}
@@ -39,6 +39,7 @@ type PluginOutput struct {
 	Type        string `yaml:"type"`
 	Description string `yaml:"description"`
 	Format      string `yaml:"format"`
+	Required    bool   `yaml:"required,omitempty"`
 }
 
 type PluginMetadata struct {
@@ -62,10 +63,33 @@ type Requirements struct {
 	Packages []string `yaml:"packages,omitempty"`
 }
 
+type Limits struct {
+	Timeout       int `yaml:"timeout,omitempty"`
+	MaxMemoryMB   int `yaml:"maxMemoryMB,omitempty"`
+	MaxCPUSeconds int `yaml:"maxCPUSeconds,omitempty"`
+}
+
+type Retry struct {
+	MaxAttempts      int      `yaml:"maxAttempts"`
+	Backoff          int      `yaml:"backoff,omitempty"`
+	BackoffFactor    float64  `yaml:"backoffFactor,omitempty"`
+	MaxBackoff       int      `yaml:"maxBackoff,omitempty"`
+	RetryOnExitCodes []int    `yaml:"retryOnExitCodes,omitempty"`
+	RetryOnPatterns  []string `yaml:"retryOnPatterns,omitempty"`
+}
+
+type ProgressPattern struct {
+	Pattern string `yaml:"pattern"`
+	Hide    bool   `yaml:"hide,omitempty"`
+}
+
 type PluginExecution struct {
 	ArgsMapping  map[string]interface{} `yaml:"argsMapping"`
 	OutputDir    string                 `yaml:"outputDir"`
 	Requirements Requirements           `yaml:"requirements,omitempty"`
+	Limits       *Limits                `yaml:"limits,omitempty"`
+	Retry        *Retry                 `yaml:"retry,omitempty"`
+	Progress     []ProgressPattern      `yaml:"progress,omitempty"`
 }
 
 type ExampleData struct {
@@ -247,12 +271,16 @@ func generateOutputTable(outputs []PluginOutput) string {
 	}
 
 	for _, output := range outputs {
+		description := output.Description
+		if output.Required {
+			description = "**Required.** " + description
+		}
 		line := fmt.Sprintf("| `%s` | `%s` | %s | %s | %s |",
 			output.Name,
 			output.Path,
 			output.Type,
 			output.Format,
-			output.Description)
+			description)
 
 		lines = append(lines, line)
 	}
@@ -328,6 +356,57 @@ func generatePluginDoc(plugin PluginConfig) string {
 		lines = append(lines, "")
 	}
 
+	if limits := plugin.Execution.Limits; limits != nil {
+		lines = append(lines, "## Resource Limits\n")
+		if limits.Timeout > 0 {
+			lines = append(lines, fmt.Sprintf("- **Timeout**: %d seconds", limits.Timeout))
+		}
+		if limits.MaxMemoryMB > 0 {
+			lines = append(lines, fmt.Sprintf("- **Max memory**: %d MB", limits.MaxMemoryMB))
+		}
+		if limits.MaxCPUSeconds > 0 {
+			lines = append(lines, fmt.Sprintf("- **Max CPU time**: %d seconds", limits.MaxCPUSeconds))
+		}
+		lines = append(lines, "")
+	}
+
+	if retry := plugin.Execution.Retry; retry != nil {
+		lines = append(lines, "## Retry Policy\n")
+		lines = append(lines, fmt.Sprintf("- **Max attempts**: %d", retry.MaxAttempts))
+		if retry.Backoff > 0 {
+			lines = append(lines, fmt.Sprintf("- **Initial backoff**: %d seconds", retry.Backoff))
+		}
+		if retry.BackoffFactor > 0 {
+			lines = append(lines, fmt.Sprintf("- **Backoff factor**: %g", retry.BackoffFactor))
+		}
+		if retry.MaxBackoff > 0 {
+			lines = append(lines, fmt.Sprintf("- **Max backoff**: %d seconds", retry.MaxBackoff))
+		}
+		if len(retry.RetryOnExitCodes) > 0 {
+			codes := make([]string, len(retry.RetryOnExitCodes))
+			for i, code := range retry.RetryOnExitCodes {
+				codes[i] = fmt.Sprintf("%d", code)
+			}
+			lines = append(lines, fmt.Sprintf("- **Retry on exit codes**: %s", strings.Join(codes, ", ")))
+		}
+		for _, pattern := range retry.RetryOnPatterns {
+			lines = append(lines, fmt.Sprintf("- **Retry on output matching**: `%s`", pattern))
+		}
+		lines = append(lines, "")
+	}
+
+	if len(plugin.Execution.Progress) > 0 {
+		lines = append(lines, "## Progress Reporting\n")
+		for _, progress := range plugin.Execution.Progress {
+			line := fmt.Sprintf("- `%s`", progress.Pattern)
+			if progress.Hide {
+				line += " (hidden from the log)"
+			}
+			lines = append(lines, line)
+		}
+		lines = append(lines, "")
+	}
+
 	exampleSection := generateExampleSection(plugin.Example)
 	if exampleSection != "" {
 		lines = append(lines, exampleSection)
@@ -3,6 +3,7 @@ import { Home } from './home/home';
 import { Settings } from './pages/settings/settings';
 import { Jobs } from './pages/jobs/jobs';
 import { JobDetail } from './pages/job-detail/job-detail';
+import { Trash } from './pages/trash/trash';
 import { Pca } from './pages/analysis/pca/pca';
 import { Imputation } from './pages/analysis/imputation/imputation';
 import { Normalization } from './pages/analysis/normalization/normalization';
@@ -32,6 +33,7 @@ export const routes: Routes = [
   { path: '', component: Home },
   { path: 'settings', component: Settings },
   { path: 'jobs', component: Jobs },
+  { path: 'trash', component: Trash },
   { path: 'jobs/:id', component: JobDetail },
   { path: 'job/:id', component: JobDetail },
   { path: 'plugins', component: Plugins },
@@ -1,9 +1,12 @@
 import { Component, OnInit, signal } from '@angular/core';
 import { RouterOutlet, Router } from '@angular/router';
 import { MatSidenavModule } from '@angular/material/sidenav';
+import { MatDialog } from '@angular/material/dialog';
 import { Toolbar } from './layout/toolbar/toolbar';
 import { Sidenav } from './layout/sidenav/sidenav';
 import { Breadcrumbs } from './layout/breadcrumbs/breadcrumbs';
+import { RecoveryModal } from './components/recovery-modal/recovery-modal';
+import { Wails } from './core/services/wails';
 
 @Component({
   selector: 'app-root',
@@ -15,10 +18,15 @@ export class App implements OnInit {
   protected readonly title = signal('cauldron-ui');
   protected readonly sidenavOpened = signal(true);
 
-  constructor(private router: Router) {}
+  constructor(
+    private router: Router,
+    private dialog: MatDialog,
+    private wails: Wails
+  ) {}
 
   ngOnInit(): void {
     this.setupMenuEventListeners();
+    this.checkInterruptedJobs();
   }
 
   toggleSidenav(): void {
@@ -28,6 +36,21 @@ export class App implements OnInit {
   closeSidenav(): void {
   }
 
+  // Jobs left running by the previous session wait for a decision when the
+  // recovery policy is set to ask.
+  private async checkInterruptedJobs(): Promise<void> {
+    if (!this.wails.isWails) return;
+
+    try {
+      const interrupted = await this.wails.getInterruptedJobs();
+      if (interrupted.length > 0) {
+        this.dialog.open(RecoveryModal, { width: '800px', data: interrupted });
+      }
+    } catch (error) {
+      console.error('Failed to load interrupted jobs:', error);
+    }
+  }
+
   private setupMenuEventListeners(): void {
     if (!window.runtime) return;
 
@@ -0,0 +1,28 @@
+import { ComponentFixture, TestBed } from '@angular/core/testing';
+import { MAT_DIALOG_DATA, MatDialogRef } from '@angular/material/dialog';
+
+import { BundleImportModal } from './bundle-import-modal';
+
+describe('BundleImportModal', () => {
+  let component: BundleImportModal;
+  let fixture: ComponentFixture<BundleImportModal>;
+
+  beforeEach(async () => {
+    await TestBed.configureTestingModule({
+      imports: [BundleImportModal],
+      providers: [
+        { provide: MatDialogRef, useValue: { close: () => {} } },
+        { provide: MAT_DIALOG_DATA, useValue: { jobId: 'job', originalJobId: 'job', outputPath: '', differences: [], canRerun: true } }
+      ]
+    })
+    .compileComponents();
+
+    fixture = TestBed.createComponent(BundleImportModal);
+    component = fixture.componentInstance;
+    await fixture.whenStable();
+  });
+
+  it('should create', () => {
+    expect(component).toBeTruthy();
+  });
+});
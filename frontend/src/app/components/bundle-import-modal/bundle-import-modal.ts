@@ -0,0 +1,40 @@
+import {Component, Inject} from '@angular/core';
+import {MAT_DIALOG_DATA, MatDialogModule, MatDialogRef} from "@angular/material/dialog";
+import {MatButtonModule} from "@angular/material/button";
+import {MatIconModule} from "@angular/material/icon";
+import {BundleDifference, JobBundleImport} from '../../core/services/wails';
+
+export type BundleImportAction = 'close' | 'view' | 'rerun';
+
+@Component({
+  selector: 'app-bundle-import-modal',
+  imports: [
+    MatDialogModule,
+    MatButtonModule,
+    MatIconModule
+  ],
+  templateUrl: './bundle-import-modal.html',
+  styleUrl: './bundle-import-modal.scss',
+})
+export class BundleImportModal {
+  constructor(
+    public dialogRef: MatDialogRef<BundleImportModal, BundleImportAction>,
+    @Inject(MAT_DIALOG_DATA) public data: JobBundleImport
+  ) {}
+
+  describe(difference: BundleDifference): string {
+    if (!difference.local) {
+      return difference.kind === 'package' && difference.recorded
+        ? `not installed (bundle: ${difference.recorded})`
+        : 'missing on this machine';
+    }
+    if (difference.kind === 'package' || difference.kind === 'environment' || difference.name.endsWith(' version')) {
+      return `${difference.local} (bundle: ${difference.recorded})`;
+    }
+    return 'contents differ';
+  }
+
+  close(action: BundleImportAction = 'close') {
+    this.dialogRef.close(action);
+  }
+}
@@ -0,0 +1,28 @@
+import { ComponentFixture, TestBed } from '@angular/core/testing';
+import { MAT_DIALOG_DATA, MatDialogRef } from '@angular/material/dialog';
+
+import { CleanupPreviewModal } from './cleanup-preview-modal';
+
+describe('CleanupPreviewModal', () => {
+  let component: CleanupPreviewModal;
+  let fixture: ComponentFixture<CleanupPreviewModal>;
+
+  beforeEach(async () => {
+    await TestBed.configureTestingModule({
+      imports: [CleanupPreviewModal],
+      providers: [
+        { provide: MatDialogRef, useValue: { close: () => {} } },
+        { provide: MAT_DIALOG_DATA, useValue: { policy: {}, candidates: [], outputBytes: 0, freedBytes: 0 } }
+      ]
+    })
+    .compileComponents();
+
+    fixture = TestBed.createComponent(CleanupPreviewModal);
+    component = fixture.componentInstance;
+    await fixture.whenStable();
+  });
+
+  it('should create', () => {
+    expect(component).toBeTruthy();
+  });
+});
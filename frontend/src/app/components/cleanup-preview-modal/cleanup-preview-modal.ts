@@ -0,0 +1,65 @@
+import {Component, Inject} from '@angular/core';
+import {DatePipe} from '@angular/common';
+import {MAT_DIALOG_DATA, MatDialogModule, MatDialogRef} from "@angular/material/dialog";
+import {MatButtonModule} from "@angular/material/button";
+import {MatCheckboxModule} from "@angular/material/checkbox";
+import {MatIconModule} from "@angular/material/icon";
+import {CleanupCandidate, CleanupPlan} from '../../core/services/wails';
+
+@Component({
+  selector: 'app-cleanup-preview-modal',
+  imports: [
+    DatePipe,
+    MatDialogModule,
+    MatButtonModule,
+    MatCheckboxModule,
+    MatIconModule
+  ],
+  templateUrl: './cleanup-preview-modal.html',
+  styleUrl: './cleanup-preview-modal.scss',
+})
+export class CleanupPreviewModal {
+  protected selected = new Set<string>();
+
+  constructor(
+    public dialogRef: MatDialogRef<CleanupPreviewModal, string[]>,
+    @Inject(MAT_DIALOG_DATA) public data: CleanupPlan
+  ) {
+    for (const candidate of data.candidates) {
+      this.selected.add(candidate.jobId);
+    }
+  }
+
+  toggle(candidate: CleanupCandidate, checked: boolean) {
+    if (checked) {
+      this.selected.add(candidate.jobId);
+    } else {
+      this.selected.delete(candidate.jobId);
+    }
+  }
+
+  selectedBytes(): number {
+    return this.data.candidates
+      .filter(candidate => this.selected.has(candidate.jobId))
+      .reduce((total, candidate) => total + candidate.outputBytes, 0);
+  }
+
+  formatSize(bytes: number): string {
+    const units = ['B', 'KB', 'MB', 'GB', 'TB'];
+    let size = bytes;
+    let unit = 0;
+    while (size >= 1024 && unit < units.length - 1) {
+      size /= 1024;
+      unit++;
+    }
+    return `${unit === 0 ? size : size.toFixed(1)} ${units[unit]}`;
+  }
+
+  close() {
+    this.dialogRef.close();
+  }
+
+  confirm() {
+    this.dialogRef.close([...this.selected]);
+  }
+}
@@ -0,0 +1,28 @@
+import { ComponentFixture, TestBed } from '@angular/core/testing';
+import { MAT_DIALOG_DATA, MatDialogRef } from '@angular/material/dialog';
+
+import { JobCompareModal } from './job-compare-modal';
+
+describe('JobCompareModal', () => {
+  let component: JobCompareModal;
+  let fixture: ComponentFixture<JobCompareModal>;
+
+  beforeEach(async () => {
+    await TestBed.configureTestingModule({
+      imports: [JobCompareModal],
+      providers: [
+        { provide: MatDialogRef, useValue: { close: () => {} } },
+        { provide: MAT_DIALOG_DATA, useValue: { ids: [] } }
+      ]
+    })
+    .compileComponents();
+
+    fixture = TestBed.createComponent(JobCompareModal);
+    component = fixture.componentInstance;
+    await fixture.whenStable();
+  });
+
+  it('should create', () => {
+    expect(component).toBeTruthy();
+  });
+});
@@ -0,0 +1,69 @@
+import {Component, Inject, OnInit, signal} from '@angular/core';
+import {CommonModule} from '@angular/common';
+import {MAT_DIALOG_DATA, MatDialogModule, MatDialogRef} from "@angular/material/dialog";
+import {MatButtonModule} from "@angular/material/button";
+import {MatIconModule} from "@angular/material/icon";
+import {MatSlideToggleModule} from "@angular/material/slide-toggle";
+import {MatProgressSpinnerModule} from "@angular/material/progress-spinner";
+import {FormsModule} from '@angular/forms';
+import {Wails, JobComparison, ComparisonField} from '../../core/services/wails';
+
+export interface JobCompareData {
+  ids: string[];
+}
+
+@Component({
+  selector: 'app-job-compare-modal',
+  imports: [
+    CommonModule,
+    FormsModule,
+    MatDialogModule,
+    MatButtonModule,
+    MatIconModule,
+    MatSlideToggleModule,
+    MatProgressSpinnerModule
+  ],
+  templateUrl: './job-compare-modal.html',
+  styleUrl: './job-compare-modal.scss',
+})
+export class JobCompareModal implements OnInit {
+  comparison = signal<JobComparison | null>(null);
+  loading = signal(true);
+  error = signal('');
+  onlyDifferences = true;
+
+  constructor(
+    public dialogRef: MatDialogRef<JobCompareModal>,
+    @Inject(MAT_DIALOG_DATA) public data: JobCompareData,
+    private wails: Wails
+  ) {}
+
+  async ngOnInit() {
+    try {
+      this.comparison.set(await this.wails.compareJobs(this.data.ids));
+    } catch (err) {
+      this.error.set(`${err}`);
+    } finally {
+      this.loading.set(false);
+    }
+  }
+
+  fields(): ComparisonField[] {
+    const fields = this.comparison()?.fields || [];
+    return this.onlyDifferences ? fields.filter(field => field.differs) : fields;
+  }
+
+  jobName(id: string): string {
+    return this.comparison()?.jobs.find(job => job.id === id)?.name || id;
+  }
+
+  featureColumns(differences: Record<string, number>): string {
+    return Object.entries(differences)
+      .map(([column, diff]) => `${column} ${diff > 0 ? '+' : ''}${diff.toPrecision(3)}`)
+      .join(', ');
+  }
+
+  close() {
+    this.dialogRef.close();
+  }
+}
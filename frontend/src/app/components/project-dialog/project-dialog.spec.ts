@@ -0,0 +1,27 @@
+import { ComponentFixture, TestBed } from '@angular/core/testing';
+import { MatDialogRef } from '@angular/material/dialog';
+
+import { ProjectDialog } from './project-dialog';
+
+describe('ProjectDialog', () => {
+  let component: ProjectDialog;
+  let fixture: ComponentFixture<ProjectDialog>;
+
+  beforeEach(async () => {
+    await TestBed.configureTestingModule({
+      imports: [ProjectDialog],
+      providers: [
+        { provide: MatDialogRef, useValue: { close: () => {} } }
+      ]
+    })
+    .compileComponents();
+
+    fixture = TestBed.createComponent(ProjectDialog);
+    component = fixture.componentInstance;
+    await fixture.whenStable();
+  });
+
+  it('should create', () => {
+    expect(component).toBeTruthy();
+  });
+});
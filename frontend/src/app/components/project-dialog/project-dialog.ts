@@ -0,0 +1,60 @@
+import {Component} from '@angular/core';
+import {FormsModule} from '@angular/forms';
+import {MatDialogModule, MatDialogRef} from "@angular/material/dialog";
+import {MatButtonModule} from "@angular/material/button";
+import {MatFormFieldModule} from '@angular/material/form-field';
+import {MatInputModule} from '@angular/material/input';
+import {MatIconModule} from '@angular/material/icon';
+import {Wails} from '../../core/services/wails';
+
+export interface ProjectDialogResult {
+  name: string;
+  description: string;
+  outputDirectory: string;
+}
+
+@Component({
+  selector: 'app-project-dialog',
+  imports: [
+    FormsModule,
+    MatDialogModule,
+    MatButtonModule,
+    MatFormFieldModule,
+    MatInputModule,
+    MatIconModule
+  ],
+  templateUrl: './project-dialog.html',
+  styleUrl: './project-dialog.scss',
+})
+export class ProjectDialog {
+  name = '';
+  description = '';
+  outputDirectory = '';
+
+  constructor(
+    public dialogRef: MatDialogRef<ProjectDialog, ProjectDialogResult>,
+    private wails: Wails
+  ) {}
+
+  async browseOutputDirectory() {
+    const directory = await this.wails.openDirectoryDialog('Select Project Output Directory');
+    if (directory) {
+      this.outputDirectory = directory;
+    }
+  }
+
+  close() {
+    this.dialogRef.close();
+  }
+
+  create() {
+    if (!this.name.trim()) {
+      return;
+    }
+    this.dialogRef.close({
+      name: this.name.trim(),
+      description: this.description.trim(),
+      outputDirectory: this.outputDirectory
+    });
+  }
+}
@@ -0,0 +1,28 @@
+import { ComponentFixture, TestBed } from '@angular/core/testing';
+import { MAT_DIALOG_DATA, MatDialogRef } from '@angular/material/dialog';
+
+import { RecoveryModal } from './recovery-modal';
+
+describe('RecoveryModal', () => {
+  let component: RecoveryModal;
+  let fixture: ComponentFixture<RecoveryModal>;
+
+  beforeEach(async () => {
+    await TestBed.configureTestingModule({
+      imports: [RecoveryModal],
+      providers: [
+        { provide: MatDialogRef, useValue: { close: () => {} } },
+        { provide: MAT_DIALOG_DATA, useValue: [] }
+      ]
+    })
+    .compileComponents();
+
+    fixture = TestBed.createComponent(RecoveryModal);
+    component = fixture.componentInstance;
+    await fixture.whenStable();
+  });
+
+  it('should create', () => {
+    expect(component).toBeTruthy();
+  });
+});
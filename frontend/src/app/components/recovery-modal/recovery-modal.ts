@@ -0,0 +1,57 @@
+import {Component, Inject, signal} from '@angular/core';
+import {DatePipe} from '@angular/common';
+import {MAT_DIALOG_DATA, MatDialogModule, MatDialogRef} from "@angular/material/dialog";
+import {MatButtonModule} from "@angular/material/button";
+import {MatIconModule} from "@angular/material/icon";
+import {InterruptedJob, RecoveryAction, Wails} from '../../core/services/wails';
+import {NotificationService} from '../../core/services/notification.service';
+
+@Component({
+  selector: 'app-recovery-modal',
+  imports: [
+    DatePipe,
+    MatDialogModule,
+    MatButtonModule,
+    MatIconModule
+  ],
+  templateUrl: './recovery-modal.html',
+  styleUrl: './recovery-modal.scss',
+})
+export class RecoveryModal {
+  protected jobs = signal<InterruptedJob[]>([]);
+  protected busy = signal<string | null>(null);
+
+  constructor(
+    public dialogRef: MatDialogRef<RecoveryModal>,
+    @Inject(MAT_DIALOG_DATA) public data: InterruptedJob[],
+    private wails: Wails,
+    private notificationService: NotificationService
+  ) {
+    this.jobs.set(data);
+  }
+
+  async recover(interrupted: InterruptedJob, action: RecoveryAction) {
+    this.busy.set(interrupted.job.id);
+    try {
+      await this.wails.recoverJob(interrupted.job.id, action);
+      this.jobs.update(jobs => jobs.filter(j => j.job.id !== interrupted.job.id));
+      if (this.jobs().length === 0) {
+        this.dialogRef.close();
+      }
+    } catch (error) {
+      this.notificationService.showError(`Failed to recover ${interrupted.job.name}: ${error}`);
+    } finally {
+      this.busy.set(null);
+    }
+  }
+
+  async recoverAll(action: RecoveryAction) {
+    for (const interrupted of [...this.jobs()]) {
+      await this.recover(interrupted, action);
+    }
+  }
+
+  close() {
+    this.dialogRef.close();
+  }
+}
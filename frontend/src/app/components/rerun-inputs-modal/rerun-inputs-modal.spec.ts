@@ -0,0 +1,28 @@
+import { ComponentFixture, TestBed } from '@angular/core/testing';
+import { MAT_DIALOG_DATA, MatDialogRef } from '@angular/material/dialog';
+
+import { RerunInputsModal } from './rerun-inputs-modal';
+
+describe('RerunInputsModal', () => {
+  let component: RerunInputsModal;
+  let fixture: ComponentFixture<RerunInputsModal>;
+
+  beforeEach(async () => {
+    await TestBed.configureTestingModule({
+      imports: [RerunInputsModal],
+      providers: [
+        { provide: MatDialogRef, useValue: { close: () => {} } },
+        { provide: MAT_DIALOG_DATA, useValue: { jobName: 'Job', checks: [] } }
+      ]
+    })
+    .compileComponents();
+
+    fixture = TestBed.createComponent(RerunInputsModal);
+    component = fixture.componentInstance;
+    await fixture.whenStable();
+  });
+
+  it('should create', () => {
+    expect(component).toBeTruthy();
+  });
+});
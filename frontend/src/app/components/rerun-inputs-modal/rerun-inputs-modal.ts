@@ -0,0 +1,46 @@
+import {Component, Inject} from '@angular/core';
+import {MAT_DIALOG_DATA, MatDialogModule, MatDialogRef} from "@angular/material/dialog";
+import {MatButtonModule} from "@angular/material/button";
+import {MatIconModule} from "@angular/material/icon";
+import {JobInputCheck} from '../../core/services/wails';
+
+export interface RerunInputsData {
+  jobName: string;
+  checks: JobInputCheck[];
+}
+
+export type RerunInputsChoice = 'snapshot' | 'current';
+
+@Component({
+  selector: 'app-rerun-inputs-modal',
+  imports: [
+    MatDialogModule,
+    MatButtonModule,
+    MatIconModule
+  ],
+  templateUrl: './rerun-inputs-modal.html',
+  styleUrl: './rerun-inputs-modal.scss',
+})
+export class RerunInputsModal {
+  protected readonly snapshotAvailable: boolean;
+
+  constructor(
+    public dialogRef: MatDialogRef<RerunInputsModal, RerunInputsChoice>,
+    @Inject(MAT_DIALOG_DATA) public data: RerunInputsData
+  ) {
+    this.snapshotAvailable = data.checks.length > 0 &&
+      data.checks.every(check => check.snapshotStatus === 'unchanged');
+  }
+
+  changed(): JobInputCheck[] {
+    return this.data.checks.filter(check => check.status !== 'unchanged');
+  }
+
+  close() {
+    this.dialogRef.close();
+  }
+
+  choose(choice: RerunInputsChoice) {
+    this.dialogRef.close(choice);
+  }
+}
@@ -110,9 +110,19 @@ export class AnnotationService {
     const content = [headers.join('\t'), ...rows].join('\n');
 
     await this.wails.writeJobOutputFile(jobId, filename, content);
+    await this.wails.saveSampleAnnotations(annotations.map(a => ({
+      sample: a.sample, condition: a.condition, batch: a.batch, color: a.color
+    })));
     await this.wails.logToFile(`[AnnotationService] Saved ${annotations.length} annotations to ${filename} for job ${jobId}`);
   }
 
+  // Returns the annotations the active project has for the given samples.
+  async loadProjectAnnotations(samples: string[]): Promise<Annotation[]> {
+    const wanted = new Set(samples);
+    const annotations = await this.wails.getSampleAnnotations();
+    return annotations.filter(a => wanted.has(a.sample));
+  }
+
   getCondition(sampleName: string, annotations: Annotation[]): string {
     const annotation = annotations.find(a => a.sample === sampleName);
     return annotation ? annotation.condition : 'Unknown';
@@ -14,6 +14,38 @@ declare global {
 export type Config = models.Config;
 export type Job = models.Job;
 export type JobRequest = models.JobRequest;
+export type JobQuery = models.JobQuery;
+export type JobQueryPage = models.JobQueryPage;
+export type JobSearchResult = models.JobSearchResult;
+export type JobInput = models.JobInput;
+export type JobInputCheck = models.JobInputCheck;
+export type JobBatch = models.JobBatch;
+export type BatchExecutionRequest = models.BatchExecutionRequest;
+export type BatchRun = models.BatchRun;
+export type BatchSummary = models.BatchSummary;
+export type BatchMode = 'cartesian' | 'zip';
+export type JobComparison = models.JobComparison;
+export type ComparisonField = models.ComparisonField;
+export type TableComparison = models.TableComparison;
+export type ProcessMetrics = models.ProcessMetrics;
+export type PluginRuntimeHistory = models.PluginRuntimeHistory;
+export type InterruptedJob = models.InterruptedJob;
+export type JobError = models.JobError;
+export type ErrorFrame = models.ErrorFrame;
+export type RecoveryAction = 'reattach' | 'restart' | 'fail';
+export type CleanupPlan = models.CleanupPlan;
+export type CleanupCandidate = models.CleanupCandidate;
+export type CleanupSummary = models.CleanupSummary;
+export type JobLogLine = models.JobLogLine;
+export type JobLogPage = models.JobLogPage;
+export type JobArtifact = models.JobArtifact;
+export type JobBundleImport = models.JobBundleImport;
+export type BundleDifference = models.BundleDifference;
+export type Project = models.Project;
+export type ProjectSummary = models.ProjectSummary;
+export type ProjectImport = models.ProjectImport;
+export type SampleAnnotation = models.SampleAnnotation;
+export type LogStream = 'all' | 'stdout' | 'stderr';
 export type PythonEnvironment = services.PythonEnvironment;
 export type REnvironment = services.REnvironment;
 export type DataFilePreview = services.DataFilePreview;
@@ -61,6 +93,9 @@ export class Wails {
   private queueStatusSubject = new BehaviorSubject<any | null>(null);
   queueStatus$: Observable<any | null> = this.queueStatusSubject.asObservable();
 
+  private projectChangedSubject = new BehaviorSubject<Project | null>(null);
+  projectChanged$: Observable<Project | null> = this.projectChangedSubject.asObservable();
+
   constructor() {
     this.setupEventListeners();
   }
@@ -94,6 +129,10 @@ export class Wails {
       EventsOn('queue:status', (data: any) => {
         this.queueStatusSubject.next(data);
       });
+
+      EventsOn('project:changed', (data: Project) => {
+        this.projectChangedSubject.next(data);
+      });
     } catch (error) {
       console.error('Failed to setup event listeners:', error);
     }
@@ -104,6 +143,11 @@ export class Wails {
     return WailsApp.GetSettings();
   }
 
+  async testWebhook(url: string): Promise<void> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.TestWebhook(url);
+  }
+
   async setSetting(key: string, value: any): Promise<void> {
     if (!this.isWails) throw new Error('Wails not available');
     return WailsApp.SetSetting(key, value);
@@ -171,6 +215,76 @@ export class Wails {
     return WailsApp.GetJob(id);
   }
 
+  async getJobLog(id: string, offset: number, limit: number, stream: LogStream = 'all'): Promise<JobLogPage> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.GetJobLog(id, offset, limit, stream);
+  }
+
+  async getJobArtifacts(id: string): Promise<JobArtifact[]> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.GetJobArtifacts(id);
+  }
+
+  async exportJobBundle(id: string, destPath: string): Promise<string> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.ExportJobBundle(id, destPath);
+  }
+
+  async listProjects(): Promise<ProjectSummary[]> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.ListProjects();
+  }
+
+  async getActiveProject(): Promise<Project> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.GetActiveProject();
+  }
+
+  async createProject(name: string, description: string = '', outputDirectory: string = ''): Promise<Project> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.CreateProject(name, description, outputDirectory);
+  }
+
+  async setActiveProject(id: string): Promise<void> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.SetActiveProject(id);
+  }
+
+  async deleteProject(id: string): Promise<void> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.DeleteProject(id);
+  }
+
+  async exportProject(id: string, destPath: string): Promise<string> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.ExportProject(id, destPath);
+  }
+
+  async importProject(archivePath: string): Promise<ProjectImport> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.ImportProject(archivePath);
+  }
+
+  async getSampleAnnotations(): Promise<SampleAnnotation[]> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.GetSampleAnnotations();
+  }
+
+  async saveSampleAnnotations(annotations: SampleAnnotation[]): Promise<void> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.SaveSampleAnnotations(annotations);
+  }
+
+  async deleteSampleAnnotations(samples: string[]): Promise<void> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.DeleteSampleAnnotations(samples);
+  }
+
+  async importJobBundle(bundlePath: string): Promise<JobBundleImport> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.ImportJobBundle(bundlePath);
+  }
+
   async getAllJobs(): Promise<Job[]> {
     console.log('[Wails Service] getAllJobs() called, isWails:', this.isWails);
     if (!this.isWails) {
@@ -189,19 +303,109 @@ export class Wails {
     }
   }
 
+  async queryJobs(query: Partial<JobQuery>): Promise<JobQueryPage> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.QueryJobs(models.JobQuery.createFrom(query));
+  }
+
+  async searchJobText(text: string, allProjects = false, limit = 50): Promise<JobSearchResult[]> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.SearchJobText(text, allProjects, limit);
+  }
+
+  async setJobTags(jobId: string, tags: string[]): Promise<void> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.SetJobTags(jobId, tags);
+  }
+
+  async getPluginRuntimeHistory(pluginId: string, limit: number = 50): Promise<PluginRuntimeHistory> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.GetPluginRuntimeHistory(pluginId, limit);
+  }
+
+  async previewCleanup(): Promise<CleanupPlan> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.PreviewCleanup();
+  }
+
+  async runCleanup(jobIds: string[]): Promise<CleanupSummary> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.RunCleanup(jobIds);
+  }
+
   async deleteJob(id: string): Promise<void> {
     if (!this.isWails) throw new Error('Wails not available');
     return WailsApp.DeleteJob(id);
   }
 
+  async listTrash(): Promise<Job[]> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.ListTrash();
+  }
+
+  async restoreJob(id: string, restoreOutput: boolean): Promise<Job> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.RestoreJob(id, restoreOutput);
+  }
+
+  async purgeJob(id: string): Promise<void> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.PurgeJob(id);
+  }
+
+  async emptyTrash(): Promise<number> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.EmptyTrash();
+  }
+
+  async getInterruptedJobs(): Promise<InterruptedJob[]> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.GetInterruptedJobs();
+  }
+
+  async recoverJob(id: string, action: RecoveryAction): Promise<void> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.RecoverJob(id, action);
+  }
+
   async reExecuteJob(id: string): Promise<string> {
     if (!this.isWails) throw new Error('Wails not available');
     return WailsApp.ReExecuteJob(id);
   }
 
-  async rerunJob(jobID: string, useSameEnvironment: boolean, pythonEnvPath: string, rEnvPath: string): Promise<string> {
+  async rerunJob(jobID: string, useSameEnvironment: boolean, pythonEnvPath: string, rEnvPath: string, fromSnapshot: boolean = false): Promise<string> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.RerunJob(jobID, useSameEnvironment, pythonEnvPath, rEnvPath, fromSnapshot);
+  }
+
+  async verifyJobInputs(jobID: string): Promise<JobInputCheck[]> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.VerifyJobInputs(jobID);
+  }
+
+  async compareJobs(ids: string[]): Promise<JobComparison> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.CompareJobs(ids);
+  }
+
+  async executePluginBatch(request: BatchExecutionRequest): Promise<BatchSummary> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.ExecutePluginBatch(request);
+  }
+
+  async getBatch(id: string): Promise<BatchSummary> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.GetBatch(id);
+  }
+
+  async listBatches(projectID: string = ''): Promise<BatchSummary[]> {
+    if (!this.isWails) throw new Error('Wails not available');
+    return WailsApp.ListBatches(projectID);
+  }
+
+  async cancelBatch(id: string): Promise<void> {
     if (!this.isWails) throw new Error('Wails not available');
-    return WailsApp.RerunJob(jobID, useSameEnvironment, pythonEnvPath, rEnvPath);
+    return WailsApp.CancelBatch(id);
   }
 
   async executePythonScript(scriptName: string, args: string[] = []): Promise<string> {
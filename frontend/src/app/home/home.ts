@@ -36,6 +36,7 @@ export class Home implements OnInit {
   protected rVersion = signal('');
   protected rPath = signal('');
   protected jobs = signal<Job[]>([]);
+  private projectId = '';
   protected recentJobs = computed(() => this.jobs().slice(0, 5));
   protected importedFiles = signal<ImportedFile[]>([]);
   protected loading = signal(false);
@@ -143,7 +144,7 @@ export class Home implements OnInit {
           const updated = [...currentJobs];
           updated[index] = job;
           this.jobs.set(updated);
-        } else {
+        } else if (job.projectId === this.projectId) {
           this.jobs.set([job, ...currentJobs]);
         }
       }
@@ -158,6 +159,13 @@ export class Home implements OnInit {
         this.loadImportedFiles();
       });
     }
+
+    this.wails.projectChanged$.subscribe(project => {
+      if (project && project.id !== this.projectId) {
+        this.loadJobs();
+        this.loadImportedFiles();
+      }
+    });
   }
 
   async loadImportedFiles() {
@@ -232,6 +240,7 @@ export class Home implements OnInit {
 
     try {
       console.log('About to call wails.getAllJobs()');
+      this.projectId = (await this.wails.getActiveProject()).id;
       const allJobs = await this.wails.getAllJobs();
       console.log('Received response from getAllJobs():', allJobs);
 
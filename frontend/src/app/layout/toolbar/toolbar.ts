@@ -3,37 +3,145 @@ import { MatToolbarModule } from '@angular/material/toolbar';
 import { MatButtonModule } from '@angular/material/button';
 import { MatIconModule } from '@angular/material/icon';
 import { MatBadgeModule } from '@angular/material/badge';
+import { MatMenuModule } from '@angular/material/menu';
+import { MatDividerModule } from '@angular/material/divider';
+import { MatDialog } from '@angular/material/dialog';
 import { Router } from '@angular/router';
-import { Wails, Job } from '../../core/services/wails';
+import { Wails, Job, Project, ProjectSummary } from '../../core/services/wails';
+import { NotificationService } from '../../core/services/notification.service';
+import { ProjectDialog, ProjectDialogResult } from '../../components/project-dialog/project-dialog';
 import { Subscription } from 'rxjs';
 
 @Component({
   selector: 'app-toolbar',
-  imports: [MatToolbarModule, MatButtonModule, MatIconModule, MatBadgeModule],
+  imports: [MatToolbarModule, MatButtonModule, MatIconModule, MatBadgeModule, MatMenuModule, MatDividerModule],
   templateUrl: './toolbar.html',
   styleUrl: './toolbar.scss',
 })
 export class Toolbar implements OnInit, OnDestroy {
   menuToggle = output<void>();
   protected activeJobsCount = signal(0);
+  protected activeProject = signal<Project | null>(null);
+  protected projects = signal<ProjectSummary[]>([]);
   private jobUpdateSubscription?: Subscription;
+  private projectSubscription?: Subscription;
 
-  constructor(private router: Router, private wails: Wails) {}
+  constructor(
+    private router: Router,
+    private wails: Wails,
+    private dialog: MatDialog,
+    private notificationService: NotificationService
+  ) {}
 
   async ngOnInit() {
     await this.updateActiveJobsCount();
+    try {
+      this.activeProject.set(await this.wails.getActiveProject());
+    } catch (err) {
+      this.activeProject.set(null);
+    }
 
     this.jobUpdateSubscription = this.wails.jobUpdate$.subscribe((job: Job | null) => {
       if (job) {
         this.updateActiveJobsCount();
       }
     });
+
+    this.projectSubscription = this.wails.projectChanged$.subscribe((project: Project | null) => {
+      if (project) {
+        this.activeProject.set(project);
+        this.updateActiveJobsCount();
+      }
+    });
   }
 
   ngOnDestroy() {
     if (this.jobUpdateSubscription) {
       this.jobUpdateSubscription.unsubscribe();
     }
+    if (this.projectSubscription) {
+      this.projectSubscription.unsubscribe();
+    }
+  }
+
+  async loadProjects() {
+    try {
+      this.projects.set(await this.wails.listProjects());
+    } catch (err) {
+      this.projects.set([]);
+    }
+  }
+
+  async switchProject(id: string) {
+    try {
+      await this.wails.setActiveProject(id);
+    } catch (err) {
+      this.notificationService.showError(`Failed to switch project: ${err}`);
+    }
+  }
+
+  createProject() {
+    const dialogRef = this.dialog.open<ProjectDialog, unknown, ProjectDialogResult>(ProjectDialog, { width: '480px' });
+    dialogRef.afterClosed().subscribe(async result => {
+      if (!result) {
+        return;
+      }
+      try {
+        const project = await this.wails.createProject(result.name, result.description, result.outputDirectory);
+        await this.wails.setActiveProject(project.id);
+      } catch (err) {
+        this.notificationService.showError(`Failed to create project: ${err}`);
+      }
+    });
+  }
+
+  async exportProject() {
+    const project = this.activeProject();
+    if (!project) {
+      return;
+    }
+    try {
+      const name = project.name.replace(/[^A-Za-z0-9._-]+/g, '_');
+      const destPath = await this.wails.saveFileDialog('Export Project', `${name}.cauldron-project.tar.xz`);
+      if (!destPath) {
+        return;
+      }
+      const archivePath = await this.wails.exportProject(project.id, destPath);
+      this.notificationService.showSuccess(`Project exported to ${archivePath}`);
+    } catch (err) {
+      this.notificationService.showError(`Failed to export project: ${err}`);
+    }
+  }
+
+  async importProject() {
+    try {
+      const archivePath = await this.wails.openFileDialog('Import Project');
+      if (!archivePath) {
+        return;
+      }
+      const result = await this.wails.importProject(archivePath);
+      await this.wails.setActiveProject(result.project.id);
+      if (result.missingFiles.length > 0) {
+        this.notificationService.showWarning(
+          `Imported ${result.project.name}; ${result.missingFiles.length} imported file(s) are not available on this machine`);
+      } else {
+        this.notificationService.showSuccess(`Imported ${result.project.name} with ${result.jobs.length} job(s)`);
+      }
+    } catch (err) {
+      this.notificationService.showError(`Failed to import project: ${err}`);
+    }
+  }
+
+  async deleteProject() {
+    const project = this.activeProject();
+    if (!project) {
+      return;
+    }
+    try {
+      await this.wails.deleteProject(project.id);
+    } catch (err) {
+      this.notificationService.showError(`Failed to delete project: ${err}`);
+    }
   }
 
   async updateActiveJobsCount() {
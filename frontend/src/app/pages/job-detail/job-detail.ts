@@ -10,13 +10,14 @@ import { MatProgressSpinnerModule } from '@angular/material/progress-spinner';
 import { MatChipsModule } from '@angular/material/chips';
 import { MatExpansionModule } from '@angular/material/expansion';
 import { MatDialog } from '@angular/material/dialog';
-import { Wails, Job } from '../../core/services/wails';
+import { Wails, Job, JobLogLine, JobArtifact, JobInputCheck, PluginRuntimeHistory, ErrorFrame } from '../../core/services/wails';
 import { PcaPlot } from './pca-plot/pca-plot';
 import { PhatePlot } from './phate-plot/phate-plot';
 import { FuzzyClusteringPlot } from './fuzzy-clustering-plot/fuzzy-clustering-plot';
 import { PluginPlot } from './plugin-plot/plugin-plot';
 import { SampleAnnotation, SampleAnnotationData } from '../../components/sample-annotation/sample-annotation';
 import { Annotation, AnnotationService } from '../../core/services/annotation.service';
+import { NotificationService } from '../../core/services/notification.service';
 
 @Component({
   selector: 'app-job-detail',
@@ -43,13 +44,25 @@ export class JobDetail implements OnInit, OnDestroy {
   protected error = signal('');
   protected jobId: string = '';
   protected pluginPlots = signal<Array<{ fileName: string, title: string }>>([]);
+  protected artifacts = signal<JobArtifact[]>([]);
+  protected logLines = signal<JobLogLine[]>([]);
+  protected logTotal = signal(0);
+  protected loadingLog = signal(false);
+  protected exporting = signal(false);
+  protected runtimeHistory = signal<PluginRuntimeHistory | null>(null);
+  protected loadingHistory = signal(false);
+  protected inputChecks = signal<JobInputCheck[] | null>(null);
+  protected verifyingInputs = signal(false);
+  protected installingPackage = signal(false);
+  private readonly logPageSize = 1000;
 
   constructor(
     private route: ActivatedRoute,
     private router: Router,
     private wails: Wails,
     private dialog: MatDialog,
-    private annotationService: AnnotationService
+    private annotationService: AnnotationService,
+    private notificationService: NotificationService
   ) {}
 
   async ngOnInit() {
@@ -59,9 +72,20 @@ export class JobDetail implements OnInit, OnDestroy {
     });
 
     if (window.runtime) {
-      window.runtime.EventsOn('job:update', (data: Job) => {
+      window.runtime.EventsOn('job:update', async (data: Job) => {
         if (data.id === this.jobId) {
+          const finished = this.job()?.status === 'in_progress' && data.status !== 'in_progress';
           this.job.set(data);
+          if (finished) {
+            await this.loadArtifacts();
+          }
+        }
+      });
+
+      window.runtime.EventsOn('job:log', (data: { jobId: string, lines: JobLogLine[] }) => {
+        if (data.jobId === this.jobId) {
+          this.logLines.update(lines => [...lines, ...data.lines]);
+          this.logTotal.update(total => total + data.lines.length);
         }
       });
     }
@@ -70,6 +94,7 @@ export class JobDetail implements OnInit, OnDestroy {
   ngOnDestroy() {
     if (window.runtime) {
       window.runtime.EventsOff('job:update');
+      window.runtime.EventsOff('job:log');
     }
   }
 
@@ -78,10 +103,8 @@ export class JobDetail implements OnInit, OnDestroy {
     try {
       const jobData = await this.wails.getJob(this.jobId);
       this.job.set(jobData);
-
-      if (jobData.status === 'completed') {
-        await this.detectPluginPlots();
-      }
+      await this.loadLog();
+      await this.loadArtifacts();
     } catch (err: any) {
       this.error.set(err.message || 'Failed to load job');
     } finally {
@@ -89,6 +112,31 @@ export class JobDetail implements OnInit, OnDestroy {
     }
   }
 
+  async loadLog() {
+    const page = await this.wails.getJobLog(this.jobId, -1, this.logPageSize);
+    this.logLines.set(page.lines || []);
+    this.logTotal.set(page.total);
+  }
+
+  async loadEarlierLog() {
+    const loaded = this.logLines();
+    if (loaded.length >= this.logTotal()) {
+      return;
+    }
+
+    this.loadingLog.set(true);
+    try {
+      const remaining = this.logTotal() - loaded.length;
+      const offset = Math.max(0, remaining - this.logPageSize);
+      const page = await this.wails.getJobLog(this.jobId, offset, remaining - offset);
+      this.logLines.set([...(page.lines || []), ...loaded]);
+    } catch (err) {
+      await this.wails.logToFile(`[Job Detail] Failed to load earlier output: ${err}`);
+    } finally {
+      this.loadingLog.set(false);
+    }
+  }
+
   goBack() {
     this.router.navigate(['/jobs']);
   }
@@ -117,6 +165,11 @@ export class JobDetail implements OnInit, OnDestroy {
     let samples: string[] = [];
     if (annotations.length === 0) {
       samples = await this.extractSampleNames();
+      const known = new Map((await this.annotationService.loadProjectAnnotations(samples)).map(a => [a.sample, a]));
+      if (known.size > 0) {
+        annotations.push(...samples.map(sample => known.get(sample) || {sample, condition: ''}));
+        samples = [];
+      }
     }
 
     const dialogData: SampleAnnotationData = {
@@ -197,21 +250,123 @@ export class JobDetail implements OnInit, OnDestroy {
     return jobType === 'pca' || jobType === 'phate' || jobType === 'fuzzy-clustering';
   }
 
+  async loadArtifacts() {
+    try {
+      this.artifacts.set(await this.wails.getJobArtifacts(this.jobId) || []);
+    } catch (err) {
+      await this.wails.logToFile(`[Job Detail] Failed to load artifacts: ${err}`);
+      this.artifacts.set([]);
+    }
+
+    if (this.job()?.status === 'completed') {
+      await this.detectPluginPlots();
+    }
+  }
+
+  formatSize(bytes: number): string {
+    const units = ['B', 'KB', 'MB', 'GB'];
+    let size = bytes;
+    let unit = 0;
+    while (size >= 1024 && unit < units.length - 1) {
+      size /= 1024;
+      unit++;
+    }
+    return `${unit === 0 ? size : size.toFixed(1)} ${units[unit]}`;
+  }
+
+  hasMetrics(): boolean {
+    const exitCode = this.job()?.metrics?.exitCode;
+    return exitCode !== undefined && exitCode !== null;
+  }
+
+  peakMemory(metrics: { maxRssBytes: number, peakRssBytes: number }): number {
+    return Math.max(metrics.maxRssBytes || 0, metrics.peakRssBytes || 0);
+  }
+
+  formatSeconds(seconds: number | undefined): string {
+    if (seconds === undefined || seconds === null) {
+      return '-';
+    }
+    if (seconds < 60) {
+      return `${seconds.toFixed(1)}s`;
+    }
+    const minutes = Math.floor(seconds / 60);
+    if (minutes < 60) {
+      return `${minutes}m ${Math.round(seconds % 60)}s`;
+    }
+    return `${Math.floor(minutes / 60)}h ${minutes % 60}m`;
+  }
+
+  pluginId(): string {
+    const job = this.job();
+    return job?.parameters?.['pluginId'] || job?.type || '';
+  }
+
+  async verifyInputs() {
+    if (this.verifyingInputs()) {
+      return;
+    }
+    this.verifyingInputs.set(true);
+    try {
+      this.inputChecks.set(await this.wails.verifyJobInputs(this.jobId));
+    } catch (err) {
+      await this.wails.logToFile(`[Job Detail] Failed to verify inputs: ${err}`);
+    } finally {
+      this.verifyingInputs.set(false);
+    }
+  }
+
+  inputCheck(path: string): JobInputCheck | undefined {
+    return this.inputChecks()?.find(check => check.path === path);
+  }
+
+  async loadRuntimeHistory() {
+    if (this.runtimeHistory() || this.loadingHistory() || !this.pluginId()) {
+      return;
+    }
+    this.loadingHistory.set(true);
+    try {
+      this.runtimeHistory.set(await this.wails.getPluginRuntimeHistory(this.pluginId()));
+    } catch (err) {
+      await this.wails.logToFile(`[Job Detail] Failed to load runtime history: ${err}`);
+    } finally {
+      this.loadingHistory.set(false);
+    }
+  }
+
   async detectPluginPlots() {
     try {
       const plotFiles: Array<{ fileName: string, title: string }> = [];
 
-      for (let i = 1; i <= 10; i++) {
-        try {
-          const fileName = `plot_${i}.json`;
-          const content = await this.wails.readJobOutputFile(this.jobId, fileName);
-          if (content) {
-            const plotData = JSON.parse(content);
-            const title = plotData.layout?.title || `Plot ${i}`;
-            plotFiles.push({ fileName, title });
+      if (this.artifacts().length === 0) {
+        // Jobs finished before artifacts were recorded have no manifest.
+        for (let i = 1; i <= 10; i++) {
+          try {
+            const fileName = `plot_${i}.json`;
+            const content = await this.wails.readJobOutputFile(this.jobId, fileName);
+            if (content) {
+              const plotData = JSON.parse(content);
+              const title = plotData.layout?.title || `Plot ${i}`;
+              plotFiles.push({ fileName, title });
+            }
+          } catch (e) {
+            break;
           }
+        }
+      }
+
+      const plotArtifacts = this.artifacts()
+        .filter(a => /^plot_\d+\.json$/.test(a.path))
+        .sort((a, b) => parseInt(a.path.slice(5), 10) - parseInt(b.path.slice(5), 10));
+
+      for (const artifact of plotArtifacts) {
+        try {
+          const content = await this.wails.readJobOutputFile(this.jobId, artifact.path);
+          const plotData = JSON.parse(content);
+          const title = plotData.layout?.title || `Plot ${plotFiles.length + 1}`;
+          plotFiles.push({ fileName: artifact.path, title });
         } catch (e) {
-          break;
+          await this.wails.logToFile(`[Job Detail] Failed to read plot ${artifact.path}: ${e}`);
         }
       }
 
@@ -225,6 +380,85 @@ export class JobDetail implements OnInit, OnDestroy {
     }
   }
 
+  isFinished(): boolean {
+    const status = this.job()?.status;
+    return status === 'completed' || status === 'failed' || status === 'cancelled';
+  }
+
+  errorCategoryLabel(category: string): string {
+    switch (category) {
+      case 'missing_package': return 'Missing package';
+      case 'file_not_found': return 'File not found';
+      case 'memory': return 'Out of memory';
+      case 'user_input': return 'Input problem';
+      case 'timeout': return 'Timed out';
+      default: return 'Script error';
+    }
+  }
+
+  frameLocation(frame: ErrorFrame): string {
+    let location = frame.file || '';
+    if (frame.line) {
+      location += `:${frame.line}`;
+    }
+    if (frame.function) {
+      location += location ? ` in ${frame.function}` : frame.function;
+    }
+    return location;
+  }
+
+  canInstallMissingPackage(): boolean {
+    const job = this.job();
+    if (!job || job.errorDetails?.category !== 'missing_package' || !job.errorDetails.package) {
+      return false;
+    }
+    return job.errorDetails.language === 'r' ? !!job.rEnvPath : !!job.pythonEnvPath;
+  }
+
+  async installMissingPackage() {
+    const job = this.job();
+    const details = job?.errorDetails;
+    if (!job || !details?.package) {
+      return;
+    }
+    this.installingPackage.set(true);
+    try {
+      if (details.language === 'r') {
+        await this.wails.installRPackages(job.rEnvPath!, [details.package]);
+      } else {
+        await this.wails.installPythonPackages(job.pythonEnvPath!, [details.package]);
+      }
+      this.notificationService.showSuccess(`Installed ${details.package}. Rerun the job to try again.`);
+    } catch (error) {
+      await this.wails.logToFile(`[Job Detail] Failed to install ${details.package}: ${error}`);
+      this.notificationService.showError(`Failed to install ${details.package}: ${error}`);
+    } finally {
+      this.installingPackage.set(false);
+    }
+  }
+
+  async exportBundle() {
+    const job = this.job();
+    if (!job) {
+      return;
+    }
+    const name = job.name.replace(/[^A-Za-z0-9._-]+/g, '_');
+    try {
+      const destPath = await this.wails.saveFileDialog('Export Job Bundle', `${name}_${job.id.slice(0, 8)}.cauldron.tar.xz`);
+      if (!destPath) {
+        return;
+      }
+      this.exporting.set(true);
+      const bundlePath = await this.wails.exportJobBundle(job.id, destPath);
+      this.notificationService.showSuccess(`Bundle exported to ${bundlePath}`);
+    } catch (error) {
+      await this.wails.logToFile(`[Job Detail] Failed to export bundle: ${error}`);
+      this.notificationService.showError(`Failed to export bundle: ${error}`);
+    } finally {
+      this.exporting.set(false);
+    }
+  }
+
   async openResultFolder() {
     const outputPath = this.job()?.outputPath;
     if (outputPath) {
@@ -9,13 +9,23 @@ import { MatMenuModule } from '@angular/material/menu';
 import { MatTooltipModule } from '@angular/material/tooltip';
 import { MatTableModule } from '@angular/material/table';
 import { MatDialog } from '@angular/material/dialog';
+import { MatFormFieldModule } from '@angular/material/form-field';
+import { MatInputModule } from '@angular/material/input';
+import { MatSelectModule } from '@angular/material/select';
+import { MatCheckboxModule } from '@angular/material/checkbox';
 import { CommonModule } from '@angular/common';
-import { Wails, Job, PythonEnvironment, REnvironment } from '../../core/services/wails';
+import { FormsModule } from '@angular/forms';
+import { Wails, Job, JobQuery, JobSearchResult, PythonEnvironment, REnvironment } from '../../core/services/wails';
+import { BundleImportAction, BundleImportModal } from '../../components/bundle-import-modal/bundle-import-modal';
+import { RerunInputsChoice, RerunInputsData, RerunInputsModal } from '../../components/rerun-inputs-modal/rerun-inputs-modal';
+import { JobCompareData, JobCompareModal } from '../../components/job-compare-modal/job-compare-modal';
+import { firstValueFrom } from 'rxjs';
 
 @Component({
   selector: 'app-jobs',
   imports: [
     CommonModule,
+    FormsModule,
     MatCardModule,
     MatChipsModule,
     MatIconModule,
@@ -23,29 +33,51 @@ import { Wails, Job, PythonEnvironment, REnvironment } from '../../core/services
     MatProgressBarModule,
     MatMenuModule,
     MatTooltipModule,
-    MatTableModule
+    MatTableModule,
+    MatFormFieldModule,
+    MatInputModule,
+    MatSelectModule,
+    MatCheckboxModule
   ],
   templateUrl: './jobs.html',
   styleUrl: './jobs.scss',
 })
 export class Jobs implements OnInit {
   protected jobs = signal<Job[]>([]);
+  private projectId = '';
   protected loading = signal(false);
+  protected loadingMore = signal(false);
+  protected totalJobs = signal(0);
+  protected nextCursor = signal('');
+  protected filters: { search: string; statuses: string[]; tag: string; envPath: string; sortBy: string; ascending: boolean } = {
+    search: '',
+    statuses: [],
+    tag: '',
+    envPath: '',
+    sortBy: 'created',
+    ascending: false
+  };
+  protected contentSearch = '';
+  protected contentSearchAllProjects = false;
+  protected searchResults = signal<JobSearchResult[] | null>(null);
+  protected searching = signal(false);
+  protected readonly statusOptions = ['pending', 'in_progress', 'completed', 'failed', 'cancelled', 'blocked', 'skipped'];
   protected pythonEnvironments = signal<PythonEnvironment[]>([]);
   protected rEnvironments = signal<REnvironment[]>([]);
   protected jobProgress = signal<Record<string, {message: string, percentage: number}>>({});
-  protected displayedColumns: string[] = ['status', 'name', 'type', 'environment', 'createdAt', 'actions'];
+  protected compareIds = signal<string[]>([]);
+  protected displayedColumns: string[] = ['select', 'status', 'name', 'type', 'environment', 'createdAt', 'actions'];
   protected queueStatus = signal<{
     paused: boolean;
     stopImmediate: boolean;
-    currentJobID: string;
+    runningJobIDs?: string[];
     pendingCount: number;
     inProgressCount: number;
     queueLength: number;
   }>({
     paused: false,
     stopImmediate: false,
-    currentJobID: '',
+    runningJobIDs: [],
     pendingCount: 0,
     inProgressCount: 0,
     queueLength: 0
@@ -64,6 +96,11 @@ export class Jobs implements OnInit {
     this.setupJobUpdates();
     this.setupProgressUpdates();
     this.setupQueueStatusUpdates();
+    this.wails.projectChanged$.subscribe(project => {
+      if (project && project.id !== this.projectId) {
+        this.loadJobs();
+      }
+    });
   }
 
   async loadEnvironments(): Promise<void> {
@@ -82,8 +119,11 @@ export class Jobs implements OnInit {
   async loadJobs(): Promise<void> {
     this.loading.set(true);
     try {
-      const allJobs = await this.wails.getAllJobs();
-      this.jobs.set(allJobs);
+      this.projectId = (await this.wails.getActiveProject()).id;
+      const page = await this.wails.queryJobs(this.buildQuery());
+      this.jobs.set(page.jobs);
+      this.totalJobs.set(page.total);
+      this.nextCursor.set(page.nextCursor || '');
     } catch (error) {
       console.error('Failed to load jobs:', error);
     } finally {
@@ -91,6 +131,76 @@ export class Jobs implements OnInit {
     }
   }
 
+  async loadMoreJobs(): Promise<void> {
+    if (!this.nextCursor()) return;
+    this.loadingMore.set(true);
+    try {
+      const page = await this.wails.queryJobs(this.buildQuery(this.nextCursor()));
+      this.jobs.update(jobs => [...jobs, ...page.jobs]);
+      this.nextCursor.set(page.nextCursor || '');
+    } catch (error) {
+      console.error('Failed to load more jobs:', error);
+    } finally {
+      this.loadingMore.set(false);
+    }
+  }
+
+  buildQuery(cursor = ''): Partial<JobQuery> {
+    return {
+      search: this.filters.search.trim(),
+      statuses: this.filters.statuses,
+      tag: this.filters.tag.trim(),
+      envPath: this.filters.envPath,
+      sortBy: this.filters.sortBy,
+      ascending: this.filters.ascending,
+      cursor
+    };
+  }
+
+  async searchJobContents(): Promise<void> {
+    const text = this.contentSearch.trim();
+    if (!text) {
+      this.searchResults.set(null);
+      return;
+    }
+    this.searching.set(true);
+    try {
+      this.searchResults.set(await this.wails.searchJobText(text, this.contentSearchAllProjects));
+    } catch (error) {
+      console.error('Failed to search jobs:', error);
+      this.searchResults.set([]);
+    } finally {
+      this.searching.set(false);
+    }
+  }
+
+  clearContentSearch(): void {
+    this.contentSearch = '';
+    this.searchResults.set(null);
+  }
+
+  hasFilters(): boolean {
+    return !!(this.filters.search.trim() || this.filters.statuses.length || this.filters.tag.trim() || this.filters.envPath);
+  }
+
+  clearFilters(): void {
+    this.filters = { ...this.filters, search: '', statuses: [], tag: '', envPath: '' };
+    this.loadJobs();
+  }
+
+  toggleSortDirection(): void {
+    this.filters.ascending = !this.filters.ascending;
+    this.loadJobs();
+  }
+
+  formatDuration(seconds?: number): string {
+    if (seconds === undefined || seconds === null) return '';
+    if (seconds < 60) return `${seconds.toFixed(1)}s`;
+    const minutes = Math.floor(seconds / 60);
+    if (minutes < 60) return `${minutes}m ${Math.round(seconds % 60)}s`;
+    return `${Math.floor(minutes / 60)}h ${minutes % 60}m`;
+  }
+
   setupJobUpdates(): void {
     this.wails.jobUpdate$.subscribe(job => {
       if (!job) return;
@@ -102,8 +212,9 @@ export class Jobs implements OnInit {
         const updated = [...currentJobs];
         updated[index] = job;
         this.jobs.set(updated);
-      } else {
+      } else if (job.projectId === this.projectId && !this.hasFilters()) {
         this.jobs.set([job, ...currentJobs]);
+        this.totalJobs.update(total => total + 1);
       }
     });
   }
@@ -137,11 +248,35 @@ export class Jobs implements OnInit {
     try {
       await this.wails.deleteJob(id);
       this.jobs.update(jobs => jobs.filter(j => j.id !== id));
+      this.totalJobs.update(total => Math.max(0, total - 1));
     } catch (error) {
       console.error('Failed to delete job:', error);
     }
   }
 
+  openTrash(): void {
+    this.router.navigate(['/trash']);
+  }
+
+  isComparing(id: string): boolean {
+    return this.compareIds().includes(id);
+  }
+
+  toggleCompare(id: string): void {
+    const ids = this.compareIds();
+    this.compareIds.set(ids.includes(id) ? ids.filter(existing => existing !== id) : [...ids, id]);
+  }
+
+  // compareJobs compares the selected jobs in the order they were picked, so
+  // the first job picked is the reference for output tables.
+  compareJobs(): void {
+    this.dialog.open<JobCompareModal, JobCompareData>(JobCompareModal, {
+      width: '960px',
+      maxWidth: '95vw',
+      data: { ids: this.compareIds() }
+    });
+  }
+
   viewJobDetail(id: string): void {
     this.router.navigate(['/jobs', id]);
   }
@@ -203,10 +338,58 @@ export class Jobs implements OnInit {
     return true;
   }
 
+  async importBundle(): Promise<void> {
+    try {
+      const bundlePath = await this.wails.openFileDialog('Import Job Bundle');
+      if (!bundlePath) {
+        return;
+      }
+      const result = await this.wails.importJobBundle(bundlePath);
+      await this.loadJobs();
+
+      const dialogRef = this.dialog.open<BundleImportModal, unknown, BundleImportAction>(BundleImportModal, {
+        width: '640px',
+        data: result
+      });
+      dialogRef.afterClosed().subscribe(async action => {
+        if (action === 'view') {
+          this.router.navigate(['/jobs', result.jobId]);
+        } else if (action === 'rerun') {
+          const settings = await this.wails.getSettings();
+          const newJobId = await this.wails.rerunJob(result.jobId, false, settings.pythonPath || '', settings.rPath || '');
+          await this.loadJobs();
+          this.router.navigate(['/jobs', newJobId]);
+        }
+      });
+    } catch (error) {
+      console.error('Failed to import bundle:', error);
+    }
+  }
+
+  // chooseRerunInputs asks whether to rerun from the input snapshot when an
+  // input changed since the job was submitted. It resolves to null when the
+  // rerun is cancelled.
+  private async chooseRerunInputs(job: Job): Promise<boolean | null> {
+    const checks = await this.wails.verifyJobInputs(job.id);
+    if (checks.every(check => check.status === 'unchanged')) {
+      return false;
+    }
+    const dialogRef = this.dialog.open<RerunInputsModal, RerunInputsData, RerunInputsChoice>(RerunInputsModal, {
+      width: '640px',
+      data: { jobName: job.name, checks }
+    });
+    const choice = await firstValueFrom(dialogRef.afterClosed());
+    return choice ? choice === 'snapshot' : null;
+  }
+
   async rerunWithSameEnvironment(event: Event, job: Job): Promise<void> {
     event.stopPropagation();
     try {
-      const newJobId = await this.wails.rerunJob(job.id, true, '', '');
+      const fromSnapshot = await this.chooseRerunInputs(job);
+      if (fromSnapshot === null) {
+        return;
+      }
+      const newJobId = await this.wails.rerunJob(job.id, true, '', '', fromSnapshot);
       await this.loadJobs();
       this.router.navigate(['/jobs', newJobId]);
     } catch (error) {
@@ -218,13 +401,18 @@ export class Jobs implements OnInit {
     event.stopPropagation();
 
     try {
+      const fromSnapshot = await this.chooseRerunInputs(job);
+      if (fromSnapshot === null) {
+        return;
+      }
       const settings = await this.wails.getSettings();
 
       const newJobId = await this.wails.rerunJob(
         job.id,
         false,
         settings.pythonPath || '',
-        settings.rPath || ''
+        settings.rPath || '',
+        fromSnapshot
       );
       await this.loadJobs();
       this.router.navigate(['/jobs', newJobId]);
@@ -247,12 +435,19 @@ export class Jobs implements OnInit {
     return false;
   }
 
-  getJobProgress(jobId: string): {message: string, percentage: number} | null {
-    return this.jobProgress()[jobId] || null;
+  getJobProgress(job: Job): {message: string, percentage: number} | null {
+    const reported = this.jobProgress()[job.id];
+    if (reported) {
+      return reported;
+    }
+    if (job.progress > 0 || job.progressMessage) {
+      return { message: job.progressMessage || '', percentage: job.progress };
+    }
+    return null;
   }
 
-  hasProgress(jobId: string): boolean {
-    return !!this.jobProgress()[jobId];
+  hasProgress(job: Job): boolean {
+    return this.getJobProgress(job) !== null;
   }
 
   async openOutputDirectory(event: Event, job: Job): Promise<void> {
@@ -6,10 +6,15 @@ import { MatButtonModule } from '@angular/material/button';
 import { MatIconModule } from '@angular/material/icon';
 import { MatProgressSpinnerModule } from '@angular/material/progress-spinner';
 import { MatSnackBar, MatSnackBarModule } from '@angular/material/snack-bar';
+import { MatFormFieldModule } from '@angular/material/form-field';
+import { MatInputModule } from '@angular/material/input';
+import { MatSelectModule } from '@angular/material/select';
+import { FormsModule } from '@angular/forms';
 import { DynamicFormComponent } from '../../components/dynamic-form/dynamic-form';
 import { PluginV2Service } from '../../core/services/plugin-v2';
 import { models } from '../../../wailsjs/go/models';
 import { EnvironmentIndicator } from '../../components/environment-indicator/environment-indicator';
+import { Wails, BatchMode, BatchSummary } from '../../core/services/wails';
 
 @Component({
   selector: 'app-plugin-execute',
@@ -20,6 +25,10 @@ import { EnvironmentIndicator } from '../../components/environment-indicator/env
     MatIconModule,
     MatProgressSpinnerModule,
     MatSnackBarModule,
+    MatFormFieldModule,
+    MatInputModule,
+    MatSelectModule,
+    FormsModule,
     DynamicFormComponent,
     EnvironmentIndicator
   ],
@@ -35,10 +44,19 @@ export class PluginExecute implements OnInit {
   error = signal('');
   createdJobId = signal<string | null>(null);
 
+  formValues: Record<string, any> = {};
+  batchName = '';
+  batchMode: BatchMode = 'cartesian';
+  batchFileInput = '';
+  batchFiles = '';
+  batchSweep = '';
+  batch = signal<BatchSummary | null>(null);
+
   constructor(
     private route: ActivatedRoute,
     private router: Router,
     private pluginService: PluginV2Service,
+    private wails: Wails,
     private snackBar: MatSnackBar
   ) {}
 
@@ -52,6 +70,7 @@ export class PluginExecute implements OnInit {
       }
 
       this.createdJobId.set(null);
+      this.batch.set(null);
       await this.loadPlugin(pluginId);
     });
   }
@@ -100,6 +119,105 @@ export class PluginExecute implements OnInit {
     this.createdJobId.set(null);
   }
 
+  fileInputs(): models.PluginInputV2[] {
+    return this.plugin()?.definition.inputs.filter(input => input.type === 'file') || [];
+  }
+
+  // parseSweep reads one parameter per line as "name = value, value, ...",
+  // converting the values to the parameter's type.
+  parseSweep(): Record<string, any[]> {
+    const sweep: Record<string, any[]> = {};
+    const inputs = this.plugin()?.definition.inputs || [];
+    for (const line of this.batchSweep.split('\n')) {
+      const separator = line.indexOf('=');
+      if (separator < 0) continue;
+      const name = line.slice(0, separator).trim();
+      const input = inputs.find(i => i.name === name);
+      if (!input) {
+        throw new Error(`Unknown parameter: ${name}`);
+      }
+      sweep[name] = line.slice(separator + 1).split(',').map(v => v.trim()).filter(v => v !== '').map(v => {
+        if (input.type === 'number') return Number(v);
+        if (input.type === 'boolean') return v === 'true';
+        return v;
+      });
+    }
+    return sweep;
+  }
+
+  async onExecuteBatch() {
+    const plugin = this.plugin();
+    if (!plugin) return;
+
+    try {
+      this.executing.set(true);
+      const files = this.batchFiles.split('\n').map(f => f.trim()).filter(f => f !== '');
+      const sweep = this.parseSweep();
+      const parameters = { ...this.formValues };
+      if (files.length > 0) {
+        delete parameters[this.batchFileInput];
+      }
+      for (const name of Object.keys(sweep)) {
+        delete parameters[name];
+      }
+
+      const summary = await this.wails.executePluginBatch(new models.BatchExecutionRequest({
+        name: this.batchName,
+        pluginId: plugin.definition.plugin.id,
+        parameters,
+        fileInput: files.length > 0 ? this.batchFileInput : '',
+        files,
+        sweep,
+        mode: this.batchMode
+      }));
+      this.batch.set(summary);
+
+      this.snackBar.open(`Batch queued with ${summary.total} runs`, 'Close', {
+        duration: 3000,
+        horizontalPosition: 'end',
+        verticalPosition: 'top'
+      });
+    } catch (err) {
+      this.snackBar.open(`Failed to run batch: ${err}`, 'Close', {
+        duration: 5000,
+        horizontalPosition: 'end',
+        verticalPosition: 'top',
+        panelClass: ['error-snackbar']
+      });
+    } finally {
+      this.executing.set(false);
+    }
+  }
+
+  async refreshBatch() {
+    const batch = this.batch();
+    if (!batch) return;
+    try {
+      this.batch.set(await this.wails.getBatch(batch.batch.id));
+    } catch (err) {
+      this.snackBar.open(`Failed to load batch: ${err}`, 'Close', { duration: 5000 });
+    }
+  }
+
+  async cancelBatch() {
+    const batch = this.batch();
+    if (!batch) return;
+    try {
+      await this.wails.cancelBatch(batch.batch.id);
+      await this.refreshBatch();
+    } catch (err) {
+      this.snackBar.open(`Failed to cancel batch: ${err}`, 'Close', { duration: 5000 });
+    }
+  }
+
+  viewRun(jobId: string) {
+    this.router.navigate(['/job', jobId]);
+  }
+
+  batchValues(run: models.BatchRun): string {
+    return Object.entries(run.values || {}).map(([name, value]) => `${name}=${value}`).join(', ');
+  }
+
   loadExample() {
     this.dynamicForm?.loadExample();
   }
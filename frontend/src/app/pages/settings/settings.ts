@@ -11,10 +11,13 @@ import { MatChipsModule } from '@angular/material/chips';
 import { MatDividerModule } from '@angular/material/divider';
 import { MatListModule } from '@angular/material/list';
 import { MatTooltipModule } from '@angular/material/tooltip';
+import { MatSlideToggleModule } from '@angular/material/slide-toggle';
 import { MatDialog } from '@angular/material/dialog';
 import { Wails, PythonEnvironment, REnvironment, VirtualEnvironment, Config } from '../../core/services/wails';
 import { PackagesModal } from '../../components/packages-modal/packages-modal';
 import { DownloadPortableEnvDialogComponent } from '../../components/download-portable-env-dialog/download-portable-env-dialog';
+import { CleanupPreviewModal } from '../../components/cleanup-preview-modal/cleanup-preview-modal';
+import { NotificationService } from '../../core/services/notification.service';
 
 @Component({
   selector: 'app-settings',
@@ -30,7 +33,8 @@ import { DownloadPortableEnvDialogComponent } from '../../components/download-po
     MatChipsModule,
     MatDividerModule,
     MatListModule,
-    MatTooltipModule
+    MatTooltipModule,
+    MatSlideToggleModule
   ],
   templateUrl: './settings.html',
   styleUrl: './settings.scss',
@@ -50,10 +54,12 @@ export class Settings implements OnInit {
   protected selectedREnv = signal<string>('');
   protected pythonInstallProgress = signal<{message: string, percentage: number} | null>(null);
   protected rInstallProgress = signal<{message: string, percentage: number} | null>(null);
+  protected previewingCleanup = signal(false);
 
   constructor(
     private wails: Wails,
-    private dialog: MatDialog
+    private dialog: MatDialog,
+    private notificationService: NotificationService
   ) {}
 
   async ngOnInit(): Promise<void> {
@@ -180,6 +186,87 @@ export class Settings implements OnInit {
     }
   }
 
+  async saveRetentionSetting(key: 'retentionKeepPerPlugin' | 'retentionFailedDays' | 'retentionMaxOutputMb' | 'trashRetentionDays', event: Event): Promise<void> {
+    const value = Math.max(0, Math.floor(Number((event.target as HTMLInputElement).value) || 0));
+    this.config.update(c => ({ ...c, [key]: value }));
+    await this.saveSetting(key, value);
+  }
+
+  async saveRecoveryPolicy(policy: string): Promise<void> {
+    this.config.update(c => ({ ...c, recoveryPolicy: policy }));
+    await this.saveSetting('recoveryPolicy', policy);
+  }
+
+  async saveInputSnapshot(mode: string): Promise<void> {
+    this.config.update(c => ({ ...c, inputSnapshot: mode }));
+    await this.saveSetting('inputSnapshot', mode);
+  }
+
+  async saveDesktopNotifications(enabled: boolean): Promise<void> {
+    this.config.update(c => ({ ...c, desktopNotifications: enabled }));
+    await this.saveSetting('desktopNotifications', enabled);
+  }
+
+  async saveWebhookUrls(event: Event): Promise<void> {
+    const urls = (event.target as HTMLTextAreaElement).value.split('\n').map(url => url.trim()).filter(url => url !== '');
+    try {
+      await this.wails.setSetting('webhookUrls', urls);
+      this.config.update(c => ({ ...c, webhookUrls: urls }));
+    } catch (error) {
+      this.notificationService.showError(`${error}`);
+    }
+  }
+
+  async saveWebhookSecret(event: Event): Promise<void> {
+    const secret = (event.target as HTMLInputElement).value;
+    this.config.update(c => ({ ...c, webhookSecret: secret }));
+    await this.saveSetting('webhookSecret', secret);
+  }
+
+  async testWebhooks(): Promise<void> {
+    const urls = this.config().webhookUrls || [];
+    for (const url of urls) {
+      try {
+        await this.wails.testWebhook(url);
+        this.notificationService.showSuccess(`Test payload delivered to ${url}`);
+      } catch (error) {
+        this.notificationService.showError(`Webhook ${url} failed: ${error}`);
+      }
+    }
+  }
+
+  async previewCleanup(): Promise<void> {
+    this.previewingCleanup.set(true);
+    try {
+      const plan = await this.wails.previewCleanup();
+      const dialogRef = this.dialog.open<CleanupPreviewModal, unknown, string[]>(CleanupPreviewModal, {
+        width: '800px',
+        data: plan
+      });
+      dialogRef.afterClosed().subscribe(async jobIds => {
+        if (!jobIds || jobIds.length === 0) {
+          return;
+        }
+        try {
+          const summary = await this.wails.runCleanup(jobIds);
+          const freed = (summary.freedBytes / (1024 * 1024)).toFixed(1);
+          const message = `Deleted ${summary.deletedJobs} job(s) and ${summary.deletedDirectories} output folder(s), freed ${freed} MB`;
+          if (summary.errors?.length) {
+            this.notificationService.showWarning(`${message}. ${summary.errors.length} item(s) could not be removed.`);
+          } else {
+            this.notificationService.showSuccess(message);
+          }
+        } catch (error) {
+          this.notificationService.showError(`Cleanup failed: ${error}`);
+        }
+      });
+    } catch (error) {
+      this.notificationService.showError(`Failed to preview cleanup: ${error}`);
+    } finally {
+      this.previewingCleanup.set(false);
+    }
+  }
+
   private async saveSetting(key: string, value: any): Promise<void> {
     try {
       await this.wails.setSetting(key, value);
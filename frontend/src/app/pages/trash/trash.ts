@@ -0,0 +1,98 @@
+import { Component, OnInit, signal } from '@angular/core';
+import { Router } from '@angular/router';
+import { CommonModule } from '@angular/common';
+import { MatCardModule } from '@angular/material/card';
+import { MatIconModule } from '@angular/material/icon';
+import { MatButtonModule } from '@angular/material/button';
+import { MatMenuModule } from '@angular/material/menu';
+import { MatTooltipModule } from '@angular/material/tooltip';
+import { MatTableModule } from '@angular/material/table';
+import { Wails, Job } from '../../core/services/wails';
+import { NotificationService } from '../../core/services/notification.service';
+
+@Component({
+  selector: 'app-trash',
+  imports: [
+    CommonModule,
+    MatCardModule,
+    MatIconModule,
+    MatButtonModule,
+    MatMenuModule,
+    MatTooltipModule,
+    MatTableModule
+  ],
+  templateUrl: './trash.html',
+  styleUrl: './trash.scss',
+})
+export class Trash implements OnInit {
+  protected jobs = signal<Job[]>([]);
+  protected loading = signal(false);
+  protected retentionDays = signal(0);
+  protected displayedColumns: string[] = ['name', 'status', 'deletedAt', 'output', 'actions'];
+
+  constructor(
+    private wails: Wails,
+    private router: Router,
+    private notificationService: NotificationService
+  ) {}
+
+  async ngOnInit(): Promise<void> {
+    await this.loadTrash();
+    try {
+      const config = await this.wails.getSettings();
+      this.retentionDays.set(config.trashRetentionDays);
+    } catch (error) {
+      console.error('Failed to load settings:', error);
+    }
+  }
+
+  async loadTrash(): Promise<void> {
+    this.loading.set(true);
+    try {
+      this.jobs.set(await this.wails.listTrash());
+    } catch (error) {
+      console.error('Failed to load trash:', error);
+    } finally {
+      this.loading.set(false);
+    }
+  }
+
+  async restoreJob(job: Job, restoreOutput: boolean): Promise<void> {
+    try {
+      await this.wails.restoreJob(job.id, restoreOutput);
+      this.jobs.update(jobs => jobs.filter(j => j.id !== job.id));
+      this.notificationService.showSuccess(`Restored ${job.name}`);
+    } catch (error) {
+      this.notificationService.showError(`Failed to restore ${job.name}: ${error}`);
+    }
+  }
+
+  async purgeJob(job: Job): Promise<void> {
+    if (!confirm(`Permanently delete ${job.name}? This cannot be undone.`)) {
+      return;
+    }
+    try {
+      await this.wails.purgeJob(job.id);
+      this.jobs.update(jobs => jobs.filter(j => j.id !== job.id));
+    } catch (error) {
+      this.notificationService.showError(`Failed to delete ${job.name}: ${error}`);
+    }
+  }
+
+  async emptyTrash(): Promise<void> {
+    if (!confirm(`Permanently delete all ${this.jobs().length} job(s) in the trash? This cannot be undone.`)) {
+      return;
+    }
+    try {
+      const purged = await this.wails.emptyTrash();
+      this.notificationService.showSuccess(`Deleted ${purged} job(s) permanently`);
+      await this.loadTrash();
+    } catch (error) {
+      this.notificationService.showError(`Failed to empty trash: ${error}`);
+    }
+  }
+
+  backToJobs(): void {
+    this.router.navigate(['/jobs']);
+  }
+}
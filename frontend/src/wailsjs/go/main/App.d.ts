@@ -3,8 +3,16 @@
 import {models} from '../models';
 import {services} from '../models';
 
+export function CancelBatch(arg1:string):Promise<void>;
+
+export function CancelJob(arg1:string):Promise<void>;
+
+export function CompareJobs(arg1:Array<string>):Promise<models.JobComparison>;
+
 export function CreateJob(arg1:models.JobRequest):Promise<string>;
 
+export function CreateProject(arg1:string,arg2:string,arg3:string):Promise<models.Project>;
+
 export function CreatePythonVirtualEnv(arg1:string,arg2:string):Promise<void>;
 
 export function CreateSamplePlugin():Promise<void>;
@@ -13,6 +21,10 @@ export function DeleteImportedFile(arg1:number):Promise<void>;
 
 export function DeleteJob(arg1:string):Promise<void>;
 
+export function DeleteProject(arg1:string):Promise<void>;
+
+export function DeleteSampleAnnotations(arg1:Array<string>):Promise<void>;
+
 export function DeleteVirtualEnvironment(arg1:number):Promise<void>;
 
 export function DetectPythonEnvironments():Promise<Array<services.PythonEnvironment>>;
@@ -25,34 +37,54 @@ export function DetectRPath():Promise<string>;
 
 export function DownloadPortableEnvironment(arg1:string,arg2:string):Promise<void>;
 
+export function EmptyTrash():Promise<number>;
+
 export function ExecutePlugin(arg1:models.PluginExecutionRequest):Promise<string>;
 
+export function ExecutePluginBatch(arg1:models.BatchExecutionRequest):Promise<models.BatchSummary>;
+
 export function ExecutePluginV2(arg1:models.PluginExecutionRequestV2):Promise<string>;
 
 export function ExecutePythonScript(arg1:string,arg2:Array<string>):Promise<string>;
 
 export function ExecuteRScript(arg1:string,arg2:Array<string>):Promise<string>;
 
+export function ExportJobBundle(arg1:string,arg2:string):Promise<string>;
+
+export function ExportProject(arg1:string,arg2:string):Promise<string>;
+
+export function GetActiveProject():Promise<models.Project>;
+
 export function GetActivePythonEnvironment():Promise<services.PythonEnvironment>;
 
 export function GetActiveREnvironment():Promise<services.REnvironment>;
 
 export function GetAllJobs():Promise<Array<models.Job>>;
 
+export function GetBatch(arg1:string):Promise<models.BatchSummary>;
+
 export function GetBundledRequirementsPath(arg1:string):Promise<string>;
 
 export function GetExampleFilePath(arg1:string,arg2:string):Promise<string>;
 
 export function GetImportedFiles():Promise<Array<services.ImportedFile>>;
 
+export function GetInterruptedJobs():Promise<Array<models.InterruptedJob>>;
+
 export function GetJob(arg1:string):Promise<models.Job>;
 
+export function GetJobArtifacts(arg1:string):Promise<Array<models.JobArtifact>>;
+
+export function GetJobLog(arg1:string,arg2:number,arg3:number,arg4:string):Promise<models.JobLogPage>;
+
 export function GetJobQueueStatus():Promise<Record<string, any>>;
 
 export function GetLogFilePath():Promise<string>;
 
 export function GetPlugin(arg1:string):Promise<models.Plugin>;
 
+export function GetPluginRuntimeHistory(arg1:string,arg2:number):Promise<models.PluginRuntimeHistory>;
+
 export function GetPluginV2(arg1:string):Promise<models.PluginV2>;
 
 export function GetPlugins():Promise<Array<models.Plugin>>;
@@ -67,12 +99,18 @@ export function GetPortableEnvironmentURL(arg1:string,arg2:string,arg3:string,ar
 
 export function GetPythonVersion():Promise<string>;
 
+export function GetQueuedJobs():Promise<Array<models.Job>>;
+
 export function GetRVersion():Promise<string>;
 
+export function GetSampleAnnotations():Promise<Array<models.SampleAnnotation>>;
+
 export function GetSettings():Promise<models.Config>;
 
 export function GetVirtualEnvironments():Promise<Array<services.VirtualEnvironment>>;
 
+export function GetWorkers():Promise<Array<models.WorkerInfo>>;
+
 export function Greet(arg1:string):Promise<string>;
 
 export function HandleQuit():Promise<void>;
@@ -81,16 +119,26 @@ export function HasInProgressJobs():Promise<boolean>;
 
 export function ImportDataFile(arg1:string):Promise<number>;
 
+export function ImportJobBundle(arg1:string):Promise<models.JobBundleImport>;
+
+export function ImportProject(arg1:string):Promise<models.ProjectImport>;
+
 export function InstallPythonPackages(arg1:string,arg2:Array<string>):Promise<void>;
 
 export function InstallPythonRequirements(arg1:string,arg2:string):Promise<void>;
 
 export function InstallRPackages(arg1:string,arg2:Array<string>):Promise<void>;
 
+export function ListBatches(arg1:string):Promise<Array<models.BatchSummary>>;
+
+export function ListProjects():Promise<Array<models.ProjectSummary>>;
+
 export function ListPythonPackages(arg1:string):Promise<Array<string>>;
 
 export function ListRPackages(arg1:string):Promise<Array<string>>;
 
+export function ListTrash():Promise<Array<models.Job>>;
+
 export function LoadRPackagesFromFile(arg1:string):Promise<Array<string>>;
 
 export function LogToFile(arg1:string):Promise<void>;
@@ -113,6 +161,12 @@ export function ParseDataFile(arg1:string,arg2:number):Promise<services.DataFile
 
 export function PauseJobQueue():Promise<void>;
 
+export function PreviewCleanup():Promise<models.CleanupPlan>;
+
+export function PurgeJob(arg1:string):Promise<void>;
+
+export function QueryJobs(arg1:models.JobQuery):Promise<models.JobQueryPage>;
+
 export function ReExecuteJob(arg1:string):Promise<string>;
 
 export function ReadFile(arg1:string):Promise<Array<number>>;
@@ -121,14 +175,22 @@ export function ReadFilePreview(arg1:string,arg2:number):Promise<Array<string>>;
 
 export function ReadJobOutputFile(arg1:string,arg2:string):Promise<string>;
 
+export function RecoverJob(arg1:string,arg2:string):Promise<void>;
+
 export function ReloadPlugins():Promise<void>;
 
 export function ReloadPluginsV2():Promise<void>;
 
-export function RerunJob(arg1:string,arg2:boolean,arg3:string,arg4:string):Promise<string>;
+export function ReorderPendingJobs(arg1:Array<string>):Promise<void>;
+
+export function RerunJob(arg1:string,arg2:boolean,arg3:string,arg4:string,arg5:boolean):Promise<string>;
+
+export function RestoreJob(arg1:string,arg2:boolean):Promise<models.Job>;
 
 export function ResumeJobQueue():Promise<void>;
 
+export function RunCleanup(arg1:Array<string>):Promise<models.CleanupSummary>;
+
 export function RunNormalization(arg1:string,arg2:string,arg3:Array<string>,arg4:string):Promise<string>;
 
 export function RunPCAAnalysis(arg1:string,arg2:string,arg3:Array<string>,arg4:number,arg5:boolean):Promise<string>;
@@ -137,12 +199,28 @@ export function RunPHATEAnalysis(arg1:string,arg2:string,arg3:Array<string>,arg4
 
 export function SaveFile(arg1:string,arg2:string):Promise<string>;
 
+export function SaveSampleAnnotations(arg1:Array<models.SampleAnnotation>):Promise<void>;
+
+export function SearchJobText(arg1:string,arg2:boolean,arg3:number):Promise<Array<models.JobSearchResult>>;
+
+export function SetActiveProject(arg1:string):Promise<void>;
+
 export function SetActivePythonEnvironment(arg1:string):Promise<void>;
 
 export function SetActiveREnvironment(arg1:string):Promise<void>;
 
+export function SetJobPriority(arg1:string,arg2:number):Promise<void>;
+
+export function SetJobTags(arg1:string,arg2:Array<string>):Promise<void>;
+
 export function SetSetting(arg1:string,arg2:any):Promise<void>;
 
+export function SetWorkerCount(arg1:string,arg2:number):Promise<void>;
+
 export function StopJobQueueImmediate():Promise<void>;
 
+export function TestWebhook(arg1:string):Promise<void>;
+
+export function VerifyJobInputs(arg1:string):Promise<Array<models.JobInputCheck>>;
+
 export function WriteJobOutputFile(arg1:string,arg2:string,arg3:string):Promise<void>;
@@ -2,10 +2,26 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelBatch(arg1) {
+  return window['go']['main']['App']['CancelBatch'](arg1);
+}
+
+export function CancelJob(arg1) {
+  return window['go']['main']['App']['CancelJob'](arg1);
+}
+
+export function CompareJobs(arg1) {
+  return window['go']['main']['App']['CompareJobs'](arg1);
+}
+
 export function CreateJob(arg1) {
   return window['go']['main']['App']['CreateJob'](arg1);
 }
 
+export function CreateProject(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateProject'](arg1, arg2, arg3);
+}
+
 export function CreatePythonVirtualEnv(arg1, arg2) {
   return window['go']['main']['App']['CreatePythonVirtualEnv'](arg1, arg2);
 }
@@ -22,6 +38,14 @@ export function DeleteJob(arg1) {
   return window['go']['main']['App']['DeleteJob'](arg1);
 }
 
+export function DeleteProject(arg1) {
+  return window['go']['main']['App']['DeleteProject'](arg1);
+}
+
+export function DeleteSampleAnnotations(arg1) {
+  return window['go']['main']['App']['DeleteSampleAnnotations'](arg1);
+}
+
 export function DeleteVirtualEnvironment(arg1) {
   return window['go']['main']['App']['DeleteVirtualEnvironment'](arg1);
 }
@@ -46,10 +70,18 @@ export function DownloadPortableEnvironment(arg1, arg2) {
   return window['go']['main']['App']['DownloadPortableEnvironment'](arg1, arg2);
 }
 
+export function EmptyTrash() {
+  return window['go']['main']['App']['EmptyTrash']();
+}
+
 export function ExecutePlugin(arg1) {
   return window['go']['main']['App']['ExecutePlugin'](arg1);
 }
 
+export function ExecutePluginBatch(arg1) {
+  return window['go']['main']['App']['ExecutePluginBatch'](arg1);
+}
+
 export function ExecutePluginV2(arg1) {
   return window['go']['main']['App']['ExecutePluginV2'](arg1);
 }
@@ -62,6 +94,18 @@ export function ExecuteRScript(arg1, arg2) {
   return window['go']['main']['App']['ExecuteRScript'](arg1, arg2);
 }
 
+export function ExportJobBundle(arg1, arg2) {
+  return window['go']['main']['App']['ExportJobBundle'](arg1, arg2);
+}
+
+export function ExportProject(arg1, arg2) {
+  return window['go']['main']['App']['ExportProject'](arg1, arg2);
+}
+
+export function GetActiveProject() {
+  return window['go']['main']['App']['GetActiveProject']();
+}
+
 export function GetActivePythonEnvironment() {
   return window['go']['main']['App']['GetActivePythonEnvironment']();
 }
@@ -74,6 +118,10 @@ export function GetAllJobs() {
   return window['go']['main']['App']['GetAllJobs']();
 }
 
+export function GetBatch(arg1) {
+  return window['go']['main']['App']['GetBatch'](arg1);
+}
+
 export function GetBundledRequirementsPath(arg1) {
   return window['go']['main']['App']['GetBundledRequirementsPath'](arg1);
 }
@@ -86,10 +134,22 @@ export function GetImportedFiles() {
   return window['go']['main']['App']['GetImportedFiles']();
 }
 
+export function GetInterruptedJobs() {
+  return window['go']['main']['App']['GetInterruptedJobs']();
+}
+
 export function GetJob(arg1) {
   return window['go']['main']['App']['GetJob'](arg1);
 }
 
+export function GetJobArtifacts(arg1) {
+  return window['go']['main']['App']['GetJobArtifacts'](arg1);
+}
+
+export function GetJobLog(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetJobLog'](arg1, arg2, arg3, arg4);
+}
+
 export function GetJobQueueStatus() {
   return window['go']['main']['App']['GetJobQueueStatus']();
 }
@@ -102,6 +162,10 @@ export function GetPlugin(arg1) {
   return window['go']['main']['App']['GetPlugin'](arg1);
 }
 
+export function GetPluginRuntimeHistory(arg1, arg2) {
+  return window['go']['main']['App']['GetPluginRuntimeHistory'](arg1, arg2);
+}
+
 export function GetPluginV2(arg1) {
   return window['go']['main']['App']['GetPluginV2'](arg1);
 }
@@ -130,10 +194,18 @@ export function GetPythonVersion() {
   return window['go']['main']['App']['GetPythonVersion']();
 }
 
+export function GetQueuedJobs() {
+  return window['go']['main']['App']['GetQueuedJobs']();
+}
+
 export function GetRVersion() {
   return window['go']['main']['App']['GetRVersion']();
 }
 
+export function GetSampleAnnotations() {
+  return window['go']['main']['App']['GetSampleAnnotations']();
+}
+
 export function GetSettings() {
   return window['go']['main']['App']['GetSettings']();
 }
@@ -142,6 +214,10 @@ export function GetVirtualEnvironments() {
   return window['go']['main']['App']['GetVirtualEnvironments']();
 }
 
+export function GetWorkers() {
+  return window['go']['main']['App']['GetWorkers']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
@@ -158,6 +234,14 @@ export function ImportDataFile(arg1) {
   return window['go']['main']['App']['ImportDataFile'](arg1);
 }
 
+export function ImportJobBundle(arg1) {
+  return window['go']['main']['App']['ImportJobBundle'](arg1);
+}
+
+export function ImportProject(arg1) {
+  return window['go']['main']['App']['ImportProject'](arg1);
+}
+
 export function InstallPythonPackages(arg1, arg2) {
   return window['go']['main']['App']['InstallPythonPackages'](arg1, arg2);
 }
@@ -170,6 +254,14 @@ export function InstallRPackages(arg1, arg2) {
   return window['go']['main']['App']['InstallRPackages'](arg1, arg2);
 }
 
+export function ListBatches(arg1) {
+  return window['go']['main']['App']['ListBatches'](arg1);
+}
+
+export function ListProjects() {
+  return window['go']['main']['App']['ListProjects']();
+}
+
 export function ListPythonPackages(arg1) {
   return window['go']['main']['App']['ListPythonPackages'](arg1);
 }
@@ -178,6 +270,10 @@ export function ListRPackages(arg1) {
   return window['go']['main']['App']['ListRPackages'](arg1);
 }
 
+export function ListTrash() {
+  return window['go']['main']['App']['ListTrash']();
+}
+
 export function LoadRPackagesFromFile(arg1) {
   return window['go']['main']['App']['LoadRPackagesFromFile'](arg1);
 }
@@ -222,6 +318,18 @@ export function PauseJobQueue() {
   return window['go']['main']['App']['PauseJobQueue']();
 }
 
+export function PreviewCleanup() {
+  return window['go']['main']['App']['PreviewCleanup']();
+}
+
+export function PurgeJob(arg1) {
+  return window['go']['main']['App']['PurgeJob'](arg1);
+}
+
+export function QueryJobs(arg1) {
+  return window['go']['main']['App']['QueryJobs'](arg1);
+}
+
 export function ReExecuteJob(arg1) {
   return window['go']['main']['App']['ReExecuteJob'](arg1);
 }
@@ -238,6 +346,10 @@ export function ReadJobOutputFile(arg1, arg2) {
   return window['go']['main']['App']['ReadJobOutputFile'](arg1, arg2);
 }
 
+export function RecoverJob(arg1, arg2) {
+  return window['go']['main']['App']['RecoverJob'](arg1, arg2);
+}
+
 export function ReloadPlugins() {
   return window['go']['main']['App']['ReloadPlugins']();
 }
@@ -246,14 +358,26 @@ export function ReloadPluginsV2() {
   return window['go']['main']['App']['ReloadPluginsV2']();
 }
 
-export function RerunJob(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['RerunJob'](arg1, arg2, arg3, arg4);
+export function ReorderPendingJobs(arg1) {
+  return window['go']['main']['App']['ReorderPendingJobs'](arg1);
+}
+
+export function RerunJob(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['RerunJob'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function RestoreJob(arg1, arg2) {
+  return window['go']['main']['App']['RestoreJob'](arg1, arg2);
 }
 
 export function ResumeJobQueue() {
   return window['go']['main']['App']['ResumeJobQueue']();
 }
 
+export function RunCleanup(arg1) {
+  return window['go']['main']['App']['RunCleanup'](arg1);
+}
+
 export function RunNormalization(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['RunNormalization'](arg1, arg2, arg3, arg4);
 }
@@ -270,6 +394,18 @@ export function SaveFile(arg1, arg2) {
   return window['go']['main']['App']['SaveFile'](arg1, arg2);
 }
 
+export function SaveSampleAnnotations(arg1) {
+  return window['go']['main']['App']['SaveSampleAnnotations'](arg1);
+}
+
+export function SearchJobText(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SearchJobText'](arg1, arg2, arg3);
+}
+
+export function SetActiveProject(arg1) {
+  return window['go']['main']['App']['SetActiveProject'](arg1);
+}
+
 export function SetActivePythonEnvironment(arg1) {
   return window['go']['main']['App']['SetActivePythonEnvironment'](arg1);
 }
@@ -278,14 +414,34 @@ export function SetActiveREnvironment(arg1) {
   return window['go']['main']['App']['SetActiveREnvironment'](arg1);
 }
 
+export function SetJobPriority(arg1, arg2) {
+  return window['go']['main']['App']['SetJobPriority'](arg1, arg2);
+}
+
+export function SetJobTags(arg1, arg2) {
+  return window['go']['main']['App']['SetJobTags'](arg1, arg2);
+}
+
 export function SetSetting(arg1, arg2) {
   return window['go']['main']['App']['SetSetting'](arg1, arg2);
 }
 
+export function SetWorkerCount(arg1, arg2) {
+  return window['go']['main']['App']['SetWorkerCount'](arg1, arg2);
+}
+
 export function StopJobQueueImmediate() {
   return window['go']['main']['App']['StopJobQueueImmediate']();
 }
 
+export function TestWebhook(arg1) {
+  return window['go']['main']['App']['TestWebhook'](arg1);
+}
+
+export function VerifyJobInputs(arg1) {
+  return window['go']['main']['App']['VerifyJobInputs'](arg1);
+}
+
 export function WriteJobOutputFile(arg1, arg2, arg3) {
   return window['go']['main']['App']['WriteJobOutputFile'](arg1, arg2, arg3);
 }
@@ -1,67 +1,162 @@
 export namespace models {
 	
-	export class Config {
-	    resultStoragePath: string;
-	    outputDirectory: string;
-	    pythonPath: string;
-	    rPath: string;
-	    rLibPath: string;
-	    curtainBackendUrl: string;
+	export class BatchExecutionRequest {
+	    name: string;
+	    pluginId: string;
+	    parameters: Record<string, any>;
+	    fileInput?: string;
+	    files?: string[];
+	    sweep?: Record<string, any[]>;
+	    mode: string;
+	    options: JobOptions;
 	
 	    static createFrom(source: any = {}) {
-	        return new Config(source);
+	        return new BatchExecutionRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.resultStoragePath = source["resultStoragePath"];
-	        this.outputDirectory = source["outputDirectory"];
-	        this.pythonPath = source["pythonPath"];
-	        this.rPath = source["rPath"];
-	        this.rLibPath = source["rLibPath"];
-	        this.curtainBackendUrl = source["curtainBackendUrl"];
+	        this.name = source["name"];
+	        this.pluginId = source["pluginId"];
+	        this.parameters = source["parameters"];
+	        this.fileInput = source["fileInput"];
+	        this.files = source["files"];
+	        this.sweep = source["sweep"];
+	        this.mode = source["mode"];
+	        this.options = this.convertValues(source["options"], JobOptions);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ExampleData {
-	    enabled: boolean;
+	export class BatchRun {
+	    index: number;
+	    jobId: string;
+	    name: string;
+	    status: string;
 	    values: Record<string, any>;
+	    outputDir: string;
+	    outputs: string[];
+	    error?: string;
+	    durationSeconds?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ExampleData(source);
+	        return new BatchRun(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
+	        this.index = source["index"];
+	        this.jobId = source["jobId"];
+	        this.name = source["name"];
+	        this.status = source["status"];
 	        this.values = source["values"];
+	        this.outputDir = source["outputDir"];
+	        this.outputs = source["outputs"];
+	        this.error = source["error"];
+	        this.durationSeconds = source["durationSeconds"];
 	    }
 	}
-	export class FieldOption {
-	    value: string;
-	    label: string;
+	export class BatchSummary {
+	    batch: JobBatch;
+	    status: string;
+	    counts: Record<string, number>;
+	    total: number;
+	    runs: BatchRun[];
+	    indexPath: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new FieldOption(source);
+	        return new BatchSummary(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.value = source["value"];
-	        this.label = source["label"];
+	        this.batch = this.convertValues(source["batch"], JobBatch);
+	        this.status = source["status"];
+	        this.counts = source["counts"];
+	        this.total = source["total"];
+	        this.runs = this.convertValues(source["runs"], BatchRun);
+	        this.indexPath = source["indexPath"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BundleDifference {
+	    kind: string;
+	    name: string;
+	    recorded: string;
+	    local: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BundleDifference(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.name = source["name"];
+	        this.recorded = source["recorded"];
+	        this.local = source["local"];
 	    }
 	}
-	export class FieldGroup {
+	export class CleanupCandidate {
+	    jobId: string;
 	    name: string;
-	    options: FieldOption[];
+	    plugin: string;
+	    status: string;
+	    // Go type: time
+	    createdAt: any;
+	    outputPath?: string;
+	    outputBytes: number;
+	    removesOutput: boolean;
+	    reasons: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new FieldGroup(source);
+	        return new CleanupCandidate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
 	        this.name = source["name"];
-	        this.options = this.convertValues(source["options"], FieldOption);
+	        this.plugin = source["plugin"];
+	        this.status = source["status"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.outputPath = source["outputPath"];
+	        this.outputBytes = source["outputBytes"];
+	        this.removesOutput = source["removesOutput"];
+	        this.reasons = source["reasons"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -82,54 +177,97 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class CleanupPlan {
+	    policy: RetentionPolicy;
+	    candidates: CleanupCandidate[];
+	    outputBytes: number;
+	    freedBytes: number;
 	
-	export class Job {
+	    static createFrom(source: any = {}) {
+	        return new CleanupPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.policy = this.convertValues(source["policy"], RetentionPolicy);
+	        this.candidates = this.convertValues(source["candidates"], CleanupCandidate);
+	        this.outputBytes = source["outputBytes"];
+	        this.freedBytes = source["freedBytes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CleanupSummary {
+	    deletedJobs: number;
+	    deletedDirectories: number;
+	    freedBytes: number;
+	    errors?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CleanupSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.deletedJobs = source["deletedJobs"];
+	        this.deletedDirectories = source["deletedDirectories"];
+	        this.freedBytes = source["freedBytes"];
+	        this.errors = source["errors"];
+	    }
+	}
+	export class ColumnDifference {
+	    name: string;
+	    compared: number;
+	    differing: number;
+	    maxAbsDiff: number;
+	    meanAbsDiff: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ColumnDifference(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.compared = source["compared"];
+	        this.differing = source["differing"];
+	        this.maxAbsDiff = source["maxAbsDiff"];
+	        this.meanAbsDiff = source["meanAbsDiff"];
+	    }
+	}
+	export class ComparedJob {
 	    id: string;
-	    type: string;
 	    name: string;
 	    status: string;
-	    progress: number;
-	    command: string;
-	    args: string[];
-	    parameters: Record<string, any>;
-	    pythonEnvPath?: string;
-	    pythonEnvType?: string;
-	    rEnvPath?: string;
-	    rEnvType?: string;
-	    outputPath: string;
-	    terminalOutput: string[];
 	    // Go type: time
 	    createdAt: any;
-	    // Go type: time
-	    startedAt?: any;
-	    // Go type: time
-	    completedAt?: any;
-	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Job(source);
+	        return new ComparedJob(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.type = source["type"];
 	        this.name = source["name"];
 	        this.status = source["status"];
-	        this.progress = source["progress"];
-	        this.command = source["command"];
-	        this.args = source["args"];
-	        this.parameters = source["parameters"];
-	        this.pythonEnvPath = source["pythonEnvPath"];
-	        this.pythonEnvType = source["pythonEnvType"];
-	        this.rEnvPath = source["rEnvPath"];
-	        this.rEnvType = source["rEnvType"];
-	        this.outputPath = source["outputPath"];
-	        this.terminalOutput = source["terminalOutput"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.startedAt = this.convertValues(source["startedAt"], null);
-	        this.completedAt = this.convertValues(source["completedAt"], null);
-	        this.error = source["error"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -150,54 +288,1213 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class JobRequest {
-	    type: string;
+	export class ComparisonField {
+	    section: string;
 	    name: string;
-	    inputFiles: string[];
-	    parameters: Record<string, any>;
+	    values: string[];
+	    differs: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new JobRequest(source);
+	        return new ComparisonField(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.type = source["type"];
+	        this.section = source["section"];
 	        this.name = source["name"];
-	        this.inputFiles = source["inputFiles"];
-	        this.parameters = source["parameters"];
+	        this.values = source["values"];
+	        this.differs = source["differs"];
 	    }
 	}
-	export class PlotAxes {
-	    x: string;
-	    y: string;
-	    colorBy?: string;
-	    sizeBy?: string;
-	    labels?: string;
+	export class Config {
+	    resultStoragePath: string;
+	    outputDirectory: string;
+	    pythonPath: string;
+	    rPath: string;
+	    rLibPath: string;
+	    curtainBackendUrl: string;
+	    pythonWorkers: number;
+	    rWorkers: number;
+	    directWorkers: number;
+	    retentionKeepPerPlugin: number;
+	    retentionFailedDays: number;
+	    retentionMaxOutputMb: number;
+	    trashRetentionDays: number;
+	    recoveryPolicy: string;
+	    inputSnapshot: string;
+	    desktopNotifications: boolean;
+	    webhookUrls: string[];
+	    webhookSecret: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PlotAxes(source);
+	        return new Config(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.x = source["x"];
-	        this.y = source["y"];
-	        this.colorBy = source["colorBy"];
-	        this.sizeBy = source["sizeBy"];
-	        this.labels = source["labels"];
+	        this.resultStoragePath = source["resultStoragePath"];
+	        this.outputDirectory = source["outputDirectory"];
+	        this.pythonPath = source["pythonPath"];
+	        this.rPath = source["rPath"];
+	        this.rLibPath = source["rLibPath"];
+	        this.curtainBackendUrl = source["curtainBackendUrl"];
+	        this.pythonWorkers = source["pythonWorkers"];
+	        this.rWorkers = source["rWorkers"];
+	        this.directWorkers = source["directWorkers"];
+	        this.retentionKeepPerPlugin = source["retentionKeepPerPlugin"];
+	        this.retentionFailedDays = source["retentionFailedDays"];
+	        this.retentionMaxOutputMb = source["retentionMaxOutputMb"];
+	        this.trashRetentionDays = source["trashRetentionDays"];
+	        this.recoveryPolicy = source["recoveryPolicy"];
+	        this.inputSnapshot = source["inputSnapshot"];
+	        this.desktopNotifications = source["desktopNotifications"];
+	        this.webhookUrls = source["webhookUrls"];
+	        this.webhookSecret = source["webhookSecret"];
+	    }
+	}
+	export class ErrorFrame {
+	    file?: string;
+	    line?: number;
+	    function?: string;
+	    code?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ErrorFrame(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.line = source["line"];
+	        this.function = source["function"];
+	        this.code = source["code"];
+	    }
+	}
+	export class ExampleData {
+	    enabled: boolean;
+	    values: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExampleData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.values = source["values"];
+	    }
+	}
+	export class ExecutionLimits {
+	    timeout?: number;
+	    maxMemoryMB?: number;
+	    maxCPUSeconds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExecutionLimits(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timeout = source["timeout"];
+	        this.maxMemoryMB = source["maxMemoryMB"];
+	        this.maxCPUSeconds = source["maxCPUSeconds"];
+	    }
+	}
+	export class FeatureDifference {
+	    key: string;
+	    maxAbsDiff: number;
+	    differences: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new FeatureDifference(source);
 	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.maxAbsDiff = source["maxAbsDiff"];
+	        this.differences = source["differences"];
+	    }
+	}
+	export class FieldOption {
+	    value: string;
+	    label: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldOption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.value = source["value"];
+	        this.label = source["label"];
+	    }
+	}
+	export class FieldGroup {
+	    name: string;
+	    options: FieldOption[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.options = this.convertValues(source["options"], FieldOption);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class InterruptedJob {
+	    job: Job;
+	    processAlive: boolean;
+	    canReattach: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new InterruptedJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.job = this.convertValues(source["job"], Job);
+	        this.processAlive = source["processAlive"];
+	        this.canReattach = source["canReattach"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Job {
+	    id: string;
+	    type: string;
+	    name: string;
+	    projectId: string;
+	    tags: string[];
+	    status: string;
+	    priority: number;
+	    queuePosition: number;
+	    leaseOwner?: string;
+	    // Go type: time
+	    leaseExpiresAt?: any;
+	    progress: number;
+	    progressMessage?: string;
+	    command: string;
+	    args: string[];
+	    parameters: Record<string, any>;
+	    pythonEnvPath?: string;
+	    pythonEnvType?: string;
+	    rEnvPath?: string;
+	    rEnvType?: string;
+	    limits: ExecutionLimits;
+	    dependsOn: string[];
+	    retry: RetryPolicy;
+	    progressPatterns?: ProgressPattern[];
+	    declaredOutputs?: PluginOutputV2[];
+	    attempt: number;
+	    attempts: JobAttempt[];
+	    // Go type: time
+	    nextAttemptAt?: any;
+	    // Go type: time
+	    runAfter?: any;
+	    schedule?: string;
+	    scheduledFrom?: string;
+	    batchId?: string;
+	    outputPath: string;
+	    inputs: JobInput[];
+	    processId?: number;
+	    processStartTime?: number;
+	    consolePath?: string;
+	    terminalOutput: string[];
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    startedAt?: any;
+	    // Go type: time
+	    completedAt?: any;
+	    durationSeconds?: number;
+	    metrics: ProcessMetrics;
+	    error?: string;
+	    errorDetails: JobError;
+	    warnings?: string[];
+	    trashPath?: string;
+	    // Go type: gorm
+	    deletedAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Job(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.type = source["type"];
+	        this.name = source["name"];
+	        this.projectId = source["projectId"];
+	        this.tags = source["tags"];
+	        this.status = source["status"];
+	        this.priority = source["priority"];
+	        this.queuePosition = source["queuePosition"];
+	        this.leaseOwner = source["leaseOwner"];
+	        this.leaseExpiresAt = this.convertValues(source["leaseExpiresAt"], null);
+	        this.progress = source["progress"];
+	        this.progressMessage = source["progressMessage"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.parameters = source["parameters"];
+	        this.pythonEnvPath = source["pythonEnvPath"];
+	        this.pythonEnvType = source["pythonEnvType"];
+	        this.rEnvPath = source["rEnvPath"];
+	        this.rEnvType = source["rEnvType"];
+	        this.limits = this.convertValues(source["limits"], ExecutionLimits);
+	        this.dependsOn = source["dependsOn"];
+	        this.retry = this.convertValues(source["retry"], RetryPolicy);
+	        this.progressPatterns = this.convertValues(source["progressPatterns"], ProgressPattern);
+	        this.declaredOutputs = this.convertValues(source["declaredOutputs"], PluginOutputV2);
+	        this.attempt = source["attempt"];
+	        this.attempts = this.convertValues(source["attempts"], JobAttempt);
+	        this.nextAttemptAt = this.convertValues(source["nextAttemptAt"], null);
+	        this.runAfter = this.convertValues(source["runAfter"], null);
+	        this.schedule = source["schedule"];
+	        this.scheduledFrom = source["scheduledFrom"];
+	        this.batchId = source["batchId"];
+	        this.outputPath = source["outputPath"];
+	        this.inputs = this.convertValues(source["inputs"], JobInput);
+	        this.processId = source["processId"];
+	        this.processStartTime = source["processStartTime"];
+	        this.consolePath = source["consolePath"];
+	        this.terminalOutput = source["terminalOutput"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.completedAt = this.convertValues(source["completedAt"], null);
+	        this.durationSeconds = source["durationSeconds"];
+	        this.metrics = this.convertValues(source["metrics"], ProcessMetrics);
+	        this.error = source["error"];
+	        this.errorDetails = this.convertValues(source["errorDetails"], JobError);
+	        this.warnings = source["warnings"];
+	        this.trashPath = source["trashPath"];
+	        this.deletedAt = this.convertValues(source["deletedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobArtifact {
+	    id: number;
+	    jobId: string;
+	    path: string;
+	    size: number;
+	    sha256: string;
+	    mimeType: string;
+	    outputName?: string;
+	    // Go type: time
+	    modifiedAt: any;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobArtifact(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.jobId = source["jobId"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.sha256 = source["sha256"];
+	        this.mimeType = source["mimeType"];
+	        this.outputName = source["outputName"];
+	        this.modifiedAt = this.convertValues(source["modifiedAt"], null);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobAttempt {
+	    attempt: number;
+	    status: string;
+	    exitCode?: number;
+	    error?: string;
+	    terminalOutput: string[];
+	    // Go type: time
+	    startedAt?: any;
+	    // Go type: time
+	    finishedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobAttempt(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.attempt = source["attempt"];
+	        this.status = source["status"];
+	        this.exitCode = source["exitCode"];
+	        this.error = source["error"];
+	        this.terminalOutput = source["terminalOutput"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.finishedAt = this.convertValues(source["finishedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobBatch {
+	    id: string;
+	    name: string;
+	    pluginId: string;
+	    projectId: string;
+	    mode: string;
+	    varied: string[];
+	    outputDir: string;
+	    runCount: number;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    finishedAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobBatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.pluginId = source["pluginId"];
+	        this.projectId = source["projectId"];
+	        this.mode = source["mode"];
+	        this.varied = source["varied"];
+	        this.outputDir = source["outputDir"];
+	        this.runCount = source["runCount"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.finishedAt = this.convertValues(source["finishedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobBundleImport {
+	    jobId: string;
+	    originalJobId: string;
+	    outputPath: string;
+	    differences: BundleDifference[];
+	    canRerun: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobBundleImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.originalJobId = source["originalJobId"];
+	        this.outputPath = source["outputPath"];
+	        this.differences = this.convertValues(source["differences"], BundleDifference);
+	        this.canRerun = source["canRerun"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobComparison {
+	    jobs: ComparedJob[];
+	    fields: ComparisonField[];
+	    tables: TableComparison[];
+	
+	    static createFrom(source: any = {}) {
+	        return new JobComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobs = this.convertValues(source["jobs"], ComparedJob);
+	        this.fields = this.convertValues(source["fields"], ComparisonField);
+	        this.tables = this.convertValues(source["tables"], TableComparison);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobError {
+	    language?: string;
+	    category?: string;
+	    type?: string;
+	    message?: string;
+	    frame?: ErrorFrame;
+	    package?: string;
+	    path?: string;
+	    suggestion?: string;
+	    excerpt?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new JobError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.language = source["language"];
+	        this.category = source["category"];
+	        this.type = source["type"];
+	        this.message = source["message"];
+	        this.frame = this.convertValues(source["frame"], ErrorFrame);
+	        this.package = source["package"];
+	        this.path = source["path"];
+	        this.suggestion = source["suggestion"];
+	        this.excerpt = source["excerpt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobInput {
+	    path: string;
+	    size: number;
+	    sha256: string;
+	    snapshotPath?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.sha256 = source["sha256"];
+	        this.snapshotPath = source["snapshotPath"];
+	    }
+	}
+	export class JobInputCheck {
+	    path: string;
+	    size: number;
+	    sha256: string;
+	    snapshotPath?: string;
+	    status: string;
+	    snapshotStatus?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobInputCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.sha256 = source["sha256"];
+	        this.snapshotPath = source["snapshotPath"];
+	        this.status = source["status"];
+	        this.snapshotStatus = source["snapshotStatus"];
+	    }
+	}
+	export class JobLogLine {
+	    id: number;
+	    jobId: string;
+	    seq: number;
+	    attempt: number;
+	    stream: string;
+	    line: string;
+	    // Go type: time
+	    timestamp: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobLogLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.jobId = source["jobId"];
+	        this.seq = source["seq"];
+	        this.attempt = source["attempt"];
+	        this.stream = source["stream"];
+	        this.line = source["line"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobLogPage {
+	    jobId: string;
+	    lines: JobLogLine[];
+	    total: number;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobLogPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.lines = this.convertValues(source["lines"], JobLogLine);
+	        this.total = source["total"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobOptions {
+	    projectId?: string;
+	    tags?: string[];
+	    limits: ExecutionLimits;
+	    dependsOn?: string[];
+	    priority: number;
+	    retry?: RetryPolicy;
+	    progressPatterns?: ProgressPattern[];
+	    declaredOutputs?: PluginOutputV2[];
+	    // Go type: time
+	    runAfter?: any;
+	    schedule?: string;
+	    snapshotInputs?: string;
+	    batchId?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.projectId = source["projectId"];
+	        this.tags = source["tags"];
+	        this.limits = this.convertValues(source["limits"], ExecutionLimits);
+	        this.dependsOn = source["dependsOn"];
+	        this.priority = source["priority"];
+	        this.retry = this.convertValues(source["retry"], RetryPolicy);
+	        this.progressPatterns = this.convertValues(source["progressPatterns"], ProgressPattern);
+	        this.declaredOutputs = this.convertValues(source["declaredOutputs"], PluginOutputV2);
+	        this.runAfter = this.convertValues(source["runAfter"], null);
+	        this.schedule = source["schedule"];
+	        this.snapshotInputs = source["snapshotInputs"];
+	        this.batchId = source["batchId"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobQuery {
+	    statuses?: string[];
+	    pluginId?: string;
+	    envPath?: string;
+	    tag?: string;
+	    search?: string;
+	    projectId?: string;
+	    allProjects?: boolean;
+	    // Go type: time
+	    createdAfter?: any;
+	    // Go type: time
+	    createdBefore?: any;
+	    sortBy?: string;
+	    ascending?: boolean;
+	    limit?: number;
+	    cursor?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statuses = source["statuses"];
+	        this.pluginId = source["pluginId"];
+	        this.envPath = source["envPath"];
+	        this.tag = source["tag"];
+	        this.search = source["search"];
+	        this.projectId = source["projectId"];
+	        this.allProjects = source["allProjects"];
+	        this.createdAfter = this.convertValues(source["createdAfter"], null);
+	        this.createdBefore = this.convertValues(source["createdBefore"], null);
+	        this.sortBy = source["sortBy"];
+	        this.ascending = source["ascending"];
+	        this.limit = source["limit"];
+	        this.cursor = source["cursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobQueryPage {
+	    jobs: Job[];
+	    nextCursor?: string;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobQueryPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobs = this.convertValues(source["jobs"], Job);
+	        this.nextCursor = source["nextCursor"];
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobRequest {
+	    type: string;
+	    name: string;
+	    inputFiles: string[];
+	    parameters: Record<string, any>;
+	    options: JobOptions;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.name = source["name"];
+	        this.inputFiles = source["inputFiles"];
+	        this.parameters = source["parameters"];
+	        this.options = this.convertValues(source["options"], JobOptions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobRuntime {
+	    jobId: string;
+	    name: string;
+	    projectId: string;
+	    status: string;
+	    // Go type: time
+	    createdAt: any;
+	    durationSeconds?: number;
+	    metrics: ProcessMetrics;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobRuntime(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.name = source["name"];
+	        this.projectId = source["projectId"];
+	        this.status = source["status"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.durationSeconds = source["durationSeconds"];
+	        this.metrics = this.convertValues(source["metrics"], ProcessMetrics);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JobSearchResult {
+	    job: Job;
+	    score: number;
+	    field: string;
+	    snippet: SnippetPart[];
+	
+	    static createFrom(source: any = {}) {
+	        return new JobSearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.job = this.convertValues(source["job"], Job);
+	        this.score = source["score"];
+	        this.field = source["field"];
+	        this.snippet = this.convertValues(source["snippet"], SnippetPart);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PlotAxes {
+	    x: string;
+	    y: string;
+	    colorBy?: string;
+	    sizeBy?: string;
+	    labels?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlotAxes(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.colorBy = source["colorBy"];
+	        this.sizeBy = source["sizeBy"];
+	        this.labels = source["labels"];
+	    }
+	}
+	export class PlotConfigData {
+	    axes: PlotAxes;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlotConfigData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.axes = this.convertValues(source["axes"], PlotAxes);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PlotCustomization {
+	    name: string;
+	    label: string;
+	    type: string;
+	    default?: any;
+	    min?: number;
+	    max?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlotCustomization(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.default = source["default"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	    }
+	}
+	export class PluginOutput {
+	    name: string;
+	    description: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PluginOutput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	    }
+	}
+	export class PluginInput {
+	    name: string;
+	    label: string;
+	    type: string;
+	    required: boolean;
+	    default?: any;
+	    options?: string[];
+	    description?: string;
+	    placeholder?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PluginInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.required = source["required"];
+	        this.default = source["default"];
+	        this.options = source["options"];
+	        this.description = source["description"];
+	        this.placeholder = source["placeholder"];
+	    }
+	}
+	export class PluginRuntimeHistory {
+	    pluginId: string;
+	    runs: JobRuntime[];
+	    completed: number;
+	    failed: number;
+	    medianDurationSeconds: number;
+	    maxDurationSeconds: number;
+	    medianCpuSeconds: number;
+	    maxMemoryBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PluginRuntimeHistory(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pluginId = source["pluginId"];
+	        this.runs = this.convertValues(source["runs"], JobRuntime);
+	        this.completed = source["completed"];
+	        this.failed = source["failed"];
+	        this.medianDurationSeconds = source["medianDurationSeconds"];
+	        this.maxDurationSeconds = source["maxDurationSeconds"];
+	        this.medianCpuSeconds = source["medianCpuSeconds"];
+	        this.maxMemoryBytes = source["maxMemoryBytes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PluginScript {
+	    path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PluginScript(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	    }
+	}
+	export class PluginConfig {
+	    name: string;
+	    description: string;
+	    version: string;
+	    author?: string;
+	    runtime: string;
+	    script: PluginScript;
+	    inputs: PluginInput[];
+	    outputs?: PluginOutput[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PluginConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.version = source["version"];
+	        this.author = source["author"];
+	        this.runtime = source["runtime"];
+	        this.script = this.convertValues(source["script"], PluginScript);
+	        this.inputs = this.convertValues(source["inputs"], PluginInput);
+	        this.outputs = this.convertValues(source["outputs"], PluginOutput);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class PlotConfigData {
-	    axes: PlotAxes;
+	export class Plugin {
+	    id: string;
+	    config: PluginConfig;
+	    folderPath: string;
+	    scriptPath: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PlotConfigData(source);
+	        return new Plugin(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.axes = this.convertValues(source["axes"], PlotAxes);
+	        this.id = source["id"];
+	        this.config = this.convertValues(source["config"], PluginConfig);
+	        this.folderPath = source["folderPath"];
+	        this.scriptPath = source["scriptPath"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -218,104 +1515,103 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class PlotCustomization {
-	    name: string;
-	    label: string;
-	    type: string;
-	    default?: any;
-	    min?: number;
-	    max?: number;
+	
+	export class ProcessMetrics {
+	    exitCode?: number;
+	    userCpuSeconds: number;
+	    systemCpuSeconds: number;
+	    maxRssBytes: number;
+	    peakRssBytes: number;
+	    peakProcesses: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new PlotCustomization(source);
+	        return new ProcessMetrics(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.label = source["label"];
-	        this.type = source["type"];
-	        this.default = source["default"];
-	        this.min = source["min"];
-	        this.max = source["max"];
+	        this.exitCode = source["exitCode"];
+	        this.userCpuSeconds = source["userCpuSeconds"];
+	        this.systemCpuSeconds = source["systemCpuSeconds"];
+	        this.maxRssBytes = source["maxRssBytes"];
+	        this.peakRssBytes = source["peakRssBytes"];
+	        this.peakProcesses = source["peakProcesses"];
 	    }
 	}
-	export class PluginOutput {
-	    name: string;
-	    description: string;
+	export class ProgressPattern {
+	    pattern: string;
+	    hide?: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new PluginOutput(source);
+	        return new ProgressPattern(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.description = source["description"];
+	        this.pattern = source["pattern"];
+	        this.hide = source["hide"];
 	    }
 	}
-	export class PluginInput {
+	export class Project {
+	    id: string;
 	    name: string;
-	    label: string;
-	    type: string;
-	    required: boolean;
-	    default?: any;
-	    options?: string[];
 	    description?: string;
-	    placeholder?: string;
+	    outputDirectory: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new PluginInput(source);
+	        return new Project(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.label = source["label"];
-	        this.type = source["type"];
-	        this.required = source["required"];
-	        this.default = source["default"];
-	        this.options = source["options"];
 	        this.description = source["description"];
-	        this.placeholder = source["placeholder"];
-	    }
-	}
-	export class PluginScript {
-	    path: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new PluginScript(source);
+	        this.outputDirectory = source["outputDirectory"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	    }
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class PluginConfig {
-	    name: string;
-	    description: string;
-	    version: string;
-	    author?: string;
-	    runtime: string;
-	    script: PluginScript;
-	    inputs: PluginInput[];
-	    outputs?: PluginOutput[];
+	export class ProjectImport {
+	    project: Project;
+	    jobs: JobBundleImport[];
+	    fileCount: number;
+	    annotationCount: number;
+	    missingFiles: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new PluginConfig(source);
+	        return new ProjectImport(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.version = source["version"];
-	        this.author = source["author"];
-	        this.runtime = source["runtime"];
-	        this.script = this.convertValues(source["script"], PluginScript);
-	        this.inputs = this.convertValues(source["inputs"], PluginInput);
-	        this.outputs = this.convertValues(source["outputs"], PluginOutput);
+	        this.project = this.convertValues(source["project"], Project);
+	        this.jobs = this.convertValues(source["jobs"], JobBundleImport);
+	        this.fileCount = source["fileCount"];
+	        this.annotationCount = source["annotationCount"];
+	        this.missingFiles = source["missingFiles"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -336,22 +1632,24 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class Plugin {
-	    id: string;
-	    config: PluginConfig;
-	    folderPath: string;
-	    scriptPath: string;
+	export class ProjectSummary {
+	    project: Project;
+	    jobCount: number;
+	    fileCount: number;
+	    annotationCount: number;
+	    active: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new Plugin(source);
+	        return new ProjectSummary(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.config = this.convertValues(source["config"], PluginConfig);
-	        this.folderPath = source["folderPath"];
-	        this.scriptPath = source["scriptPath"];
+	        this.project = this.convertValues(source["project"], Project);
+	        this.jobCount = source["jobCount"];
+	        this.fileCount = source["fileCount"];
+	        this.annotationCount = source["annotationCount"];
+	        this.active = source["active"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -372,7 +1670,6 @@ export namespace models {
 		    return a;
 		}
 	}
-	
 	export class Requirements {
 	    python?: string;
 	    r?: string;
@@ -393,6 +1690,9 @@ export namespace models {
 	    argsMapping: Record<string, any>;
 	    outputDir: string;
 	    requirements?: Requirements;
+	    limits?: ExecutionLimits;
+	    retry?: RetryPolicy;
+	    progress?: ProgressPattern[];
 	
 	    static createFrom(source: any = {}) {
 	        return new PluginExecution(source);
@@ -403,6 +1703,9 @@ export namespace models {
 	        this.argsMapping = source["argsMapping"];
 	        this.outputDir = source["outputDir"];
 	        this.requirements = this.convertValues(source["requirements"], Requirements);
+	        this.limits = this.convertValues(source["limits"], ExecutionLimits);
+	        this.retry = this.convertValues(source["retry"], RetryPolicy);
+	        this.progress = this.convertValues(source["progress"], ProgressPattern);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -471,6 +1774,7 @@ export namespace models {
 	    type: string;
 	    description: string;
 	    format: string;
+	    required?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new PluginOutputV2(source);
@@ -483,7 +1787,138 @@ export namespace models {
 	        this.type = source["type"];
 	        this.description = source["description"];
 	        this.format = source["format"];
+	        this.required = source["required"];
+	    }
+	}
+	export class RetentionPolicy {
+	    keepPerPlugin: number;
+	    failedAfterDays: number;
+	    maxOutputMb: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetentionPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.keepPerPlugin = source["keepPerPlugin"];
+	        this.failedAfterDays = source["failedAfterDays"];
+	        this.maxOutputMb = source["maxOutputMb"];
+	    }
+	}
+	export class RetryPolicy {
+	    maxAttempts: number;
+	    backoff?: number;
+	    backoffFactor?: number;
+	    maxBackoff?: number;
+	    retryOnExitCodes?: number[];
+	    retryOnPatterns?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RetryPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxAttempts = source["maxAttempts"];
+	        this.backoff = source["backoff"];
+	        this.backoffFactor = source["backoffFactor"];
+	        this.maxBackoff = source["maxBackoff"];
+	        this.retryOnExitCodes = source["retryOnExitCodes"];
+	        this.retryOnPatterns = source["retryOnPatterns"];
+	    }
+	}
+	export class SampleAnnotation {
+	    sample: string;
+	    condition: string;
+	    batch?: string;
+	    color?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SampleAnnotation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sample = source["sample"];
+	        this.condition = source["condition"];
+	        this.batch = source["batch"];
+	        this.color = source["color"];
+	    }
+	}
+	export class SnippetPart {
+	    text: string;
+	    match?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SnippetPart(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.match = source["match"];
+	    }
+	}
+	export class TableComparison {
+	    name: string;
+	    referenceJobId: string;
+	    jobId: string;
+	    keyColumn: string;
+	    referenceRows: number;
+	    rows: number;
+	    sharedRows: number;
+	    onlyReferenceRows: number;
+	    onlyRows: number;
+	    repeatedKeys: number;
+	    sharedColumns: string[];
+	    onlyReferenceColumns: string[];
+	    onlyColumns: string[];
+	    columns: ColumnDifference[];
+	    features: FeatureDifference[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TableComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.referenceJobId = source["referenceJobId"];
+	        this.jobId = source["jobId"];
+	        this.keyColumn = source["keyColumn"];
+	        this.referenceRows = source["referenceRows"];
+	        this.rows = source["rows"];
+	        this.sharedRows = source["sharedRows"];
+	        this.onlyReferenceRows = source["onlyReferenceRows"];
+	        this.onlyRows = source["onlyRows"];
+	        this.repeatedKeys = source["repeatedKeys"];
+	        this.sharedColumns = source["sharedColumns"];
+	        this.onlyReferenceColumns = source["onlyReferenceColumns"];
+	        this.onlyColumns = source["onlyColumns"];
+	        this.columns = this.convertValues(source["columns"], ColumnDifference);
+	        this.features = this.convertValues(source["features"], FeatureDifference);
+	        this.error = source["error"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class VisibilityCondition {
 	    field: string;
@@ -718,6 +2153,106 @@ export namespace models {
 	}
 	
 
+	export class WebhookPayload {
+	    event: string;
+	    jobId?: string;
+	    batchId?: string;
+	    name: string;
+	    plugin: string;
+	    status: string;
+	    durationSeconds?: number;
+	    outputPath: string;
+	    error?: string;
+	    total?: number;
+	    counts?: Record<string, number>;
+	    // Go type: time
+	    finishedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebhookPayload(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.event = source["event"];
+	        this.jobId = source["jobId"];
+	        this.batchId = source["batchId"];
+	        this.name = source["name"];
+	        this.plugin = source["plugin"];
+	        this.status = source["status"];
+	        this.durationSeconds = source["durationSeconds"];
+	        this.outputPath = source["outputPath"];
+	        this.error = source["error"];
+	        this.total = source["total"];
+	        this.counts = source["counts"];
+	        this.finishedAt = this.convertValues(source["finishedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorkerInfo {
+	    id: string;
+	    runtime: string;
+	    busy: boolean;
+	    retiring: boolean;
+	    jobId?: string;
+	    jobName?: string;
+	    pid?: number;
+	    // Go type: time
+	    startedAt?: any;
+	    runningSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkerInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.runtime = source["runtime"];
+	        this.busy = source["busy"];
+	        this.retiring = source["retiring"];
+	        this.jobId = source["jobId"];
+	        this.jobName = source["jobName"];
+	        this.pid = source["pid"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.runningSeconds = source["runningSeconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 }
 
 export namespace services {
@@ -742,6 +2277,7 @@ export namespace services {
 	}
 	export class ImportedFile {
 	    ID: number;
+	    ProjectID: string;
 	    Name: string;
 	    Path: string;
 	    Size: number;
@@ -756,6 +2292,7 @@ export namespace services {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.ID = source["ID"];
+	        this.ProjectID = source["ProjectID"];
 	        this.Name = source["Name"];
 	        this.Path = source["Path"];
 	        this.Size = source["Size"];
@@ -6,8 +6,11 @@ import pandas as pd
 from sklearn.experimental import enable_iterative_imputer
 from sklearn.impute import IterativeImputer, KNNImputer, SimpleImputer
 
+from cauldron_progress import report_progress
+
 
 def impute(file_path: str, output_folder: str, columns_name: str, imputer_type: str = "knn", n_neighbors: int = 5, max_iter: int = 10, simple_strategy: str = "mean", fill_value: float = 0.0):
+    report_progress(5, "Loading data")
     if file_path.endswith(".tsv") or file_path.endswith(".txt"):
         df = pd.read_csv(file_path, sep="\t")
     elif file_path.endswith(".csv"):
@@ -29,8 +32,10 @@ def impute(file_path: str, output_folder: str, columns_name: str, imputer_type:
         imputer = IterativeImputer(max_iter=max_iter)
     else:
         raise ValueError("Invalid imputer type")
+    report_progress(20, f"Imputing {len(columns)} columns with {imputer_type}")
     sample_df = pd.DataFrame(imputer.fit_transform(sample_df), columns=columns)
     df[columns] = sample_df[columns]
+    report_progress(95, "Writing results")
     os.makedirs(output_folder, exist_ok=True)
     df.to_csv(os.path.join(output_folder, "imputed.data.txt"), sep="\t", index=False)
 
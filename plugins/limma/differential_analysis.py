@@ -2,6 +2,8 @@ import pandas as pd
 import click
 import os
 
+from cauldron_progress import report_progress
+
 
 def set_up_R_HOME(value: str):
     os.environ["R_HOME"] = value
@@ -13,6 +15,7 @@ def diff_analysis(input_file: str, output_folder: str, annotation_file: str, com
                   normalize: str = "quantiles.robust"):
     from coral.data import Coral
     from coral.utility import detect_delimiter_from_extension
+    report_progress(5, "Loading data")
     coral = Coral()
     coral.load_unproccessed_file(input_file, sep=detect_delimiter_from_extension(input_file))
     annotation_df = pd.read_csv(annotation_file, sep=detect_delimiter_from_extension(annotation_file))
@@ -33,24 +36,29 @@ def diff_analysis(input_file: str, output_folder: str, annotation_file: str, com
     print(coral.unprocessed_df.isnull().sum() / len(coral.unprocessed_df))
 
 
+    report_progress(20, "Filtering missing values")
     coral.filter_missing_columns(col_filter)
     coral.prepare()
     coral.filter_missing_rows(row_filter)
     #if impute != "knn":
     if impute != "":
+        report_progress(35, f"Imputing with {impute}")
         coral.impute(impute)
         coral.export_df_from_R(os.path.join(output_folder, "imputed.txt").replace("\\", "/"))
     if log2:
         coral.log_transform()
     if aggregate_column:
+        report_progress(50, f"Aggregating by {aggregate_column}")
         coral.aggregate_features(aggregate_column, aggregate_method)
         coral.export_df_from_R(os.path.join(output_folder, "aggregated.txt").replace("\\", "/"))
     if normalize:
+        report_progress(60, f"Normalizing with {normalize}")
         coral.normalize(normalize)
         coral.export_df_from_R(os.path.join(output_folder, "normalized.txt").replace("\\", "/"))
     #if impute == "knn":
     #    coral.impute(impute)
     #    coral.export_df_from_R(os.path.join(output_folder, "imputed.txt").replace("\\", "/"))
+    report_progress(70, "Running limma")
     coral.prepare_for_limma()
     result = []
     for d in coral.run_limma():
@@ -59,6 +67,7 @@ def diff_analysis(input_file: str, output_folder: str, annotation_file: str, com
         result = pd.concat(result)
     else:
         result = result[0]
+    report_progress(95, "Writing results")
     os.makedirs(output_folder, exist_ok=True)
     result.to_csv(os.path.join(output_folder, "differential_analysis.txt"), sep="\t", index=False)
 
@@ -0,0 +1,28 @@
+from typing import Optional
+
+
+def report_progress(progress: Optional[float] = None, message: Optional[str] = None):
+    """Print a ##cauldron progress line for the job queue.
+
+    Cauldron sets the job's progress bar and status message from these lines
+    and keeps them out of the job log.
+    """
+    fields = []
+    if progress is not None:
+        fields.append(f"progress={max(0.0, min(100.0, float(progress))):g}")
+    if message is not None:
+        escaped = str(message).replace("\\", "\\\\").replace('"', '\\"')
+        fields.append(f'message="{escaped}"')
+    if not fields:
+        return
+
+    print("##cauldron " + " ".join(fields), flush=True)
+
+
+def report_step(current: int, total: int, message: Optional[str] = None):
+    """Report progress as step current of total."""
+    if total <= 0:
+        report_progress(message=message)
+        return
+    report_progress(current / total * 100, message)
+